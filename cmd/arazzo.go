@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/speakeasy-api/speakeasy/internal/arazzo"
+	charm_internal "github.com/speakeasy-api/speakeasy/internal/charm"
+	"github.com/speakeasy-api/speakeasy/internal/log"
+	"github.com/speakeasy-api/speakeasy/internal/model"
+	"github.com/speakeasy-api/speakeasy/internal/model/flag"
+	"github.com/speakeasy-api/speakeasy/internal/utils"
+	"gopkg.in/yaml.v3"
+)
+
+const arazzoLong = "# Arazzo \n The `arazzo` command provides a set of commands for working with Arazzo workflow documents."
+
+var arazzoCmd = &model.CommandGroup{
+	Usage:          "arazzo",
+	Short:          "Work with Arazzo workflow documents",
+	Long:           utils.RenderMarkdown(arazzoLong),
+	InteractiveMsg: "What do you want to do?",
+	Commands:       []model.Command{arazzoRunCmd, arazzoScaffoldCmd},
+}
+
+const arazzoRunLong = `# Arazzo Run
+
+Runs the workflows of an Arazzo document against an API, step by step.
+
+Each step calls the operation its ` + "`operationId`" + ` or ` + "`operationPath`" + ` refers to in the OpenAPI source descriptions of the document, or another workflow. Runtime expressions in parameters and request bodies are evaluated, success criteria are checked against the response, and outputs are passed on to later steps.
+
+Requests are sent to ` + "`--base-url`" + `, or to the first server of each operation's OpenAPI document. Source descriptions that can't be loaded from their URL, like those of generated test workflows, can be pointed at a local OpenAPI document with ` + "`--schema`" + ` or, per source, ` + "`--source`" + `.
+
+The command fails if any workflow fails. Use ` + "`--report`" + ` to write a JUnit or JSON report for CI.`
+
+type arazzoRunFlags struct {
+	File         string            `json:"file"`
+	BaseURL      string            `json:"base-url"`
+	Workflows    []string          `json:"workflow"`
+	Schema       string            `json:"schema"`
+	Sources      map[string]string `json:"source"`
+	Inputs       map[string]string `json:"input"`
+	Headers      []string          `json:"header"`
+	Report       string            `json:"report"`
+	ReportFormat string            `json:"report-format"`
+	Timeout      int               `json:"timeout"`
+}
+
+var arazzoRunCmd = &model.ExecutableCommand[arazzoRunFlags]{
+	Usage: "run",
+	Short: "Run the workflows of an Arazzo document against an API",
+	Long:  utils.RenderMarkdown(arazzoRunLong),
+	Run:   runArazzo,
+	Flags: []flag.Flag{
+		flag.StringFlag{
+			Name:         "file",
+			Shorthand:    "f",
+			Description:  "path to the Arazzo document",
+			DefaultValue: "arazzo.yaml",
+		},
+		flag.StringFlag{
+			Name:        "base-url",
+			Description: "the server to send requests to, defaults to the servers of the OpenAPI source descriptions",
+		},
+		flag.StringSliceFlag{
+			Name:        "workflow",
+			Shorthand:   "w",
+			Description: "IDs of the workflows to run, defaults to all workflows",
+		},
+		flag.StringFlag{
+			Name:        "schema",
+			Shorthand:   "s",
+			Description: "path or URL of the OpenAPI document to use for every OpenAPI source description",
+		},
+		flag.MapFlag{
+			Name:        "source",
+			Description: "a map from source description name to the path or URL of its OpenAPI document",
+		},
+		flag.MapFlag{
+			Name:        "input",
+			Description: "a map of workflow inputs, values are parsed as YAML",
+		},
+		flag.StringSliceFlag{
+			Name:        "header",
+			Shorthand:   "H",
+			Description: "headers to send with every request, as \"Name: value\"",
+		},
+		flag.StringFlag{
+			Name:        "report",
+			Description: "path to write a report of the run to",
+		},
+		flag.EnumFlag{
+			Name:          "report-format",
+			Description:   "format of the report, auto infers it from the extension of --report",
+			AllowedValues: []string{string(arazzo.ReportFormatAuto), string(arazzo.ReportFormatJUnit), string(arazzo.ReportFormatJSON)},
+			DefaultValue:  string(arazzo.ReportFormatAuto),
+		},
+		flag.IntFlag{
+			Name:         "timeout",
+			Description:  "seconds to wait for each request before failing its step",
+			DefaultValue: int(arazzo.DefaultRequestTimeout / time.Second),
+		},
+	},
+}
+
+func runArazzo(ctx context.Context, flags arazzoRunFlags) error {
+	headers := http.Header{}
+	for _, header := range flags.Headers {
+		name, value, ok := strings.Cut(header, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("invalid header %q: expected \"Name: value\"", header)
+		}
+		headers.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	inputs := map[string]any{}
+	for name, raw := range flags.Inputs {
+		var value any
+		if err := yaml.Unmarshal([]byte(raw), &value); err != nil {
+			value = raw
+		}
+		inputs[name] = value
+	}
+
+	opts := arazzo.RunOptions{
+		File:        flags.File,
+		BaseURL:     flags.BaseURL,
+		WorkflowIDs: flags.Workflows,
+		Schema:      flags.Schema,
+		Sources:     flags.Sources,
+		Inputs:      inputs,
+		Headers:     headers,
+		Timeout:     time.Duration(flags.Timeout) * time.Second,
+	}
+
+	return arazzo.Run(ctx, opts, flags.Report, arazzo.ReportFormat(flags.ReportFormat))
+}
+
+const arazzoScaffoldLong = `# Arazzo Scaffold
+
+Generates an Arazzo document with workflows derived from an OpenAPI document, as a starting point for testing an API.
+
+Each resource that can be created and then fetched, updated or deleted by id gets a workflow chaining its create, get, update, list and delete operations, linked by the id in the create response. Every other operation gets a single step workflow. Request bodies and parameters are filled in from the examples, defaults and types of their schemas, and each step checks for the operation's success status code.
+
+The generated document is written to ` + "`--out`" + `, or to stdout, and can be edited and then run with ` + "`speakeasy arazzo run`" + `.`
+
+type arazzoScaffoldFlags struct {
+	Schema    string `json:"schema"`
+	Out       string `json:"out"`
+	SourceURL string `json:"source-url"`
+}
+
+var arazzoScaffoldCmd = &model.ExecutableCommand[arazzoScaffoldFlags]{
+	Usage: "scaffold",
+	Short: "Generate Arazzo workflows from an OpenAPI document",
+	Long:  utils.RenderMarkdown(arazzoScaffoldLong),
+	Run:   scaffoldArazzo,
+	Flags: []flag.Flag{
+		flag.StringFlag{
+			Name:                       "schema",
+			Shorthand:                  "s",
+			Description:                "path to the OpenAPI document",
+			Required:                   true,
+			AutocompleteFileExtensions: charm_internal.OpenAPIFileExtensions,
+		},
+		flag.StringFlag{
+			Name:        "out",
+			Shorthand:   "o",
+			Description: "path to write the Arazzo document to, defaults to stdout",
+		},
+		flag.StringFlag{
+			Name:        "source-url",
+			Description: "the URL of the OpenAPI document in the generated source description, defaults to the path of --schema relative to --out",
+		},
+	},
+}
+
+func scaffoldArazzo(ctx context.Context, flags arazzoScaffoldFlags) error {
+	sourceURL := flags.SourceURL
+	if sourceURL == "" {
+		sourceURL = flags.Schema
+		if flags.Out != "" && !strings.HasPrefix(flags.Schema, "http://") && !strings.HasPrefix(flags.Schema, "https://") {
+			if rel, err := relativePath(filepath.Dir(flags.Out), flags.Schema); err == nil {
+				sourceURL = rel
+			}
+		}
+	}
+
+	if err := arazzo.WriteScaffold(ctx, flags.Schema, sourceURL, flags.Out); err != nil {
+		return err
+	}
+
+	if flags.Out != "" {
+		log.From(ctx).Successf("Arazzo workflows written to %s", flags.Out)
+	}
+	return nil
+}
+
+func relativePath(dir, path string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(absDir, absPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
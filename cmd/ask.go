@@ -13,11 +13,12 @@ type AskFlags struct {
 }
 
 var AskCmd = &model.ExecutableCommand[AskFlags]{
-	Usage:        "ask",
-	Short:        "Starts a conversation with Speakeasy trained AI",
-	Long:         "Starts a conversation with Speakeasy trained AI. Ask about OpenAPI, Speakeasy, configuring SDKs, or anything else you need help with.",
-	Run:          AskFunc,
-	RequiresAuth: false,
+	Usage:           "ask",
+	Short:           "Starts a conversation with Speakeasy trained AI",
+	Long:            "Starts a conversation with Speakeasy trained AI. Ask about OpenAPI, Speakeasy, configuring SDKs, or anything else you need help with.",
+	Run:             AskFunc,
+	RequiresAuth:    false,
+	RequiresNetwork: true,
 	Flags: []flag.Flag{
 		flag.StringFlag{
 			Name:        "message",
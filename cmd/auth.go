@@ -11,6 +11,7 @@ import (
 	"github.com/speakeasy-api/speakeasy/internal/config"
 	"github.com/speakeasy-api/speakeasy/internal/interactivity"
 	"github.com/speakeasy-api/speakeasy/internal/log"
+	"github.com/speakeasy-api/speakeasy/internal/offline"
 	"github.com/spf13/cobra"
 )
 
@@ -58,6 +59,9 @@ func authInit() {
 }
 
 func loginExec(cmd *cobra.Command, args []string) error {
+	if err := offline.RequireNetwork("speakeasy auth login"); err != nil {
+		return err
+	}
 	return login(cmd, true)
 }
 
@@ -127,6 +131,10 @@ func login(cmd *cobra.Command, force bool) error {
 }
 
 func authStatusExec(cmd *cobra.Command, args []string) error {
+	if err := offline.RequireNetwork("speakeasy auth status"); err != nil {
+		return err
+	}
+
 	ctx := cmd.Context()
 	logger := log.From(ctx)
 
@@ -27,11 +27,12 @@ var billingCmd = &model.CommandGroup{
 }
 
 var activateCmd = &model.ExecutableCommand[BillingFlags]{
-	Usage:        "activate",
-	Short:        "Activate a paid feature",
-	Long:         `Activate a paid feature in your Speakeasy workspace.`,
-	Run:          activateExec,
-	RequiresAuth: true,
+	Usage:           "activate",
+	Short:           "Activate a paid feature",
+	Long:            `Activate a paid feature in your Speakeasy workspace.`,
+	Run:             activateExec,
+	RequiresAuth:    true,
+	RequiresNetwork: true,
 	Flags: []flag.Flag{
 		flag.EnumFlag{
 			Name:        "feature",
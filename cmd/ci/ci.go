@@ -19,8 +19,10 @@ var CICmd = &model.CommandGroup{
 		finalizeCmd,
 		prDescriptionCmd,
 		createOrUpdatePRCmd,
+		fanoutPlanCmd,
 		fanoutFinalizeCmd,
 		publishEventCmd,
+		verifyPublishCmd,
 
 		tagCmd,
 		ciTestCmd,
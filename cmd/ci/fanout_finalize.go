@@ -18,6 +18,7 @@ type fanoutFinalizeFlags struct {
 	CleanupPaths       string `json:"cleanup-paths"`
 	PostGenerateScript string `json:"post-generate-script"`
 	CommitMessage      string `json:"commit-message"`
+	MergeStrategies    string `json:"merge-strategies"`
 	CleanupWorkers     bool   `json:"cleanup-workers"`
 	CreateRelease      bool   `json:"create-release"`
 	CommentTestResults bool   `json:"comment-test-results"`
@@ -28,7 +29,10 @@ var fanoutFinalizeCmd = &model.ExecutableCommand[fanoutFinalizeFlags]{
 	Short: "Finalize parallel generation by aggregating worker commits into one PR branch",
 	Long: `Cherry-picks commits from per-target worker branches onto a base branch, aggregates
 changelog/report data, removes ephemeral changelog artifacts, squashes to one commit,
-force-pushes the PR branch, and creates or updates the PR.`,
+force-pushes the PR branch, and creates or updates the PR.
+
+Conflicts between workers in workflow.lock, gen.lock and RELEASES.md are merged
+structurally; conflicts in any other file fail finalization.`,
 	Run: runFanoutFinalize,
 	Flags: []flag.Flag{
 		flag.StringFlag{
@@ -72,6 +76,11 @@ force-pushes the PR branch, and creates or updates the PR.`,
 			Description:  "Squashed commit message override",
 			DefaultValue: os.Getenv("INPUT_COMMIT_MESSAGE"),
 		},
+		flag.StringFlag{
+			Name:         "merge-strategies",
+			Description:  "Comma/newline-separated pattern=strategy rules (lockfile, releases, ours, theirs, fail) for resolving conflicts between workers; lockfiles and RELEASES.md are merged by default",
+			DefaultValue: os.Getenv("INPUT_MERGE_STRATEGIES"),
+		},
 		flag.BooleanFlag{
 			Name:         "cleanup-workers",
 			Description:  "Delete worker branches after successful finalization",
@@ -99,6 +108,7 @@ func runFanoutFinalize(ctx context.Context, flags fanoutFinalizeFlags) error {
 	setEnvIfNotEmpty("INPUT_CLEANUP_PATHS", flags.CleanupPaths)
 	setEnvIfNotEmpty("INPUT_POST_GENERATE_SCRIPT", flags.PostGenerateScript)
 	setEnvIfNotEmpty("INPUT_COMMIT_MESSAGE", flags.CommitMessage)
+	setEnvIfNotEmpty("INPUT_MERGE_STRATEGIES", flags.MergeStrategies)
 	setEnvBool("INPUT_CLEANUP_WORKERS", flags.CleanupWorkers)
 	setEnvBool("INPUT_CREATE_RELEASE", flags.CreateRelease)
 	setEnvBool("INPUT_COMMENT_TEST_RESULTS", flags.CommentTestResults)
@@ -111,6 +121,7 @@ func runFanoutFinalize(ctx context.Context, flags fanoutFinalizeFlags) error {
 		CleanupPaths:       flags.CleanupPaths,
 		PostGenerateScript: flags.PostGenerateScript,
 		CommitMessage:      flags.CommitMessage,
+		MergeStrategies:    flags.MergeStrategies,
 		CleanupWorkers:     flags.CleanupWorkers,
 		CreateRelease:      flags.CreateRelease,
 		CommentTestResults: flags.CommentTestResults,
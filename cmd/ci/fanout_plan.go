@@ -0,0 +1,70 @@
+package ci
+
+import (
+	"context"
+	"os"
+
+	"github.com/speakeasy-api/speakeasy/internal/ci/actions"
+	"github.com/speakeasy-api/speakeasy/internal/model"
+	"github.com/speakeasy-api/speakeasy/internal/model/flag"
+)
+
+type fanoutPlanFlags struct {
+	BaseBranch string `json:"base-branch"`
+	Targets    string `json:"targets"`
+	Format     string `json:"format"`
+	OutputFile string `json:"output-file"`
+	Force      bool   `json:"force"`
+}
+
+var fanoutPlanCmd = &model.ExecutableCommand[fanoutPlanFlags]{
+	Usage: "fanout-plan",
+	Short: "Plan parallel generation by emitting a per-target worker matrix",
+	Long: `Reads workflow.yaml and workflow.lock, determines which targets need regeneration
+(changed source revisions, local input changes or configuration changes), orders them by
+estimated generation cost and emits a CI matrix with deterministic worker branch names.
+
+The worker branches are exposed as the worker_branches output and can be passed
+directly to fanout-finalize.`,
+	Run: runFanoutPlan,
+	Flags: []flag.Flag{
+		flag.StringFlag{
+			Name:         "base-branch",
+			Description:  "Base branch the workflow is running from",
+			DefaultValue: os.Getenv("INPUT_BASE_BRANCH"),
+		},
+		flag.StringFlag{
+			Name:         "targets",
+			Description:  "Comma/newline-separated targets to consider (defaults to all workflow targets)",
+			DefaultValue: os.Getenv("INPUT_TARGETS"),
+		},
+		flag.EnumFlag{
+			Name:          "format",
+			Description:   "Matrix format to emit",
+			DefaultValue:  "github",
+			AllowedValues: []string{"github", "gitlab"},
+		},
+		flag.StringFlag{
+			Name:         "output-file",
+			Description:  "Optional path to write the full plan (workers, reasons, skipped targets) as JSON",
+			DefaultValue: os.Getenv("INPUT_OUTPUT_FILE"),
+		},
+		flag.BooleanFlag{
+			Name:         "force",
+			Description:  "Plan every target regardless of detected changes",
+			DefaultValue: os.Getenv("INPUT_FORCE") == "true",
+		},
+	},
+}
+
+func runFanoutPlan(ctx context.Context, flags fanoutPlanFlags) error {
+	setEnvIfNotEmpty("INPUT_BASE_BRANCH", flags.BaseBranch)
+
+	return actions.FanoutPlan(ctx, actions.FanoutPlanInputs{
+		BaseBranch: flags.BaseBranch,
+		Targets:    flags.Targets,
+		Format:     flags.Format,
+		OutputFile: flags.OutputFile,
+		Force:      flags.Force,
+	})
+}
@@ -1,6 +1,9 @@
 package ci
 
-import "os"
+import (
+	"os"
+	"strconv"
+)
 
 // setEnvIfNotEmpty sets an environment variable only if the provided value is non-empty.
 // This is used to bridge CLI flags to environment variables. When a flag has a value
@@ -23,3 +26,12 @@ func setEnvBool(key string, value bool) {
 		_ = os.Setenv(key, "false")
 	}
 }
+
+// envIntOrDefault returns the integer value of an environment variable, or
+// fallback if it is unset or not an integer.
+func envIntOrDefault(key string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return fallback
+}
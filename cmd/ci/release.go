@@ -10,19 +10,26 @@ import (
 )
 
 type releaseFlags struct {
-	GithubAccessToken  string `json:"github-access-token"`
-	Target             string `json:"target"`
-	WorkingDirectory   string `json:"working-directory"`
-	Debug              bool   `json:"debug"`
-	RegistryTags       string `json:"registry-tags"`
-	EnableSDKChangelog string `json:"enable-sdk-changelog"`
+	GithubAccessToken     string `json:"github-access-token"`
+	Target                string `json:"target"`
+	WorkingDirectory      string `json:"working-directory"`
+	Debug                 bool   `json:"debug"`
+	RegistryTags          string `json:"registry-tags"`
+	EnableSDKChangelog    string `json:"enable-sdk-changelog"`
+	ReleaseNotesTemplates string `json:"release-notes-templates"`
+	Preview               bool   `json:"preview"`
 }
 
 var releaseCmd = &model.ExecutableCommand[releaseFlags]{
 	Usage: "release",
 	Short: "Create GitHub releases for generated SDKs (used by CI/CD)",
-	Long:  "Creates GitHub releases based on generation output. Used by CI/CD after SDK generation.",
-	Run:   runRelease,
+	Long: `Creates GitHub releases based on generation output. Used by CI/CD after SDK generation.
+
+Release notes can be customized with Go text/template files in the directory given by
+--release-notes-templates: release.md.tmpl renders the combined release body, and
+<language>.md.tmpl (or target.md.tmpl as a fallback) renders per-target release bodies.
+Use --preview to render release notes locally without creating any releases.`,
+	Run: runRelease,
 	Flags: []flag.Flag{
 		flag.StringFlag{
 			Name:         "github-access-token",
@@ -54,6 +61,15 @@ var releaseCmd = &model.ExecutableCommand[releaseFlags]{
 			Description:  "Enable SDK changelog generation",
 			DefaultValue: os.Getenv("INPUT_ENABLE_SDK_CHANGELOG"),
 		},
+		flag.StringFlag{
+			Name:         "release-notes-templates",
+			Description:  "Directory containing release notes templates (release.md.tmpl, target.md.tmpl, <language>.md.tmpl)",
+			DefaultValue: os.Getenv("INPUT_RELEASE_NOTES_TEMPLATES"),
+		},
+		flag.BooleanFlag{
+			Name:        "preview",
+			Description: "Render release notes locally without creating releases",
+		},
 	},
 }
 
@@ -64,6 +80,7 @@ func runRelease(ctx context.Context, flags releaseFlags) error {
 	setEnvBool("INPUT_DEBUG", flags.Debug)
 	setEnvIfNotEmpty("INPUT_REGISTRY_TAGS", flags.RegistryTags)
 	setEnvIfNotEmpty("INPUT_ENABLE_SDK_CHANGELOG", flags.EnableSDKChangelog)
+	setEnvIfNotEmpty("INPUT_RELEASE_NOTES_TEMPLATES", flags.ReleaseNotesTemplates)
 
-	return actions.Release(ctx)
+	return actions.Release(ctx, actions.ReleaseInputs{Preview: flags.Preview})
 }
@@ -2,11 +2,15 @@ package ci
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"slices"
+	"strings"
 
 	"github.com/speakeasy-api/speakeasy/internal/ci/actions"
 	"github.com/speakeasy-api/speakeasy/internal/model"
 	"github.com/speakeasy-api/speakeasy/internal/model/flag"
+	"github.com/speakeasy-api/speakeasy/internal/validation"
 )
 
 type validateFlags struct {
@@ -16,12 +20,14 @@ type validateFlags struct {
 	MaxValidationWarnings int      `json:"max-validation-warnings"`
 	Ruleset               string   `json:"ruleset"`
 	FailOnSkipped         bool     `json:"fail-on-skipped"`
+	Format                string   `json:"format"`
+	Output                string   `json:"output"`
 }
 
 var validateCmd = &model.ExecutableCommand[validateFlags]{
 	Usage: "validate",
 	Short: "Validate OpenAPI specs and post PR comment with results",
-	Long:  "Validates OpenAPI specs matching the given glob patterns. Posts a consolidated PR comment with results and writes a GitHub Actions step summary.",
+	Long:  "Validates OpenAPI specs matching the given glob patterns. Posts a consolidated PR comment with results and writes a GitHub Actions step summary. Use --format to also write the findings as a SARIF, JUnit, JSON (GitLab code quality) or Checkstyle report.",
 	Run:   runValidate,
 	Flags: []flag.Flag{
 		flag.StringFlag{
@@ -56,6 +62,17 @@ var validateCmd = &model.ExecutableCommand[validateFlags]{
 			Description:  "Fail if any operations would be skipped during SDK generation",
 			DefaultValue: os.Getenv("INPUT_FAIL_ON_SKIPPED") == "true",
 		},
+		flag.StringFlag{
+			Name:         "format",
+			Description:  "Format of a report of the findings, written instead of the comment to stdout or to --output: " + strings.Join(validation.OutputFormats, ", "),
+			DefaultValue: os.Getenv("INPUT_FORMAT"),
+		},
+		flag.StringFlag{
+			Name:         "output",
+			Shorthand:    "o",
+			Description:  "Path to write the --format report to, defaults to stdout",
+			DefaultValue: os.Getenv("INPUT_OUTPUT"),
+		},
 	},
 }
 
@@ -65,6 +82,10 @@ func runValidate(ctx context.Context, flags validateFlags) error {
 		ruleset = "speakeasy-recommended"
 	}
 
+	if flags.Format != "" && !slices.Contains(validation.OutputFormats, flags.Format) {
+		return fmt.Errorf("invalid format %s, expected one of %s", flags.Format, strings.Join(validation.OutputFormats, ", "))
+	}
+
 	return actions.ValidateSpecs(ctx, actions.ValidateSpecsInputs{
 		GithubAccessToken:     flags.GithubAccessToken,
 		Specs:                 flags.Specs,
@@ -72,5 +93,7 @@ func runValidate(ctx context.Context, flags validateFlags) error {
 		MaxValidationWarnings: flags.MaxValidationWarnings,
 		Ruleset:               ruleset,
 		FailOnSkipped:         flags.FailOnSkipped,
+		Format:                validation.OutputFormat(flags.Format),
+		Output:                flags.Output,
 	})
 }
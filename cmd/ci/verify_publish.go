@@ -0,0 +1,89 @@
+package ci
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/speakeasy-api/speakeasy/internal/ci/actions"
+	"github.com/speakeasy-api/speakeasy/internal/model"
+	"github.com/speakeasy-api/speakeasy/internal/model/flag"
+)
+
+type verifyPublishFlags struct {
+	TargetDirectory  string `json:"target-directory"`
+	RegistryName     string `json:"registry-name"`
+	PackageName      string `json:"package-name"`
+	PackageVersion   string `json:"package-version"`
+	RegistryBaseURLs string `json:"registry-base-urls"`
+	TimeoutMinutes   int    `json:"timeout-minutes"`
+	WarnOnly         bool   `json:"warn-only"`
+	Debug            bool   `json:"debug"`
+}
+
+var verifyPublishCmd = &model.ExecutableCommand[verifyPublishFlags]{
+	Usage: "verify-publish",
+	Short: "Verify a published SDK version is available from its package registry (used by CI/CD)",
+	Long: `Polls the package registry (npm, PyPI, Go proxy, Maven Central, NuGet, RubyGems or Packagist)
+with backoff until the version released from the target directory is listed, failing if it
+does not appear before the timeout. The package name and version default to those in the
+target's gen.yaml and gen.lock.`,
+	Run: runVerifyPublish,
+	Flags: []flag.Flag{
+		flag.StringFlag{
+			Name:         "target-directory",
+			Description:  "Directory of the target SDK",
+			DefaultValue: os.Getenv("INPUT_TARGET_DIRECTORY"),
+		},
+		flag.StringFlag{
+			Name:         "registry-name",
+			Description:  "Name of the package registry (npm, pypi, go, sonatype, nuget, gems, packagist)",
+			DefaultValue: os.Getenv("INPUT_REGISTRY_NAME"),
+			Required:     true,
+		},
+		flag.StringFlag{
+			Name:         "package-name",
+			Description:  "Package name override (groupID:artifactID for Maven)",
+			DefaultValue: os.Getenv("INPUT_PACKAGE_NAME"),
+		},
+		flag.StringFlag{
+			Name:         "package-version",
+			Description:  "Package version override",
+			DefaultValue: os.Getenv("INPUT_PACKAGE_VERSION"),
+		},
+		flag.StringFlag{
+			Name:         "registry-base-urls",
+			Description:  "Comma/newline-separated registry=url overrides of the registry metadata endpoints",
+			DefaultValue: os.Getenv("INPUT_REGISTRY_BASE_URLS"),
+		},
+		flag.IntFlag{
+			Name:         "timeout-minutes",
+			Description:  "How long to wait for the package to appear",
+			DefaultValue: envIntOrDefault("INPUT_TIMEOUT_MINUTES", 10),
+		},
+		flag.BooleanFlag{
+			Name:         "warn-only",
+			Description:  "Annotate a missing package as a warning instead of failing",
+			DefaultValue: os.Getenv("INPUT_WARN_ONLY") == "true",
+		},
+		flag.BooleanFlag{
+			Name:         "debug",
+			Description:  "Enable debug mode",
+			DefaultValue: os.Getenv("INPUT_DEBUG") == "true",
+		},
+	},
+}
+
+func runVerifyPublish(ctx context.Context, flags verifyPublishFlags) error {
+	setEnvBool("INPUT_DEBUG", flags.Debug)
+
+	return actions.VerifyPublish(ctx, actions.VerifyPublishInputs{
+		TargetDirectory: flags.TargetDirectory,
+		RegistryName:    flags.RegistryName,
+		PackageName:     flags.PackageName,
+		Version:         flags.PackageVersion,
+		Endpoints:       flags.RegistryBaseURLs,
+		Timeout:         time.Duration(flags.TimeoutMinutes) * time.Minute,
+		WarnOnly:        flags.WarnOnly,
+	})
+}
@@ -43,11 +43,12 @@ speakeasy diff registry --namespace myns --old sha256:abc... --new sha256:def...
 ` + "```"
 
 var diffRegistryCmd = &model.ExecutableCommand[DiffFlags]{
-	Usage:        "registry",
-	Short:        "Compare specs by registry namespace and digests",
-	Long:         utils.RenderMarkdown(diffRegistryLong),
-	Run:          runDiffRegistry,
-	RequiresAuth: true,
+	Usage:           "registry",
+	Short:           "Compare specs by registry namespace and digests",
+	Long:            utils.RenderMarkdown(diffRegistryLong),
+	Run:             runDiffRegistry,
+	RequiresAuth:    true,
+	RequiresNetwork: true,
 	Flags: []flag.Flag{
 		flag.StringFlag{
 			Name:        "org",
@@ -2,6 +2,7 @@ package lint
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"maps"
@@ -19,6 +20,7 @@ import (
 	"github.com/speakeasy-api/speakeasy/internal/log"
 	"github.com/speakeasy-api/speakeasy/internal/model"
 	"github.com/speakeasy-api/speakeasy/internal/model/flag"
+	"github.com/speakeasy-api/speakeasy/internal/schemas"
 	"github.com/speakeasy-api/speakeasy/internal/sdkgen"
 	"github.com/speakeasy-api/speakeasy/internal/suggest"
 	"github.com/speakeasy-api/speakeasy/internal/utils"
@@ -47,12 +49,38 @@ type LintOpenapiFlags struct {
 	NonInteractive        bool   `json:"non-interactive"`
 	Suggestions           bool   `json:"suggestions"`
 	DryRun                bool   `json:"dry-run"`
+	Fix                   bool   `json:"fix"`
+	FixDryRun             bool   `json:"fix-dry-run"`
+	Baseline              string `json:"baseline"`
+	UpdateBaseline        bool   `json:"update-baseline"`
+	Format                string `json:"format"`
 }
 
 const lintOpenAPILong = `# Lint 
 ## OpenAPI
 
-Validates an OpenAPI document is valid and conforms to the Speakeasy OpenAPI specification.`
+Validates an OpenAPI document is valid and conforms to the Speakeasy OpenAPI specification.
+
+Organization specific rules, such as path naming or required extensions, can be added in YAML files in ` + "`.speakeasy/rules/`" + `. They use the rule format of Spectral rulesets: ` + "`given`" + ` selects nodes of the document with JSONPath, and ` + "`then`" + ` checks them, or one of their fields, with one of the ` + "`truthy`" + `, ` + "`falsy`" + `, ` + "`defined`" + `, ` + "`undefined`" + `, ` + "`pattern`" + `, ` + "`enumeration`" + `, ` + "`length`" + ` or ` + "`casing`" + ` functions. Use ` + "`~`" + ` to select property names, as in ` + "`$.paths.*~`" + `.
+
+` + "```yaml" + `
+rules:
+  operation-owner:
+    description: Operations must declare their owning team
+    severity: error
+    given: $.paths.*['get','put','post','patch','delete']
+    then:
+      field: x-owner
+      function: truthy
+` + "```" + `
+
+Custom rules are reported alongside those of the ruleset, and also apply when linting sources during ` + "`speakeasy run`" + `.
+
+Use ` + "`--fix`" + ` to fix findings that don't need judgement before linting: missing, duplicate and inconsistently cased operationIds, operations with a summary but no description, and unused components. The document is rewritten in place, keeping its comments and key order. Use ` + "`--fix-dry-run`" + ` to print the fixes as an overlay instead.
+
+Use ` + "`--baseline`" + ` to only report findings that aren't in a baseline of known findings, recorded with ` + "`--update-baseline`" + `. Findings are recorded per document, by its path relative to the current directory, and matched by rule, location and message, so they stay known when unrelated changes move them to another line. ` + "`speakeasy run`" + ` applies the baseline at ` + "`" + validation.DefaultBaselinePath + "`" + ` when there is one, so pre-existing findings don't fail it but new ones do.
+
+Use ` + "`--format`" + ` to write findings to stdout as a report for CI: ` + "`sarif`" + ` for GitHub code scanning, ` + "`json`" + ` for GitLab code quality, or ` + "`junit`" + ` and ` + "`checkstyle`" + ` for Jenkins.`
 
 var LintOpenapiCmd = &model.ExecutableCommand[LintOpenapiFlags]{
 	Usage:          "openapi",
@@ -105,6 +133,28 @@ var LintOpenapiCmd = &model.ExecutableCommand[LintOpenapiFlags]{
 			Name:        "dry-run",
 			Description: "run dry-run SDK generation to surface target-specific warnings",
 		},
+		flag.BooleanFlag{
+			Name:        "fix",
+			Description: "fix fixable findings, rewriting the document in place, before linting",
+		},
+		flag.BooleanFlag{
+			Name:        "fix-dry-run",
+			Description: "print an overlay with the fixes --fix would make, without changing the document",
+		},
+		flag.StringFlag{
+			Name:        "baseline",
+			Description: "path to a baseline of known findings to leave out, such as " + validation.DefaultBaselinePath,
+		},
+		flag.BooleanFlag{
+			Name:        "update-baseline",
+			Description: "record the current findings in the baseline, " + validation.DefaultBaselinePath + " unless --baseline is set",
+		},
+		flag.EnumFlag{
+			Name:          "format",
+			Description:   "output format, or a SARIF, JUnit, JSON (GitLab code quality) or Checkstyle report of the findings written to stdout",
+			AllowedValues: append([]string{"text"}, validation.OutputFormats...),
+			DefaultValue:  "text",
+		},
 	},
 }
 
@@ -128,14 +178,18 @@ var lintConfigCmd = &model.ExecutableCommand[lintConfigFlags]{
 }
 
 type lintArazzoFlags struct {
-	File string `json:"file"`
+	File    string            `json:"file"`
+	Schema  string            `json:"schema"`
+	Sources map[string]string `json:"source"`
 }
 
 var lintArazzoCmd = &model.ExecutableCommand[lintArazzoFlags]{
 	Usage: "arazzo",
 	Short: "Validate an Arazzo document",
-	Long:  `Validates an Arazzo document adheres to the Arazzo specification. Supports either yaml or json based Arazzo documents.`,
-	Run:   validateArazzo,
+	Long: `Validates an Arazzo document adheres to the Arazzo specification. Supports either yaml or json based Arazzo documents.
+
+The steps of each workflow are also checked against the OpenAPI documents of the source descriptions: referenced operations must exist, parameters and request body fields must match the operation, criteria and outputs must reference fields of its responses, and step and workflow outputs used by later steps must be defined. Use --schema or --source to check against a local OpenAPI document instead of the URLs in the document.`,
+	Run: validateArazzo,
 	Flags: []flag.Flag{
 		flag.StringFlag{
 			Name:         "file",
@@ -143,12 +197,30 @@ var lintArazzoCmd = &model.ExecutableCommand[lintArazzoFlags]{
 			Description:  "path to the Arazzo document",
 			DefaultValue: "arazzo.yaml",
 		},
+		flag.StringFlag{
+			Name:        "schema",
+			Shorthand:   "s",
+			Description: "path or URL of the OpenAPI document to check every OpenAPI source description against",
+		},
+		flag.MapFlag{
+			Name:        "source",
+			Description: "a map from source description name to the path or URL of its OpenAPI document",
+		},
 	},
 }
 
 func lintOpenapi(ctx context.Context, flags LintOpenapiFlags) error {
 	// no authentication required for validating specs
 
+	if flags.Fix || flags.FixDryRun {
+		if err := fixOpenapi(ctx, flags); err != nil {
+			return err
+		}
+		if flags.FixDryRun {
+			return nil
+		}
+	}
+
 	limits := validation.OutputLimits{
 		MaxWarns:  flags.MaxValidationWarnings,
 		MaxErrors: flags.MaxValidationErrors,
@@ -159,7 +231,21 @@ func lintOpenapi(ctx context.Context, flags LintOpenapiFlags) error {
 		return err
 	}
 
-	res, err := validation.ValidateOpenAPI(ctx, "", flags.SchemaPath, flags.Header, flags.Token, &limits, flags.Ruleset, wd, false, false, "")
+	if flags.UpdateBaseline {
+		return updateBaseline(ctx, flags, wd)
+	}
+
+	baseline, err := loadBaseline(flags, wd)
+	if err != nil {
+		return err
+	}
+
+	res, err := validation.ValidateOpenAPI(ctx, "", flags.SchemaPath, flags.Header, flags.Token, &limits, flags.Ruleset, wd, false, false, "", baseline)
+	if flags.Format != "text" && res != nil {
+		if err := validation.WriteFindings(os.Stdout, validation.OutputFormat(flags.Format), []string{flags.SchemaPath}, validation.FindingsOf(flags.SchemaPath, res)); err != nil {
+			return fmt.Errorf("failed to write findings: %w", err)
+		}
+	}
 	if err != nil {
 		return err
 	}
@@ -175,7 +261,7 @@ func lintOpenapi(ctx context.Context, flags LintOpenapiFlags) error {
 
 func lintOpenapiInteractive(ctx context.Context, flags LintOpenapiFlags) error {
 	// If non-interactive flag is set, use the non-interactive version
-	if flags.NonInteractive {
+	if flags.NonInteractive || flags.Fix || flags.FixDryRun || flags.UpdateBaseline || flags.Format != "text" {
 		return lintOpenapi(ctx, flags)
 	}
 
@@ -189,6 +275,11 @@ func lintOpenapiInteractive(ctx context.Context, flags LintOpenapiFlags) error {
 		return err
 	}
 
+	baseline, err := loadBaseline(flags, wd)
+	if err != nil {
+		return err
+	}
+
 	logger := log.From(ctx)
 	logger.Info("Linting OpenAPI document...\n")
 
@@ -199,7 +290,7 @@ func lintOpenapiInteractive(ctx context.Context, flags LintOpenapiFlags) error {
 	}
 
 	// Use the core Validate function to get results without displaying them
-	res, err := validation.Validate(ctx, logger, schema, flags.SchemaPath, &limits, isRemote, flags.Ruleset, wd, false, false, "")
+	res, err := validation.Validate(ctx, logger, schema, flags.SchemaPath, &limits, isRemote, flags.Ruleset, wd, false, false, "", baseline)
 	if err != nil {
 		return err
 	}
@@ -213,6 +304,101 @@ func lintOpenapiInteractive(ctx context.Context, flags LintOpenapiFlags) error {
 	return nil
 }
 
+// loadBaseline returns the known findings of the linted document in the
+// baseline at --baseline, if set.
+func loadBaseline(flags LintOpenapiFlags, wd string) (*validation.DocumentBaseline, error) {
+	if flags.Baseline == "" {
+		return nil, nil
+	}
+
+	baseline, err := validation.LoadBaseline(flags.Baseline)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("lint baseline %s not found, create it with --update-baseline", flags.Baseline)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return baseline.Document(validation.DocumentKey(wd, flags.SchemaPath)), nil
+}
+
+// updateBaseline records all current findings of a document in its baseline,
+// keeping those recorded for other documents.
+func updateBaseline(ctx context.Context, flags LintOpenapiFlags, wd string) error {
+	logger := log.From(ctx)
+	logger.Info("Linting OpenAPI document...\n")
+
+	isRemote, schema, err := openapi.GetSchemaContents(ctx, flags.SchemaPath, flags.Header, flags.Token)
+	if err != nil {
+		return fmt.Errorf("failed to get document contents: %w", err)
+	}
+
+	res, err := validation.Validate(ctx, logger, schema, flags.SchemaPath, nil, isRemote, flags.Ruleset, wd, false, true, "", nil)
+	if err != nil {
+		return err
+	}
+
+	path := flags.Baseline
+	if path == "" {
+		path = validation.DefaultBaselinePath
+	}
+	baseline, err := validation.LoadBaseline(path)
+	if errors.Is(err, os.ErrNotExist) {
+		baseline = &validation.Baseline{}
+	} else if err != nil {
+		return err
+	}
+	baseline.SetDocument(validation.DocumentKey(wd, flags.SchemaPath), validation.NewDocumentBaseline(schema, res.AllErrors))
+	if err := baseline.Write(path); err != nil {
+		return err
+	}
+
+	logger.Successf("Recorded %d findings in the lint baseline %s\n", len(res.AllErrors), path)
+	return nil
+}
+
+// fixOpenapi fixes the fixable findings of an OpenAPI document, rewriting it
+// in place, or for a dry run printing an overlay with the fixes.
+func fixOpenapi(ctx context.Context, flags LintOpenapiFlags) error {
+	logger := log.From(ctx)
+
+	isRemote, schema, err := openapi.GetSchemaContents(ctx, flags.SchemaPath, flags.Header, flags.Token)
+	if err != nil {
+		return fmt.Errorf("failed to get document contents: %w", err)
+	}
+	if isRemote && !flags.FixDryRun {
+		return fmt.Errorf("--fix can't rewrite remote documents, use --fix-dry-run to get an overlay with the fixes instead")
+	}
+
+	fixed, o, fixes, err := validation.FixOpenAPI(schema)
+	if err != nil {
+		return err
+	}
+
+	for _, fix := range fixes {
+		logger.Infof("Line %d: %s %s", fix.Line, styles.Dimmed.Render(fix.Rule), fix.Message)
+	}
+
+	if flags.FixDryRun {
+		return o.Format(os.Stdout)
+	}
+
+	if len(fixes) == 0 {
+		logger.Info("No fixable findings\n")
+		return nil
+	}
+
+	out, err := schemas.Render(fixed, flags.SchemaPath, utils.HasYAMLExt(flags.SchemaPath))
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(flags.SchemaPath, out, 0o644); err != nil {
+		return fmt.Errorf("failed to write fixed document: %w", err)
+	}
+
+	logger.Successf("Fixed %d findings in %s\n", len(fixes), flags.SchemaPath)
+	return nil
+}
+
 func lintConfig(ctx context.Context, flags lintConfigFlags) error {
 	// To support the old version of this command, check if there is no workflow.yaml. If there isn't, run the old version
 	wf, _, err := utils.GetWorkflowAndDir()
@@ -241,7 +427,7 @@ func lintConfig(ctx context.Context, flags lintConfigFlags) error {
 }
 
 func validateArazzo(ctx context.Context, flags lintArazzoFlags) error {
-	return arazzo.Validate(ctx, flags.File)
+	return arazzo.Validate(ctx, flags.File, flags.Schema, flags.Sources)
 }
 
 // runAndDisplayDiagnostics runs diagnostics on the schema and displays them in non-interactive mode
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/speakeasy-api/sdk-gen-config/workflow"
+	"github.com/speakeasy-api/speakeasy/internal/charm/styles"
+	"github.com/speakeasy-api/speakeasy/internal/download"
+	"github.com/speakeasy-api/speakeasy/internal/log"
+	"github.com/speakeasy-api/speakeasy/internal/model"
+	"github.com/speakeasy-api/speakeasy/internal/model/flag"
+	"github.com/speakeasy-api/speakeasy/internal/offline"
+	"github.com/speakeasy-api/speakeasy/internal/updates"
+	"github.com/speakeasy-api/speakeasy/internal/utils"
+)
+
+var mirrorCmd = &model.CommandGroup{
+	Usage: "mirror",
+	Short: "Manage the local mirror used in offline mode",
+	Long: `Manage the local mirror used in offline mode.
+
+With --offline or SPEAKEASY_OFFLINE set, the CLI doesn't use the network: pinned CLI versions and registry sources are resolved from a local mirror instead, at ~/.speakeasy/mirror or SPEAKEASY_MIRROR_DIR.
+Populate the mirror with speakeasy mirror sync on a machine with network access, then copy it to the air-gapped machine.`,
+	Commands: []model.Command{mirrorSyncCmd},
+}
+
+type mirrorSyncFlags struct {
+	Dir      string   `json:"dir"`
+	Versions []string `json:"version"`
+	Arches   []string `json:"arch"`
+}
+
+var mirrorSyncCmd = &model.ExecutableCommand[mirrorSyncFlags]{
+	Usage: "sync",
+	Short: "Download CLI releases and registry sources into the local mirror",
+	Long: `Download CLI releases and registry sources into the local mirror.
+
+The CLI releases mirrored are those of --version, by default the speakeasyVersion the workflow is pinned to, or the latest release.
+The registry sources mirrored are the registry inputs of the workflow, and the revisions in its lockfile for frozen runs.
+Releases are verified against their checksums and signatures as they would be when installed.`,
+	Run:             runMirrorSync,
+	RequiresAuth:    true,
+	RequiresNetwork: true,
+	Flags: []flag.Flag{
+		flag.StringFlag{
+			Name:        "dir",
+			Description: "the directory of the mirror, defaults to SPEAKEASY_MIRROR_DIR or ~/.speakeasy/mirror",
+		},
+		flag.StringSliceFlag{
+			Name:        "version",
+			Description: "CLI versions to mirror, a version number or latest",
+		},
+		flag.StringSliceFlag{
+			Name:        "arch",
+			Description: "CLI architectures to mirror, such as linux_amd64, defaults to the architecture of this CLI",
+		},
+	},
+}
+
+func runMirrorSync(ctx context.Context, flags mirrorSyncFlags) error {
+	logger := log.From(ctx)
+
+	mirrorDir := flags.Dir
+	if mirrorDir == "" {
+		var err error
+		if mirrorDir, err = offline.MirrorDir(); err != nil {
+			return err
+		}
+	}
+
+	// Mirroring the CLI alone doesn't need a workflow.
+	wf, projectDir, _ := utils.GetWorkflowAndDir()
+
+	versions := flags.Versions
+	if len(versions) == 0 {
+		versions = []string{"latest"}
+		if wf != nil && wf.SpeakeasyVersion.String() != "" && wf.SpeakeasyVersion.String() != "latest" {
+			versions = []string{strings.TrimPrefix(wf.SpeakeasyVersion.String(), "v")}
+		}
+	}
+
+	arches := flags.Arches
+	if len(arches) == 0 {
+		artifactArch, _ := ctx.Value(updates.ArtifactArchContextKey).(string)
+		arches = []string{artifactArch}
+	}
+
+	for _, arch := range arches {
+		tags, err := updates.MirrorReleases(ctx, mirrorDir, versions, arch, 30)
+		if err != nil {
+			return fmt.Errorf("failed to mirror CLI releases for %s: %w", arch, err)
+		}
+		logger.Infof("Mirrored CLI %s for %s", strings.Join(tags, ", "), arch)
+	}
+
+	if wf != nil {
+		documents, err := registryDocumentsToMirror(wf, projectDir)
+		if err != nil {
+			return err
+		}
+
+		for _, document := range documents {
+			if _, err := download.MirrorRegistryBundle(ctx, document, mirrorDir); err != nil {
+				return fmt.Errorf("failed to mirror %s/%s/%s@%s: %w", document.OrganizationSlug, document.WorkspaceSlug, document.NamespaceName, document.Reference, err)
+			}
+			logger.Infof("Mirrored registry source %s@%s", document.NamespaceName, document.Reference)
+		}
+	}
+
+	logger.PrintfStyled(styles.Success, "Mirror synced to %s\n", mirrorDir)
+	logger.PrintfStyled(styles.Dimmed, "Copy it to the offline machine and set SPEAKEASY_MIRROR_DIR if it isn't at ~/.speakeasy/mirror\n")
+
+	return nil
+}
+
+// registryDocumentsToMirror returns the registry inputs of the workflow's
+// sources, and the revisions its lockfile pins them to for frozen runs.
+func registryDocumentsToMirror(wf *workflow.Workflow, projectDir string) ([]workflow.SpeakeasyRegistryDocument, error) {
+	var documents []workflow.SpeakeasyRegistryDocument
+	var seen []string
+	add := func(document workflow.SpeakeasyRegistryDocument) {
+		key := strings.Join([]string{document.OrganizationSlug, document.WorkspaceSlug, document.NamespaceName, document.Reference}, "/")
+		if !slices.Contains(seen, key) {
+			seen = append(seen, key)
+			documents = append(documents, document)
+		}
+	}
+
+	lockfile, _ := workflow.LoadLockfile(projectDir)
+
+	for sourceID, source := range wf.Sources {
+		var orgSlug, workspaceSlug string
+		for _, input := range source.Inputs {
+			if !input.IsSpeakeasyRegistry() {
+				continue
+			}
+
+			document := workflow.ParseSpeakeasyRegistryReference(input.Location.Resolve())
+			if document == nil {
+				return nil, fmt.Errorf("failed to parse speakeasy registry reference %s", input.Location.Resolve())
+			}
+			add(*document)
+			orgSlug, workspaceSlug = document.OrganizationSlug, document.WorkspaceSlug
+		}
+
+		if orgSlug == "" && source.Registry != nil {
+			var err error
+			orgSlug, workspaceSlug, _, _, err = source.Registry.ParseRegistryLocation()
+			if err != nil {
+				return nil, fmt.Errorf("error parsing registry location %s: %w", string(source.Registry.Location), err)
+			}
+		}
+
+		if lockfile == nil || orgSlug == "" {
+			continue
+		}
+		lockSource, ok := lockfile.Sources[sourceID]
+		if !ok || lockSource.SourceNamespace == "" || lockSource.SourceRevisionDigest == "" {
+			continue
+		}
+
+		// The same location frozen runs resolve the source from.
+		location := fmt.Sprintf("registry.speakeasyapi.dev/%s/%s/%s@%s", orgSlug, workspaceSlug, lockSource.SourceNamespace, lockSource.SourceRevisionDigest)
+		document := workflow.ParseSpeakeasyRegistryReference(location)
+		if document == nil {
+			return nil, fmt.Errorf("failed to parse speakeasy registry reference %s", location)
+		}
+		add(*document)
+	}
+
+	return documents, nil
+}
@@ -80,3 +80,28 @@ func restoreFileToPristine(dir, filePath string, pristineContent []byte) error {
 
 	return nil
 }
+
+// newScratchProject creates a temporary directory holding copies of the given
+// files from the project's .speakeasy directory, to generate into without
+// touching the project or its git history. The caller removes the directory.
+func newScratchProject(dir string, files ...string) (string, error) {
+	tmpDir, err := os.MkdirTemp("", "speakeasy-patches-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".speakeasy"), 0o755); err != nil {
+		return tmpDir, err
+	}
+
+	for _, file := range files {
+		content, err := os.ReadFile(filepath.Join(dir, ".speakeasy", file))
+		if err != nil {
+			return tmpDir, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		if err := os.WriteFile(filepath.Join(tmpDir, ".speakeasy", file), content, 0o644); err != nil {
+			return tmpDir, err
+		}
+	}
+
+	return tmpDir, nil
+}
@@ -0,0 +1,196 @@
+package patches
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	config "github.com/speakeasy-api/sdk-gen-config"
+	"github.com/speakeasy-api/speakeasy/internal/model"
+	"github.com/speakeasy-api/speakeasy/internal/model/flag"
+	internalPatches "github.com/speakeasy-api/speakeasy/internal/patches"
+	"github.com/speakeasy-api/speakeasy/internal/updates"
+)
+
+type doctorFlags struct {
+	Dir          string `json:"dir"`
+	Fix          bool   `json:"fix"`
+	PruneDeleted bool   `json:"prune-deleted"`
+	Schema       string `json:"schema"`
+	Lang         string `json:"lang"`
+}
+
+var doctorCmd = &model.ExecutableCommand[doctorFlags]{
+	Usage: "doctor",
+	Short: "Find and repair missing pristine objects and stale entries in gen.lock",
+	Long: `Checks every file tracked in gen.lock for:
+
+  - pristine objects missing from the local git object database (shallow clones, gc)
+  - pristine objects that exist but are not reachable from any ref, and so may be gc'd
+  - entries for files that no longer exist on disk
+
+With --fix, missing objects are recovered by fetching the generation snapshots
+(refs/speakeasy/gen/*) from origin. Any still missing can be rebuilt by passing --schema
+and --lang: the SDK is regenerated into a temporary directory with the Speakeasy version
+recorded in gen.lock, and files whose content matches the recorded object exactly are
+restored. Unreachable objects are pinned under a new local snapshot ref, and stale entries
+are removed from gen.lock.`,
+	Run: runDoctor,
+	Flags: []flag.Flag{
+		flag.StringFlag{
+			Name:         "dir",
+			Shorthand:    "d",
+			Description:  "project directory containing .speakeasy/gen.lock",
+			DefaultValue: ".",
+		},
+		flag.BooleanFlag{
+			Name:        "fix",
+			Description: "repair the problems found",
+		},
+		flag.BooleanFlag{
+			Name:        "prune-deleted",
+			Description: "also prune entries for generated files you deleted on purpose; they will be generated again",
+		},
+		flag.StringFlag{
+			Name:        "schema",
+			Shorthand:   "s",
+			Description: "OpenAPI document the SDK was last generated from, to rebuild missing pristine objects",
+		},
+		flag.StringFlag{
+			Name:        "lang",
+			Shorthand:   "l",
+			Description: "target language of the SDK, required with --schema",
+		},
+	},
+}
+
+func runDoctor(ctx context.Context, flags doctorFlags) error {
+	if flags.Schema != "" && flags.Lang == "" {
+		return fmt.Errorf("--lang is required with --schema")
+	}
+
+	dir, lf, err := loadLockFile(flags.Dir)
+	if err != nil {
+		return err
+	}
+
+	gitRepo, err := internalPatches.OpenGitRepository(dir)
+	if err != nil {
+		return err
+	}
+
+	findings, err := internalPatches.Diagnose(dir, lf, gitRepo, flags.PruneDeleted)
+	if err != nil {
+		return err
+	}
+	if len(findings) == 0 {
+		fmt.Println("No problems found.")
+		return nil
+	}
+
+	if !flags.Fix {
+		printFindings(findings)
+		fmt.Println("\nRun with --fix to repair.")
+		return nil
+	}
+
+	if err := internalPatches.RecoverFromSnapshots(dir, gitRepo, findings); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	if flags.Schema != "" && hasUnfixedMissing(findings) {
+		generatedDir, err := generateAtRecordedVersion(ctx, dir, lf, flags.Schema, flags.Lang)
+		if generatedDir != "" {
+			defer os.RemoveAll(generatedDir)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		} else if err := internalPatches.RecoverFromGeneration(gitRepo, findings, generatedDir); err != nil {
+			return err
+		}
+	}
+
+	ref, err := internalPatches.PinPristineObjects(dir, gitRepo, findings)
+	if err != nil {
+		return err
+	}
+
+	internalPatches.PruneStaleEntries(lf, findings)
+	if err := config.SaveLockFile(dir, lf); err != nil {
+		return fmt.Errorf("failed to save gen.lock: %w", err)
+	}
+
+	printFindings(findings)
+	if ref != "" {
+		fmt.Printf("\nPinned pristine objects under %s. Push it to share them:\n  git push origin %s\n", ref, ref)
+	}
+	if hasUnfixedMissing(findings) {
+		fmt.Fprintln(os.Stderr, "\nSome pristine objects could not be recovered. Custom code in those files cannot be merged until they are; pass --schema and --lang to rebuild them.")
+	}
+
+	return nil
+}
+
+func printFindings(findings []internalPatches.DoctorFinding) {
+	for _, f := range findings {
+		status := "  "
+		if f.Fixed {
+			status = "✓ "
+		}
+		line := fmt.Sprintf("%s%-22s %s", status, f.Issue, f.Path)
+		if f.PristineObject != "" && f.Issue != internalPatches.IssueStaleEntry {
+			line += fmt.Sprintf(" (%s)", f.PristineObject[:min(12, len(f.PristineObject))])
+		}
+		if f.Detail != "" {
+			line += ": " + f.Detail
+		}
+		fmt.Println(line)
+	}
+}
+
+func hasUnfixedMissing(findings []internalPatches.DoctorFinding) bool {
+	for _, f := range findings {
+		if f.Issue == internalPatches.IssueMissingPristine && !f.Fixed {
+			return true
+		}
+	}
+	return false
+}
+
+// generateAtRecordedVersion regenerates the SDK into a temporary directory
+// with the Speakeasy version recorded in gen.lock, so unchanged inputs
+// reproduce the recorded pristine objects byte for byte.
+func generateAtRecordedVersion(ctx context.Context, dir string, lf *config.LockFile, schema, lang string) (string, error) {
+	version := lf.Management.SpeakeasyVersion
+	if version == "" {
+		return "", fmt.Errorf("gen.lock does not record the Speakeasy version used to generate the SDK")
+	}
+
+	schemaPath, err := filepath.Abs(schema)
+	if err != nil {
+		return "", err
+	}
+
+	artifactArch, _ := ctx.Value(updates.ArtifactArchContextKey).(string)
+	bin, err := updates.InstallVersion(ctx, version, artifactArch, 30)
+	if err != nil {
+		return "", fmt.Errorf("failed to install Speakeasy %s: %w", version, err)
+	}
+
+	tmpDir, err := newScratchProject(dir, "gen.yaml", "gen.lock")
+	if err != nil {
+		return tmpDir, err
+	}
+
+	fmt.Fprintf(os.Stderr, "Regenerating %s SDK with Speakeasy %s to rebuild pristine objects...\n", lang, version)
+	cmd := exec.CommandContext(ctx, bin, "generate", "sdk", "--lang", lang, "--schema", schemaPath, "--out", tmpDir, "--auto-yes")
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return tmpDir, fmt.Errorf("failed to regenerate SDK with Speakeasy %s: %w", version, err)
+	}
+
+	return tmpDir, nil
+}
@@ -0,0 +1,72 @@
+package patches
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/speakeasy-api/speakeasy/internal/model"
+	"github.com/speakeasy-api/speakeasy/internal/model/flag"
+	internalPatches "github.com/speakeasy-api/speakeasy/internal/patches"
+)
+
+type exportFlags struct {
+	Dir    string `json:"dir"`
+	Output string `json:"output"`
+}
+
+var exportCmd = &model.ExecutableCommand[exportFlags]{
+	Usage: "export",
+	Short: "Export custom code as a series of patch files",
+	Long: `Writes the diff of every file with custom code against its pristine (generated) version
+as a git format-patch compatible series, with a series.json manifest. The patches can be
+re-applied to a freshly generated SDK with "speakeasy patches import", or with git am.`,
+	Run: runExport,
+	Flags: []flag.Flag{
+		flag.StringFlag{
+			Name:         "dir",
+			Shorthand:    "d",
+			Description:  "project directory containing .speakeasy/gen.lock",
+			DefaultValue: ".",
+		},
+		flag.StringFlag{
+			Name:         "output",
+			Shorthand:    "o",
+			Description:  "directory to write the patch series to",
+			DefaultValue: "speakeasy-patches",
+		},
+	},
+}
+
+func runExport(ctx context.Context, flags exportFlags) error {
+	dir, lf, err := loadLockFile(flags.Dir)
+	if err != nil {
+		return err
+	}
+
+	gitRepo, err := internalPatches.OpenGitRepository(dir)
+	if err != nil {
+		return err
+	}
+
+	manifest, warnings, err := internalPatches.ExportSeries(dir, lf, gitRepo, flags.Output)
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(manifest.Patches) == 0 {
+		fmt.Println("No files with custom code detected.")
+		return nil
+	}
+
+	for _, patch := range manifest.Patches {
+		fmt.Printf("  %s (+%d/-%d)\n", filepath.Join(flags.Output, patch.File), patch.Added, patch.Removed)
+	}
+	fmt.Printf("\nExported %d patch(es) to %s\n", len(manifest.Patches), flags.Output)
+
+	return nil
+}
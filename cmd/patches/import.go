@@ -0,0 +1,78 @@
+package patches
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/speakeasy-api/speakeasy/internal/model"
+	"github.com/speakeasy-api/speakeasy/internal/model/flag"
+	internalPatches "github.com/speakeasy-api/speakeasy/internal/patches"
+)
+
+type importFlags struct {
+	Dir      string `json:"dir"`
+	Input    string `json:"input"`
+	ThreeWay bool   `json:"three-way"`
+}
+
+var importCmd = &model.ExecutableCommand[importFlags]{
+	Usage: "import",
+	Short: "Apply a patch series exported by \"speakeasy patches export\"",
+	Long: `Applies each patch of a series to the SDK in order. Files the generator has since moved are
+located by their @generated-id. When a patch no longer applies cleanly and the pristine
+object is available, a three-way merge leaves conflict markers to resolve.`,
+	Run: runImport,
+	Flags: []flag.Flag{
+		flag.StringFlag{
+			Name:         "dir",
+			Shorthand:    "d",
+			Description:  "project directory containing .speakeasy/gen.lock",
+			DefaultValue: ".",
+		},
+		flag.StringFlag{
+			Name:         "input",
+			Shorthand:    "i",
+			Description:  "directory containing the patch series",
+			DefaultValue: "speakeasy-patches",
+		},
+		flag.BooleanFlag{
+			Name:         "three-way",
+			Description:  "fall back to a three-way merge when a patch does not apply cleanly",
+			DefaultValue: true,
+		},
+	},
+}
+
+func runImport(ctx context.Context, flags importFlags) error {
+	dir, err := filepath.Abs(flags.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve directory: %w", err)
+	}
+
+	results, err := internalPatches.ImportSeries(dir, flags.Input, flags.ThreeWay)
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for _, result := range results {
+		switch {
+		case result.Err != nil:
+			failed++
+			fmt.Fprintf(os.Stderr, "  ✗ %s: %v\n", result.Patch.File, result.Err)
+		case result.Path != result.Patch.Path:
+			fmt.Printf("  ✓ %s -> %s (moved from %s)\n", result.Patch.File, result.Path, result.Patch.Path)
+		default:
+			fmt.Printf("  ✓ %s -> %s\n", result.Patch.File, result.Path)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d patch(es) failed to apply", failed, len(results))
+	}
+
+	fmt.Printf("\nApplied %d patch(es)\n", len(results))
+	return nil
+}
@@ -7,5 +7,5 @@ import (
 var PatchesCmd = &model.CommandGroup{
 	Usage:    "patches",
 	Short:    "Debug and inspect pristine vs patched SDK files",
-	Commands: []model.Command{viewPristineCmd, viewDiffCmd, restorePristineCmd},
+	Commands: []model.Command{viewPristineCmd, viewDiffCmd, restorePristineCmd, exportCmd, importCmd, resolveCmd, reportCmd, doctorCmd},
 }
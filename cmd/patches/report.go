@@ -0,0 +1,144 @@
+package patches
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/speakeasy-api/speakeasy-core/events"
+	"github.com/speakeasy-api/speakeasy/internal/model"
+	"github.com/speakeasy-api/speakeasy/internal/model/flag"
+	internalPatches "github.com/speakeasy-api/speakeasy/internal/patches"
+	"github.com/speakeasy-api/speakeasy/internal/sdkgen"
+)
+
+type reportFlags struct {
+	Dir     string `json:"dir"`
+	Format  string `json:"format"`
+	NextDir string `json:"next-dir"`
+	Schema  string `json:"schema"`
+	Lang    string `json:"lang"`
+}
+
+var reportCmd = &model.ExecutableCommand[reportFlags]{
+	Usage: "report",
+	Short: "Report on the custom code in the SDK and its risk of conflicting with the next generation",
+	Long: `Lists every file with custom code along with the lines added and removed, how long ago it was
+first customized (from git blame), and whether it would conflict with the next generation.
+
+To predict conflicts, either pass --schema and --lang to generate the upcoming SDK into a
+temporary directory, or point --next-dir at an already generated copy. The project itself is
+never modified. Use --format markdown for pull request comments.`,
+	Run: runReport,
+	Flags: []flag.Flag{
+		flag.StringFlag{
+			Name:         "dir",
+			Shorthand:    "d",
+			Description:  "project directory containing .speakeasy/gen.lock",
+			DefaultValue: ".",
+		},
+		flag.EnumFlag{
+			Name:          "format",
+			Shorthand:     "f",
+			Description:   "output format",
+			AllowedValues: []string{"table", "json", "markdown"},
+			DefaultValue:  "table",
+		},
+		flag.StringFlag{
+			Name:        "next-dir",
+			Description: "directory containing the output of the upcoming generation to predict conflicts against",
+		},
+		flag.StringFlag{
+			Name:        "schema",
+			Shorthand:   "s",
+			Description: "OpenAPI document to generate the upcoming SDK from, to predict conflicts",
+		},
+		flag.StringFlag{
+			Name:        "lang",
+			Shorthand:   "l",
+			Description: "target language of the SDK, required with --schema",
+		},
+	},
+}
+
+func runReport(ctx context.Context, flags reportFlags) error {
+	dir, lf, err := loadLockFile(flags.Dir)
+	if err != nil {
+		return err
+	}
+
+	gitRepo, err := internalPatches.OpenGitRepository(dir)
+	if err != nil {
+		return err
+	}
+
+	nextDir := flags.NextDir
+	if flags.Schema != "" {
+		if nextDir != "" {
+			return fmt.Errorf("--schema and --next-dir cannot be used together")
+		}
+		if flags.Lang == "" {
+			return fmt.Errorf("--lang is required with --schema")
+		}
+
+		nextDir, err = generateUpcoming(ctx, dir, flags.Schema, flags.Lang)
+		if nextDir != "" {
+			defer os.RemoveAll(nextDir)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	report, warnings, err := internalPatches.BuildReport(dir, lf, gitRepo, nextDir)
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+	if err != nil {
+		return err
+	}
+
+	switch flags.Format {
+	case "json":
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+	case "markdown":
+		fmt.Print(report.Markdown())
+	default:
+		if len(report.Files) == 0 {
+			fmt.Println("No files with custom code detected.")
+			return nil
+		}
+		fmt.Print(report.Table())
+	}
+
+	return nil
+}
+
+// generateUpcoming generates the SDK from scratch into a temporary directory
+// using the project's gen.yaml, giving the pristine output of the next
+// generation without touching the project.
+func generateUpcoming(ctx context.Context, dir, schema, lang string) (string, error) {
+	tmpDir, err := newScratchProject(dir, "gen.yaml")
+	if err != nil {
+		return tmpDir, err
+	}
+
+	fmt.Fprintf(os.Stderr, "Generating upcoming %s SDK to predict conflicts...\n", lang)
+	if _, err := sdkgen.Generate(ctx, sdkgen.GenerateOptions{
+		Language:       lang,
+		SchemaPath:     schema,
+		OutDir:         tmpDir,
+		CLIVersion:     events.GetSpeakeasyVersionFromContext(ctx),
+		AutoYes:        true,
+		SkipVersioning: true,
+	}); err != nil {
+		return tmpDir, fmt.Errorf("failed to generate upcoming SDK: %w", err)
+	}
+
+	return tmpDir, nil
+}
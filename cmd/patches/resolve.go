@@ -0,0 +1,100 @@
+package patches
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	config "github.com/speakeasy-api/sdk-gen-config"
+	"github.com/speakeasy-api/speakeasy/internal/interactivity"
+	"github.com/speakeasy-api/speakeasy/internal/model"
+	"github.com/speakeasy-api/speakeasy/internal/model/flag"
+	internalPatches "github.com/speakeasy-api/speakeasy/internal/patches"
+	"github.com/speakeasy-api/speakeasy/internal/utils"
+)
+
+type resolveFlags struct {
+	Dir string `json:"dir"`
+}
+
+var resolveCmd = &model.ExecutableCommand[resolveFlags]{
+	Usage: "resolve",
+	Short: "Interactively resolve conflicts between custom code and a new generation",
+	Long: `Walks each file left conflicted by generation, showing the previous generation (base),
+your custom code (ours) and the new generation (theirs) side by side. For each hunk, keep
+ours, theirs or both, or edit it in $EDITOR. Resolved files are written, marked resolved
+in the git index, and their pristine object in gen.lock is updated to the new generation.`,
+	Run: runResolve,
+	Flags: []flag.Flag{
+		flag.StringFlag{
+			Name:         "dir",
+			Shorthand:    "d",
+			Description:  "project directory containing .speakeasy/gen.lock",
+			DefaultValue: ".",
+		},
+	},
+}
+
+func runResolve(ctx context.Context, flags resolveFlags) error {
+	dir, lf, err := loadLockFile(flags.Dir)
+	if err != nil {
+		return err
+	}
+
+	gitRepo, err := internalPatches.OpenGitRepository(dir)
+	if err != nil {
+		return err
+	}
+
+	files, err := internalPatches.LoadConflicts(dir)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		fmt.Println("No conflicts to resolve.")
+		return nil
+	}
+
+	// Files whose conflicts merge cleanly line by line need no input.
+	var resolved, conflicted []*internalPatches.ConflictFile
+	for _, f := range files {
+		if f.Resolved() {
+			resolved = append(resolved, f)
+		} else {
+			conflicted = append(conflicted, f)
+		}
+	}
+
+	if len(conflicted) > 0 {
+		if !utils.IsInteractive() {
+			return fmt.Errorf("patches resolve requires an interactive terminal")
+		}
+
+		interactive, err := interactivity.RunConflictResolver(conflicted)
+		if err != nil {
+			return err
+		}
+		resolved = append(resolved, interactive...)
+	}
+
+	for _, f := range resolved {
+		if err := internalPatches.ApplyResolution(dir, lf, gitRepo, f); err != nil {
+			return err
+		}
+		fmt.Printf("  Resolved %s\n", f.Path)
+	}
+
+	if len(resolved) > 0 {
+		if err := config.SaveLockFile(dir, lf); err != nil {
+			return fmt.Errorf("failed to save gen.lock: %w", err)
+		}
+	}
+
+	if remaining := len(files) - len(resolved); remaining > 0 {
+		fmt.Fprintf(os.Stderr, "%d file(s) still have conflicts; run speakeasy patches resolve again to continue.\n", remaining)
+		return nil
+	}
+
+	fmt.Println("\nAll conflicts resolved. Run: speakeasy run --skip-versioning")
+	return nil
+}
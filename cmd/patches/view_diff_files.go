@@ -42,6 +42,7 @@ func runViewDiffFiles(ctx context.Context, flags viewDiffFilesFlags) error {
 	// Compare every tracked file on disk against its pristine git object.
 	// "Custom code" = file exists on disk AND differs from the pristine (generated) version.
 	var diffs []internalPatches.FileDiff
+	unreadable := 0
 	for path := range lf.TrackedFiles.Keys() {
 		tracked, ok := lf.TrackedFiles.Get(path)
 		if !ok || tracked.PristineGitObject == "" {
@@ -52,6 +53,7 @@ func runViewDiffFiles(ctx context.Context, flags viewDiffFilesFlags) error {
 		// ComputeFileDiff buries GetBlob errors internally, so check here first.
 		if _, err := gitRepo.GetBlob(tracked.PristineGitObject); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: could not read pristine object for %s: %v\n", path, err)
+			unreadable++
 			continue
 		}
 
@@ -61,6 +63,10 @@ func runViewDiffFiles(ctx context.Context, flags viewDiffFilesFlags) error {
 		}
 	}
 
+	if unreadable > 0 {
+		fmt.Fprintf(os.Stderr, "%d pristine object(s) are missing; run speakeasy patches doctor --fix to recover them.\n", unreadable)
+	}
+
 	if len(diffs) == 0 {
 		fmt.Println("No files with custom code detected.")
 		return nil
@@ -29,6 +29,7 @@ import (
 	"github.com/speakeasy-api/speakeasy/internal/config"
 	"github.com/speakeasy-api/speakeasy/internal/interactivity"
 	"github.com/speakeasy-api/speakeasy/internal/log"
+	"github.com/speakeasy-api/speakeasy/internal/offline"
 	"github.com/speakeasy-api/speakeasy/internal/sdk"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -144,6 +145,10 @@ func pullInit() {
 }
 
 func pullExec(cmd *cobra.Command, args []string) error {
+	if err := offline.RequireNetwork("speakeasy pull"); err != nil {
+		return err
+	}
+
 	flags := cmd.Flags()
 
 	// Get flag values
@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	config "github.com/speakeasy-api/sdk-gen-config"
+	ciutils "github.com/speakeasy-api/speakeasy/internal/ci/utils"
+	"github.com/speakeasy-api/speakeasy/internal/git"
+	"github.com/speakeasy-api/speakeasy/internal/log"
+	"github.com/speakeasy-api/speakeasy/internal/model"
+	"github.com/speakeasy-api/speakeasy/internal/model/flag"
+	"github.com/speakeasy-api/speakeasy/internal/rollback"
+	"github.com/speakeasy-api/speakeasy/internal/utils"
+)
+
+var releaseCmd = &model.CommandGroup{
+	Usage:    "release",
+	Short:    "Manage SDK releases",
+	Commands: []model.Command{releaseRollbackCmd},
+}
+
+type releaseRollbackFlags struct {
+	Target        string `json:"target"`
+	Version       string `json:"version"`
+	Branch        string `json:"branch"`
+	GitHubRelease string `json:"github-release"`
+	Reason        string `json:"reason"`
+}
+
+const releaseRollbackLong = `# Release Rollback
+
+Roll back a bad SDK release of a workflow target.
+
+This command:
+- finds the generation commit that released the version and reverts it on a new branch
+- restores gen.lock and workflow.lock to their state before the release
+- marks the GitHub release as a prerelease or draft (requires the gh CLI)
+- prints the commands to deprecate or yank the version from its package registry
+
+The rollback branch is not pushed. Review it, then push and open a pull request.
+A generation commit that also changed other targets, such as a squashed multi-target commit, is not rolled back; revert it manually.
+
+Example usage:
+` + "```bash" + `
+speakeasy release rollback --target my-python-sdk --version 1.4.0
+` + "```"
+
+var releaseRollbackCmd = &model.ExecutableCommand[releaseRollbackFlags]{
+	Usage: "rollback",
+	Short: "Revert a bad SDK release and print the commands to yank it from its registry",
+	Long:  utils.RenderMarkdown(releaseRollbackLong),
+	Run:   runReleaseRollback,
+	Flags: []flag.Flag{
+		flag.StringFlag{
+			Name:        "target",
+			Shorthand:   "t",
+			Description: "the workflow target to roll back",
+			Required:    true,
+		},
+		flag.StringFlag{
+			Name:        "version",
+			Shorthand:   "v",
+			Description: "the released version to roll back",
+			Required:    true,
+		},
+		flag.StringFlag{
+			Name:        "branch",
+			Shorthand:   "b",
+			Description: "name of the branch to create for the rollback (default: speakeasy-rollback-<target>-v<version>)",
+		},
+		flag.EnumFlag{
+			Name:          "github-release",
+			Description:   "how to mark the GitHub release of the version",
+			AllowedValues: []string{"prerelease", "draft", "none"},
+			DefaultValue:  "prerelease",
+		},
+		flag.StringFlag{
+			Name:        "reason",
+			Description: "reason for the rollback, used in deprecation messages",
+		},
+	},
+}
+
+func runReleaseRollback(ctx context.Context, flags releaseRollbackFlags) error {
+	logger := log.From(ctx)
+	version := strings.TrimPrefix(flags.Version, "v")
+
+	wf, projectDir, err := utils.GetWorkflowAndDir()
+	if err != nil {
+		return err
+	}
+
+	target, ok := wf.Targets[flags.Target]
+	if !ok {
+		return fmt.Errorf("target %s not found in workflow.yaml", flags.Target)
+	}
+
+	outDir := projectDir
+	if target.Output != nil {
+		outDir = filepath.Join(projectDir, *target.Output)
+	}
+
+	repoRoot, err := git.RunGitCommand(projectDir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return fmt.Errorf("rollback requires a git repository: %w", err)
+	}
+	repoRoot = strings.TrimSpace(repoRoot)
+
+	genLockPath, err := filepath.Rel(repoRoot, filepath.Join(outDir, ".speakeasy", "gen.lock"))
+	if err != nil {
+		return err
+	}
+	workflowLockPath, err := filepath.Rel(repoRoot, filepath.Join(projectDir, ".speakeasy", "workflow.lock"))
+	if err != nil {
+		return err
+	}
+
+	targetDir, err := filepath.Rel(repoRoot, outDir)
+	if err != nil {
+		return err
+	}
+	var otherTargetDirs []string
+	for name, other := range wf.Targets {
+		if name == flags.Target {
+			continue
+		}
+		otherOutDir := projectDir
+		if other.Output != nil {
+			otherOutDir = filepath.Join(projectDir, *other.Output)
+		}
+		if dir, err := filepath.Rel(repoRoot, otherOutDir); err == nil {
+			otherTargetDirs = append(otherTargetDirs, dir)
+		}
+	}
+
+	commit, err := rollback.FindGenerationCommit(repoRoot, genLockPath, version)
+	if err != nil {
+		return err
+	}
+	logger.Infof("Version %s of %s was generated in %s", version, flags.Target, commit.Hash)
+
+	branch := flags.Branch
+	if branch == "" {
+		branch = fmt.Sprintf("speakeasy-rollback-%s-v%s", flags.Target, version)
+	}
+
+	message := fmt.Sprintf("chore: roll back %s v%s", flags.Target, version)
+	if commit.PreviousVersion != "" {
+		message += fmt.Sprintf(" to v%s", commit.PreviousVersion)
+	}
+
+	result, err := rollback.Revert(rollback.Options{
+		RepoDir:          repoRoot,
+		Branch:           branch,
+		Commit:           commit.Hash,
+		GenLockPath:      genLockPath,
+		WorkflowLockPath: workflowLockPath,
+		Message:          message,
+		TargetDir:        targetDir,
+		OtherTargetDirs:  otherTargetDirs,
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Successf("Created rollback commit %s on branch %s", result.Commit, result.Branch)
+	for _, location := range result.Overridden {
+		logger.Warnf("%s was also changed after the release and has been reset to its previous value", location)
+	}
+
+	// Tags follow the same convention as releases created in CI.
+	tag := "v" + version
+	if relOut, err := filepath.Rel(repoRoot, outDir); err == nil && relOut != "." {
+		tag = filepath.ToSlash(relOut) + "/" + tag
+	}
+
+	if flags.GitHubRelease != "none" {
+		if err := markGitHubRelease(ctx, repoRoot, tag, flags.GitHubRelease); err != nil {
+			logger.Warnf("Failed to mark GitHub release %s as %s: %v", tag, flags.GitHubRelease, err)
+		} else {
+			logger.Successf("Marked GitHub release %s as %s", tag, flags.GitHubRelease)
+		}
+	}
+
+	logger.Println("\nTo deprecate or yank the release from its registry:")
+	for _, instruction := range rollback.YankInstructions(target.Target, rollbackPackageName(outDir, target.Target), version, tag, flags.Reason) {
+		logger.Println("  " + instruction)
+	}
+
+	logger.Println(fmt.Sprintf("\nPush the rollback branch when ready:\n  git push -u origin %s", result.Branch))
+
+	return nil
+}
+
+func markGitHubRelease(ctx context.Context, repoRoot, tag, state string) error {
+	args := []string{"release", "edit", tag}
+	if state == "draft" {
+		args = append(args, "--draft")
+	} else {
+		args = append(args, "--prerelease", "--latest=false")
+	}
+
+	cmd := exec.CommandContext(ctx, "gh", args...)
+	cmd.Dir = repoRoot
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("gh %s: %w - %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// rollbackPackageName returns the registry package name of the target, in the
+// groupID:artifactID form for Maven packages.
+func rollbackPackageName(outDir, lang string) string {
+	cfg, err := config.Load(outDir)
+	if err != nil || cfg.Config == nil {
+		return "<package>"
+	}
+
+	langCfg, ok := cfg.Config.Languages[lang]
+	if !ok {
+		return "<package>"
+	}
+
+	if lang == "java" {
+		return fmt.Sprintf("%s:%s", langCfg.Cfg["groupID"], langCfg.Cfg["artifactID"])
+	}
+	return ciutils.GetPackageName(lang, &langCfg)
+}
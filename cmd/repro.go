@@ -64,12 +64,13 @@ type slugs struct {
 }
 
 var reproCmd = &model.ExecutableCommand[ReproFlags]{
-	Usage:        "repro [repro-id]",
-	Short:        "Reproduce a failed generation locally",
-	Long:         utils.RenderMarkdown(reproLong),
-	Run:          runRepro,
-	RequiresAuth: true,
-	PreRun:       reproPreRun,
+	Usage:           "repro [repro-id]",
+	Short:           "Reproduce a failed generation locally",
+	Long:            utils.RenderMarkdown(reproLong),
+	Run:             runRepro,
+	RequiresAuth:    true,
+	RequiresNetwork: true,
+	PreRun:          reproPreRun,
 	Flags: []flag.Flag{
 		flag.StringFlag{
 			Name:        "directory",
@@ -74,6 +74,7 @@ func init() {
 
 func Init(version, artifactArch string) {
 	rootCmd.PersistentFlags().String("logLevel", string(log.LevelInfo), fmt.Sprintf("the log level (available options: [%s])", strings.Join(log.Levels, ", ")))
+	rootCmd.PersistentFlags().Bool("offline", env.IsOffline(), "run without network access, resolving pinned CLI versions and registry bundles from the local mirror populated by speakeasy mirror sync (also set by SPEAKEASY_OFFLINE)")
 
 	// TODO: migrate this file to use model.CommandGroup once all subcommands have been refactored
 	addCommand(rootCmd, agentCmd)
@@ -81,9 +82,11 @@ func Init(version, artifactArch string) {
 	addCommand(rootCmd, quickstartCmd)
 	addCommand(rootCmd, billingCmd)
 	addCommand(rootCmd, runCmd)
+	addCommand(rootCmd, studioCmd)
 	addCommand(rootCmd, configureCmd)
 	addCommand(rootCmd, generate.GenerateCmd)
 	addCommand(rootCmd, lint.LintCmd)
+	addCommand(rootCmd, arazzoCmd)
 	addCommand(rootCmd, mcp.MCPCmd)
 	addCommand(rootCmd, openapi.OpenAPICmd)
 	addCommand(rootCmd, migrateCmd)
@@ -98,6 +101,7 @@ func Init(version, artifactArch string) {
 	languageServerInit(version)
 	bumpInit()
 	addCommand(rootCmd, tagCmd)
+	addCommand(rootCmd, releaseCmd)
 	addCommand(rootCmd, cleanCmd)
 
 	addCommand(rootCmd, AskCmd)
@@ -107,6 +111,7 @@ func Init(version, artifactArch string) {
 	addCommand(rootCmd, patches.PatchesCmd)
 	addCommand(rootCmd, ci.CICmd)
 	pullInit()
+	addCommand(rootCmd, mirrorCmd)
 }
 
 func addCommand(cmd *cobra.Command, command model.Command) {
@@ -143,6 +148,12 @@ func setupRootCmd(version, artifactArch string) {
 		ctx = events.SetSpeakeasyVersionInContext(ctx, version)
 		cmd.SetContext(ctx)
 
+		// The environment carries offline mode to every package, and to the CLI
+		// version a workflow is pinned to.
+		if offlineFlag, _ := cmd.Flags().GetBool("offline"); offlineFlag {
+			_ = os.Setenv("SPEAKEASY_OFFLINE", "true")
+		}
+
 		if !slices.Contains([]string{"update", "language-server"}, cmd.Name()) {
 			checkForUpdate(ctx, version, artifactArch, cmd)
 		}
@@ -163,7 +174,7 @@ func checkForUpdate(ctx context.Context, currentVersion, artifactArch string, cm
 		return
 	}
 
-	if env.IsLocalDev() {
+	if env.IsLocalDev() || env.IsOffline() {
 		return
 	}
 
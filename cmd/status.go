@@ -35,10 +35,11 @@ type statusFlagsArgs struct {
 }
 
 var statusCmd = &model.ExecutableCommand[statusFlagsArgs]{
-	Usage:        "status",
-	Short:        "Review status of current workspace",
-	Run:          runStatus,
-	RequiresAuth: true,
+	Usage:           "status",
+	Short:           "Review status of current workspace",
+	Run:             runStatus,
+	RequiresAuth:    true,
+	RequiresNetwork: true,
 	Flags: []flag.Flag{
 		flag.EnumFlag{
 			Name:          "output",
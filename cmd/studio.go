@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/speakeasy-api/speakeasy/internal/log"
+	"github.com/speakeasy-api/speakeasy/internal/model"
+	"github.com/speakeasy-api/speakeasy/internal/model/flag"
+	"github.com/speakeasy-api/speakeasy/internal/run"
+	"github.com/speakeasy-api/speakeasy/internal/studio"
+	"github.com/speakeasy-api/speakeasy/internal/utils"
+)
+
+var studioCmd = &model.CommandGroup{
+	Usage:    "studio",
+	Short:    "Work on your workflow in the Speakeasy Studio",
+	Commands: []model.Command{studioServeCmd},
+}
+
+type studioServeFlags struct {
+	Target   string `json:"target"`
+	Source   string `json:"source"`
+	Headless bool   `json:"headless"`
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Token    string `json:"token"`
+}
+
+const studioServeLong = `# Studio Serve
+
+Run the workflow once, then serve the local Studio API for it.
+
+By default this opens the Studio web UI, as ` + "`speakeasy run --watch`" + ` does. With ` + "`--headless`" + ` the
+web UI is not opened and the API stays up on a fixed port until the process is stopped, so
+editor extensions and other local tools can drive the workflow:
+
+- the API is described by an OpenAPI document served at ` + "`/openapi.yaml`" + `
+- requests authenticate with the token in the ` + "`X-Secret-Key`" + ` header or as a bearer token
+- any number of clients can connect; runs are streamed to every client subscribed to ` + "`/events`" + `
+- requests select a source or target of the workflow with the ` + "`source`" + ` and ` + "`target`" + ` query parameters, and
+  targets generating into different output directories run concurrently, even if they share a source, whose
+  document is then written by one run at a time
+
+The token defaults to ` + "`SPEAKEASY_STUDIO_TOKEN`" + `, or to the saved Studio secret if that is not set.
+
+Example usage:
+` + "```bash" + `
+speakeasy studio serve --headless --port 4100 --target my-typescript-sdk
+` + "```"
+
+var studioServeCmd = &model.ExecutableCommand[studioServeFlags]{
+	Usage: "serve",
+	Short: "Serve the local Studio API, optionally headless for editor integrations",
+	Long:  utils.RenderMarkdown(studioServeLong),
+	Run:   runStudioServe,
+	Flags: []flag.Flag{
+		flag.StringFlag{
+			Name:         "target",
+			Shorthand:    "t",
+			Description:  "target ID to serve (as defined under 'targets' in workflow.yaml), or 'all'",
+			DefaultValue: "all",
+		},
+		flag.StringFlag{
+			Name:        "source",
+			Shorthand:   "s",
+			Description: "source ID to serve, instead of the source of the target",
+		},
+		flag.BooleanFlag{
+			Name:        "headless",
+			Description: "serve the API without opening the Studio web UI",
+		},
+		flag.StringFlag{
+			Name:         "host",
+			Description:  "interface to listen on in headless mode",
+			DefaultValue: "localhost",
+		},
+		flag.IntFlag{
+			Name:         "port",
+			Shorthand:    "p",
+			Description:  "port to listen on in headless mode",
+			DefaultValue: 3333,
+		},
+		flag.StringFlag{
+			Name:         "token",
+			Description:  "token clients must present in headless mode",
+			DefaultValue: os.Getenv("SPEAKEASY_STUDIO_TOKEN"),
+		},
+	},
+}
+
+func runStudioServe(ctx context.Context, flags studioServeFlags) error {
+	opts := []run.Opt{
+		run.WithSkipCleanup(), // The studio reruns the workflow, so keep its temporary files around
+		run.WithAllowPrompts(false),
+	}
+	if flags.Source != "" {
+		opts = append(opts, run.WithSource(flags.Source))
+	} else {
+		opts = append(opts, run.WithTarget(flags.Target))
+	}
+
+	workflow, err := run.NewWorkflow(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	defer workflow.Cleanup()
+
+	// The studio exists to fix failing runs, so serve it regardless of the outcome
+	if err := workflow.Run(ctx); err != nil {
+		log.From(ctx).Error(err.Error())
+	}
+
+	if !flags.Headless {
+		if !studio.CanLaunch(ctx, workflow) {
+			return fmt.Errorf("the studio can't be launched for this workflow; it needs a single source and an interactive terminal")
+		}
+		return studio.LaunchStudio(ctx, workflow)
+	}
+
+	return studio.ServeHeadless(ctx, workflow, studio.ServeOptions{
+		Host:  flags.Host,
+		Port:  flags.Port,
+		Token: flags.Token,
+	})
+}
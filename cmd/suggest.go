@@ -7,9 +7,11 @@ import (
 	charminternal "github.com/speakeasy-api/speakeasy/internal/charm"
 	"github.com/speakeasy-api/speakeasy/internal/model"
 	"github.com/speakeasy-api/speakeasy/internal/model/flag"
+	"github.com/speakeasy-api/speakeasy/internal/offline"
 	"github.com/speakeasy-api/speakeasy/internal/suggest"
 	"github.com/speakeasy-api/speakeasy/internal/utils"
 	"os"
+	"slices"
 )
 
 const suggestLong = `
@@ -53,31 +55,82 @@ var suggestFlagDefs = []flag.Flag{
 	},
 }
 
-var suggestOperationIDsCmd = &model.ExecutableCommand[suggestFlags]{
-	Usage:        "operation-ids",
-	Short:        "Automatically improve your SDK's method names",
-	Run:          runSuggestOperationIDs,
-	RequiresAuth: true,
-	Flags:        suggestFlagDefs,
+type suggestOperationIDsFlags struct {
+	Schema  string `json:"schema"`
+	Out     string `json:"out"`
+	Overlay bool   `json:"overlay"`
+	Local   bool   `json:"local"`
 }
 
+const suggestOperationIDsLong = `# Suggest Operation IDs
+
+Suggest method names for the operations of your OpenAPI document, grouping them with ` + "`x-speakeasy-group`" + ` and naming them with ` + "`x-speakeasy-name-override`" + `.
+
+With ` + "`--local`" + `, names are derived from each operation's path, HTTP method and tags instead of by the Speakeasy API, so the same document always produces the same suggestions and no login is required:
+
+- collections are listed and created: ` + "`GET /pets`" + ` becomes ` + "`pets.list()`" + ` and ` + "`POST /pets`" + ` becomes ` + "`pets.create()`" + `
+- items are fetched, updated and deleted: ` + "`GET /pets/{petId}`" + ` becomes ` + "`pets.get()`" + `
+- nested resources and actions are named after their path: ` + "`GET /pets/{petId}/owners`" + ` becomes ` + "`pets.listOwners()`" + ` and ` + "`POST /pets/{petId}/adopt`" + ` becomes ` + "`pets.adopt()`" + `
+
+Names that collide within a group are disambiguated by path parameter, then by HTTP method.`
+
+var suggestOperationIDsCmd = &model.ExecutableCommand[suggestOperationIDsFlags]{
+	Usage: "operation-ids",
+	Short: "Automatically improve your SDK's method names",
+	Long:  utils.RenderMarkdown(suggestOperationIDsLong),
+	Run:   runSuggestOperationIDs,
+	// Authentication is only needed without --local
+	RequiresAuthWith: func(flags *suggestOperationIDsFlags) bool {
+		return !flags.Local
+	},
+	Flags: append(slices.Clone(suggestFlagDefs), flag.BooleanFlag{
+		Name:        "local",
+		Description: "derive method names from paths, methods and tags without calling the Speakeasy API",
+	}),
+}
+
+const suggestErrorTypesLong = `# Suggest Error Types
+
+Suggest error responses for the operations of your OpenAPI document, reusing the error responses it already defines where possible. Suggestions are made locally, so no login is required and this works in offline mode.
+
+Status codes are grouped into shared responses: 400/422, 401/403, 404, 409, 412, 429 and 5XX by default. Supply your own grouping in ` + "`.speakeasy/suggest.yaml`" + ` to match an existing error model:
+
+` + "```yaml" + `
+errorSchema: "#/components/schemas/Error" # shared by every group, no schemas are added
+errorGroups:
+  - name: ClientError
+    codes: ["400", "404", "409"]
+    description: Client error
+  - name: ServerError
+    codes: ["5XX"]
+    responseName: ServerError
+    schemaRef: "#/components/schemas/ServerError"
+` + "```"
+
 var suggestErrorTypesCmd = &model.ExecutableCommand[suggestFlags]{
-	Usage:        "error-types",
-	Short:        "Automatically improve your SDK's error handling ergonomics",
-	Run:          runSuggestErrorTypes,
-	RequiresAuth: true,
-	Flags:        suggestFlagDefs,
+	Usage: "error-types",
+	Short: "Automatically improve your SDK's error handling ergonomics",
+	Long:  utils.RenderMarkdown(suggestErrorTypesLong),
+	Run:   runSuggestErrorTypes,
+	// Error types are suggested locally, without logging in or network access
+	Flags: suggestFlagDefs,
 }
 
-func runSuggestOperationIDs(ctx context.Context, flags suggestFlags) error {
-	return runSuggest(ctx, flags, suggestions.ModificationTypeMethodName)
+func runSuggestOperationIDs(ctx context.Context, flags suggestOperationIDsFlags) error {
+	if !flags.Local {
+		if err := offline.RequireNetwork("speakeasy suggest operation-ids without --local"); err != nil {
+			return err
+		}
+	}
+
+	return runSuggest(ctx, suggestFlags{Schema: flags.Schema, Out: flags.Out, Overlay: flags.Overlay}, suggestions.ModificationTypeMethodName, flags.Local)
 }
 
 func runSuggestErrorTypes(ctx context.Context, flags suggestFlags) error {
-	return runSuggest(ctx, flags, suggestions.ModificationTypeErrorNames)
+	return runSuggest(ctx, flags, suggestions.ModificationTypeErrorNames, false)
 }
 
-func runSuggest(ctx context.Context, flags suggestFlags, modificationType string) error {
+func runSuggest(ctx context.Context, flags suggestFlags, modificationType string, local bool) error {
 	yamlOut := utils.HasYAMLExt(flags.Out)
 	if flags.Overlay && !yamlOut {
 		return fmt.Errorf("output path must be a YAML or YML file when generating an overlay. Set --overlay=false to write an updated spec")
@@ -89,5 +142,5 @@ func runSuggest(ctx context.Context, flags suggestFlags, modificationType string
 	}
 	defer outFile.Close()
 
-	return suggest.SuggestAndWrite(ctx, modificationType, flags.Schema, flags.Overlay, yamlOut, outFile)
+	return suggest.SuggestAndWrite(ctx, modificationType, flags.Schema, flags.Overlay, yamlOut, local, outFile)
 }
@@ -30,10 +30,11 @@ type tagPromoteFlagsArgs struct {
 }
 
 var tagPromoteCmd = &model.ExecutableCommand[tagPromoteFlagsArgs]{
-	Usage:        "promote",
-	Short:        "Add tags to a revision in the Registry, based on the most recent workflow run",
-	Run:          runTagPromote,
-	RequiresAuth: true,
+	Usage:           "promote",
+	Short:           "Add tags to a revision in the Registry, based on the most recent workflow run",
+	Run:             runTagPromote,
+	RequiresAuth:    true,
+	RequiresNetwork: true,
 	Flags: []flag.Flag{
 		flag.StringSliceFlag{
 			Name:        "sources",
@@ -61,10 +62,11 @@ type tagApplyFlagsArgs struct {
 }
 
 var tagApplyCmd = &model.ExecutableCommand[tagApplyFlagsArgs]{
-	Usage:        "apply",
-	Short:        "Add tags to a given revision of your API. Specific to a registry namespace",
-	Run:          runTagApply,
-	RequiresAuth: true,
+	Usage:           "apply",
+	Short:           "Add tags to a given revision of your API. Specific to a registry namespace",
+	Run:             runTagApply,
+	RequiresAuth:    true,
+	RequiresNetwork: true,
 	Flags: []flag.Flag{
 		flag.StringFlag{ // TODO: maybe it would be better to just take in a registry URL
 			Name:        "namespace-name",
@@ -3,46 +3,50 @@ package arazzo
 import (
 	"context"
 	"fmt"
-	"os"
+	"slices"
 
 	"github.com/charmbracelet/lipgloss"
-	"github.com/speakeasy-api/openapi/arazzo"
 	"github.com/speakeasy-api/speakeasy/internal/charm/styles"
 	"github.com/speakeasy-api/speakeasy/internal/log"
 )
 
-func Validate(ctx context.Context, file string) error {
+// Validate validates an Arazzo document and checks its steps against the
+// OpenAPI documents of its source descriptions, as located by schema and
+// sources if set.
+func Validate(ctx context.Context, file, schema string, sources map[string]string) error {
 	logger := log.From(ctx)
 	logger.Info("Validating Arazzo document...\n")
 
-	f, err := os.Open(file)
+	validationErrors, err := Lint(ctx, file, schema, sources)
 	if err != nil {
-		return fmt.Errorf("failed to open file: %w", err)
+		return err
 	}
-	defer f.Close()
 
-	_, validationErrors, err := arazzo.Unmarshal(ctx, f)
-	if err != nil {
-		return fmt.Errorf("failed to unmarshal file: %w", err)
-	}
+	errs, warnings := splitBySeverity(validationErrors)
 
-	if len(validationErrors) == 0 {
+	if len(errs) == 0 {
 		msg := styles.RenderSuccessMessage(
 			"Arazzo document valid ✓",
 			"0 errors",
+			fmt.Sprintf("%d warnings", len(warnings)),
 		)
 		logger.Println(msg)
+		if len(warnings) > 0 {
+			logger.Println(styles.RenderWarningMessage("Warnings", listErrors(warnings)...))
+		}
 		return nil
 	}
 
-	lines := make([]string, 0, len(validationErrors))
-
-	for _, err := range validationErrors {
-		lines = append(lines, fmt.Sprintf("- %s", err.Error()))
-	}
-
-	msg := styles.RenderErrorMessage("Validation Errors", lipgloss.Center, lines...)
+	msg := styles.RenderErrorMessage("Validation Errors", lipgloss.Center, listErrors(slices.Concat(errs, warnings))...)
 	logger.Println(msg)
 
 	return fmt.Errorf(`Arazzo document invalid ✖`) //nolint:staticcheck // Arazzo is a name
 }
+
+func listErrors(errs []error) []string {
+	lines := make([]string, 0, len(errs))
+	for _, err := range errs {
+		lines = append(lines, fmt.Sprintf("- %s", err.Error()))
+	}
+	return lines
+}
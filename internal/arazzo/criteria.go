@@ -0,0 +1,432 @@
+package arazzo
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/speakeasy-api/jsonpath/pkg/jsonpath"
+	"github.com/speakeasy-api/openapi/arazzo/criterion"
+	"github.com/speakeasy-api/openapi/expression"
+	"gopkg.in/yaml.v3"
+)
+
+// check evaluates a criterion, returning an error describing why it isn't
+// met.
+func (s *scope) check(c *criterion.Criterion) error {
+	var context any
+	if c.Context != nil {
+		v, err := s.evaluate(*c.Context)
+		if err != nil {
+			return err
+		}
+		context = v
+	}
+
+	condition := strings.TrimSpace(c.Condition)
+
+	switch c.Type.GetType() {
+	case criterion.CriterionTypeSimple:
+		// A literal condition is the expected value of the context, as in
+		// the response bodies of generated test workflows
+		if c.Context != nil && !strings.HasPrefix(condition, "$") {
+			var expected any
+			if err := yaml.Unmarshal([]byte(condition), &expected); err != nil {
+				return fmt.Errorf("invalid condition %q: %w", condition, err)
+			}
+			if path, ok := match(expected, context, ""); !ok {
+				return fmt.Errorf("%s does not match the expected value at %s", *c.Context, pathOrRoot(path))
+			}
+			return nil
+		}
+
+		ok, err := s.evaluateCondition(condition)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("condition %s is not met", condition)
+		}
+		return nil
+	case criterion.CriterionTypeRegex:
+		re, err := regexp.Compile(condition)
+		if err != nil {
+			return fmt.Errorf("invalid regex %q: %w", condition, err)
+		}
+		if !re.MatchString(stringify(context)) {
+			return fmt.Errorf("%s does not match %s", contextName(c), condition)
+		}
+		return nil
+	case criterion.CriterionTypeJsonPath:
+		path, err := jsonpath.NewPath(condition)
+		if err != nil {
+			return fmt.Errorf("invalid JSONPath %q: %w", condition, err)
+		}
+		var node yaml.Node
+		data, err := json.Marshal(context)
+		if err != nil {
+			return err
+		}
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return err
+		}
+		if len(path.Query(&node)) == 0 {
+			return fmt.Errorf("%s does not match %s", contextName(c), condition)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%s criteria are not supported", c.Type.GetType())
+	}
+}
+
+func contextName(c *criterion.Criterion) string {
+	if c.Context == nil {
+		return "value"
+	}
+	return string(*c.Context)
+}
+
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+// evaluateCondition evaluates a simple condition such as
+// `$statusCode == 200 && $response.body#/id != null`.
+func (s *scope) evaluateCondition(condition string) (bool, error) {
+	tokens, err := tokenize(condition)
+	if err != nil {
+		return false, err
+	}
+
+	p := &conditionParser{scope: s, tokens: tokens}
+	v, err := p.or()
+	if err != nil {
+		return false, err
+	}
+	if p.pos < len(p.tokens) {
+		return false, fmt.Errorf("invalid condition %q: unexpected %s", condition, p.tokens[p.pos].value)
+	}
+	return v, nil
+}
+
+type tokenKind int
+
+const (
+	tokenOperand tokenKind = iota
+	tokenLiteral
+	tokenOperator
+	tokenParen
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+var operators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!"}
+
+func tokenize(condition string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(condition); {
+		c := condition[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, token{tokenParen, string(c)})
+			i++
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(condition[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("invalid condition %q: unterminated string", condition)
+			}
+			tokens = append(tokens, token{tokenLiteral, condition[i+1 : i+1+end]})
+			i += end + 2
+		default:
+			if op := operatorAt(condition[i:]); op != "" {
+				tokens = append(tokens, token{tokenOperator, op})
+				i += len(op)
+				continue
+			}
+			start := i
+			for i < len(condition) && !strings.ContainsRune(" \t\n()", rune(condition[i])) && operatorAt(condition[i:]) == "" {
+				i++
+			}
+			tokens = append(tokens, token{tokenOperand, condition[start:i]})
+		}
+	}
+	return tokens, nil
+}
+
+func operatorAt(s string) string {
+	for _, op := range operators {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+type conditionParser struct {
+	scope  *scope
+	tokens []token
+	pos    int
+}
+
+func (p *conditionParser) peek(kind tokenKind, values ...string) bool {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != kind {
+		return false
+	}
+	for _, v := range values {
+		if p.tokens[p.pos].value == v {
+			return true
+		}
+	}
+	return len(values) == 0
+}
+
+func (p *conditionParser) or() (bool, error) {
+	v, err := p.and()
+	if err != nil {
+		return false, err
+	}
+	for p.peek(tokenOperator, "||") {
+		p.pos++
+		rhs, err := p.and()
+		if err != nil {
+			return false, err
+		}
+		v = v || rhs
+	}
+	return v, nil
+}
+
+func (p *conditionParser) and() (bool, error) {
+	v, err := p.unary()
+	if err != nil {
+		return false, err
+	}
+	for p.peek(tokenOperator, "&&") {
+		p.pos++
+		rhs, err := p.unary()
+		if err != nil {
+			return false, err
+		}
+		v = v && rhs
+	}
+	return v, nil
+}
+
+func (p *conditionParser) unary() (bool, error) {
+	if p.peek(tokenOperator, "!") {
+		p.pos++
+		v, err := p.unary()
+		return !v, err
+	}
+	if p.peek(tokenParen, "(") {
+		p.pos++
+		v, err := p.or()
+		if err != nil {
+			return false, err
+		}
+		if !p.peek(tokenParen, ")") {
+			return false, fmt.Errorf("invalid condition: missing )")
+		}
+		p.pos++
+		return v, nil
+	}
+	return p.comparison()
+}
+
+func (p *conditionParser) comparison() (bool, error) {
+	lhs, err := p.operand()
+	if err != nil {
+		return false, err
+	}
+	if !p.peek(tokenOperator, "==", "!=", "<", "<=", ">", ">=") {
+		return truthy(lhs), nil
+	}
+	op := p.tokens[p.pos].value
+	p.pos++
+
+	rhs, err := p.operand()
+	if err != nil {
+		return false, err
+	}
+	return compare(lhs, op, rhs)
+}
+
+func (p *conditionParser) operand() (any, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("invalid condition: missing operand")
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+
+	switch t.kind {
+	case tokenLiteral:
+		return t.value, nil
+	case tokenOperand:
+		if strings.HasPrefix(t.value, "$") {
+			return p.scope.evaluate(expression.Expression(t.value))
+		}
+		return literal(t.value), nil
+	default:
+		return nil, fmt.Errorf("invalid condition: unexpected %s", t.value)
+	}
+}
+
+func literal(s string) any {
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	return s
+}
+
+var statusRange = regexp.MustCompile(`^[1-5][xX][xX]$`)
+
+func compare(lhs any, op string, rhs any) (bool, error) {
+	switch op {
+	case "==", "!=":
+		equal := equals(lhs, rhs)
+		return equal == (op == "=="), nil
+	}
+
+	l, lok := toFloat(lhs)
+	r, rok := toFloat(rhs)
+	if !lok || !rok {
+		return false, fmt.Errorf("cannot compare %v %s %v: values must be numbers", lhs, op, rhs)
+	}
+	switch op {
+	case "<":
+		return l < r, nil
+	case "<=":
+		return l <= r, nil
+	case ">":
+		return l > r, nil
+	default:
+		return l >= r, nil
+	}
+}
+
+func equals(a, b any) bool {
+	// Status code ranges, as in $statusCode == 2XX
+	for _, pair := range [][2]any{{a, b}, {b, a}} {
+		if s, ok := pair[1].(string); ok && statusRange.MatchString(s) {
+			if n, ok := toFloat(pair[0]); ok {
+				return int(n)/100 == int(s[0]-'0')
+			}
+		}
+	}
+
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if l, ok := toFloat(a); ok {
+		if r, ok := toFloat(b); ok {
+			return l == r
+		}
+	}
+	if l, ok := a.(bool); ok {
+		r, ok := b.(bool)
+		return ok && l == r
+	}
+
+	as, bs := stringify(a), stringify(b)
+	if as == bs {
+		return true
+	}
+	// Media types match regardless of parameters, so a Content-Type of
+	// application/json; charset=utf-8 equals application/json
+	if strings.Contains(as, "/") && strings.Contains(bs, "/") {
+		am, _, aerr := mime.ParseMediaType(as)
+		bm, _, berr := mime.ParseMediaType(bs)
+		return aerr == nil && berr == nil && am == bm
+	}
+	return false
+}
+
+func toFloat(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func truthy(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	default:
+		return true
+	}
+}
+
+// match checks that actual contains everything in expected: objects may have
+// additional properties, arrays and scalars must be equal. It returns the JSON
+// pointer of the first mismatch.
+func match(expected, actual any, path string) (string, bool) {
+	switch e := expected.(type) {
+	case map[string]any:
+		a, ok := actual.(map[string]any)
+		if !ok {
+			return path, false
+		}
+		for key, value := range e {
+			child := path + "/" + escapeTokens([]string{key})[0]
+			if _, ok := a[key]; !ok {
+				return child, false
+			}
+			if p, ok := match(value, a[key], child); !ok {
+				return p, false
+			}
+		}
+		return "", true
+	case []any:
+		a, ok := actual.([]any)
+		if !ok || len(a) != len(e) {
+			return path, false
+		}
+		for i := range e {
+			if p, ok := match(e[i], a[i], fmt.Sprintf("%s/%d", path, i)); !ok {
+				return p, false
+			}
+		}
+		return "", true
+	default:
+		if !equals(expected, actual) {
+			return path, false
+		}
+		return "", true
+	}
+}
@@ -0,0 +1,732 @@
+package arazzo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/speakeasy-api/openapi/arazzo"
+	"github.com/speakeasy-api/openapi/arazzo/criterion"
+	"github.com/speakeasy-api/openapi/expression"
+)
+
+const (
+	// maxStepExecutions bounds the steps a workflow runs, to stop goto
+	// actions that loop forever.
+	maxStepExecutions = 1000
+	// maxWorkflowDepth bounds workflows calling workflows.
+	maxWorkflowDepth = 32
+	// DefaultRequestTimeout bounds each request when RunOptions doesn't set
+	// a timeout or a client.
+	DefaultRequestTimeout = 30 * time.Second
+)
+
+// RunOptions configure a run of an Arazzo document.
+type RunOptions struct {
+	File string
+	// BaseURL is the server requests are sent to, defaulting to the first
+	// server of each operation's source description.
+	BaseURL string
+	// WorkflowIDs selects the workflows to run, defaulting to all of them.
+	WorkflowIDs []string
+	// Schema replaces the location of every OpenAPI source description.
+	Schema string
+	// Sources replaces the location of source descriptions by name, taking
+	// precedence over Schema.
+	Sources map[string]string
+	Inputs  map[string]any
+	// Headers are sent with every request, e.g. for authentication.
+	Headers http.Header
+	// Timeout bounds each request, defaulting to DefaultRequestTimeout. It is
+	// ignored if Client is set.
+	Timeout time.Duration
+	Client  *http.Client
+	// OnStep is called after each step completes.
+	OnStep func(workflowID string, step *StepResult)
+}
+
+// Runner runs the workflows of an Arazzo document.
+type Runner struct {
+	doc     *arazzo.Arazzo
+	dir     string
+	sources []*source
+	opts    RunOptions
+	report  *Report
+	results map[string]*WorkflowResult
+	depth   int
+}
+
+// transition is what a workflow does after a step completes.
+type transition struct {
+	end        bool
+	stepID     string
+	workflowID string
+}
+
+type parameter struct {
+	name  string
+	in    arazzo.In
+	value any
+}
+
+// Execute runs the workflows of an Arazzo document, in document order, and
+// reports their results. Workflows a selected workflow depends on run first.
+func Execute(ctx context.Context, opts RunOptions) (*Report, error) {
+	if opts.Client == nil {
+		timeout := opts.Timeout
+		if timeout <= 0 {
+			timeout = DefaultRequestTimeout
+		}
+		opts.Client = &http.Client{Timeout: timeout}
+	}
+
+	f, err := os.Open(opts.File)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	doc, _, err := arazzo.Unmarshal(ctx, f, arazzo.WithSkipValidation())
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal file: %w", err)
+	}
+
+	for name := range opts.Sources {
+		if doc.SourceDescriptions.Find(name) == nil {
+			return nil, fmt.Errorf("source description %s not found in %s", name, opts.File)
+		}
+	}
+	for _, id := range opts.WorkflowIDs {
+		if doc.Workflows.Find(id) == nil {
+			return nil, fmt.Errorf("workflow %s not found in %s", id, opts.File)
+		}
+	}
+
+	dir := filepath.Dir(opts.File)
+	sources, err := loadSources(ctx, opts.Client, doc, dir, opts.Schema, opts.Sources)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Runner{
+		doc:     doc,
+		dir:     dir,
+		sources: sources,
+		opts:    opts,
+		report:  &Report{File: opts.File, BaseURL: opts.BaseURL, Passed: true},
+		results: map[string]*WorkflowResult{},
+	}
+
+	start := time.Now()
+	for _, w := range doc.Workflows {
+		if len(opts.WorkflowIDs) > 0 && !slices.Contains(opts.WorkflowIDs, w.WorkflowID) {
+			continue
+		}
+		// Already run as a dependency
+		if _, ok := r.results[w.WorkflowID]; ok {
+			continue
+		}
+		r.runTopLevel(ctx, w)
+	}
+	r.report.Duration = time.Since(start).Seconds()
+
+	return r.report, nil
+}
+
+// runTopLevel runs a workflow with the inputs of the run and records it in
+// the report.
+func (r *Runner) runTopLevel(ctx context.Context, w *arazzo.Workflow) *WorkflowResult {
+	result := r.runWorkflow(ctx, w, r.opts.Inputs)
+	r.results[w.WorkflowID] = result
+	r.report.Workflows = append(r.report.Workflows, result)
+	r.report.Passed = r.report.Passed && result.Passed
+	return result
+}
+
+func (r *Runner) runWorkflow(ctx context.Context, w *arazzo.Workflow, inputs map[string]any) *WorkflowResult {
+	result := &WorkflowResult{WorkflowID: w.WorkflowID, Passed: true, inputs: inputs}
+	start := time.Now()
+	defer func() {
+		result.Duration = time.Since(start).Seconds()
+	}()
+
+	r.depth++
+	defer func() { r.depth-- }()
+	if r.depth > maxWorkflowDepth {
+		result.fail(fmt.Errorf("workflows are nested more than %d deep", maxWorkflowDepth))
+		return result
+	}
+
+	for _, dependency := range w.DependsOn {
+		id := string(dependency)
+		if dependency.IsExpression() {
+			result.fail(fmt.Errorf("depending on workflow %s of another source description is not supported", id))
+			return result
+		}
+
+		dependencyResult, ok := r.results[id]
+		if !ok {
+			dependencyWorkflow := r.doc.Workflows.Find(id)
+			if dependencyWorkflow == nil {
+				result.fail(fmt.Errorf("depends on unknown workflow %s", id))
+				return result
+			}
+			dependencyResult = r.runTopLevel(ctx, dependencyWorkflow)
+		}
+		if !dependencyResult.Passed {
+			result.fail(fmt.Errorf("depends on workflow %s, which failed", id))
+			return result
+		}
+	}
+
+	s := &scope{runner: r, inputs: inputs, steps: map[string]map[string]any{}}
+
+	executions := 0
+	for i := 0; i < len(w.Steps); {
+		if executions++; executions > maxStepExecutions {
+			result.fail(fmt.Errorf("ran more than %d steps, check the goto actions for loops", maxStepExecutions))
+			return result
+		}
+
+		step := w.Steps[i]
+		stepResult, next := r.runStep(ctx, w, step, s)
+		result.Steps = append(result.Steps, stepResult)
+		if r.opts.OnStep != nil {
+			r.opts.OnStep(w.WorkflowID, stepResult)
+		}
+
+		switch {
+		case next.workflowID != "":
+			target := r.doc.Workflows.Find(next.workflowID)
+			if target == nil {
+				result.fail(fmt.Errorf("step %s goes to unknown workflow %s", step.StepID, next.workflowID))
+				return result
+			}
+			targetResult := r.runWorkflow(ctx, target, inputs)
+			if !targetResult.Passed {
+				result.fail(fmt.Errorf("workflow %s failed: %s", next.workflowID, targetResult.failure()))
+			}
+			return result
+		case next.stepID != "":
+			j := slices.IndexFunc(w.Steps, func(s *arazzo.Step) bool { return s.StepID == next.stepID })
+			if j < 0 {
+				result.fail(fmt.Errorf("step %s goes to unknown step %s", step.StepID, next.stepID))
+				return result
+			}
+			i = j
+		case next.end:
+			if !stepResult.Passed {
+				result.Passed = false
+			}
+			if result.Passed {
+				r.evaluateWorkflowOutputs(w, s, result)
+			}
+			return result
+		default:
+			i++
+		}
+	}
+
+	r.evaluateWorkflowOutputs(w, s, result)
+	return result
+}
+
+func (r *Runner) evaluateWorkflowOutputs(w *arazzo.Workflow, s *scope, result *WorkflowResult) {
+	outputs, err := evaluateOutputs(w.Outputs, s)
+	if err != nil {
+		result.fail(fmt.Errorf("failed to evaluate outputs: %w", err))
+		return
+	}
+	result.Outputs = outputs
+}
+
+func evaluateOutputs(outputs arazzo.Outputs, s *scope) (map[string]any, error) {
+	values := map[string]any{}
+	for name, expr := range outputs.All() {
+		v, err := s.evaluate(expr)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		values[name] = v
+	}
+	return values, nil
+}
+
+// runStep runs a step, retrying it as its failure actions direct, and returns
+// what the workflow should do next.
+func (r *Runner) runStep(ctx context.Context, w *arazzo.Workflow, step *arazzo.Step, s *scope) (*StepResult, transition) {
+	result := &StepResult{StepID: step.StepID}
+	start := time.Now()
+	defer func() {
+		result.Duration = time.Since(start).Seconds()
+	}()
+
+	for {
+		result.Attempts++
+		stepScope := &scope{runner: r, inputs: s.inputs, steps: s.steps}
+
+		result.Failures = r.attempt(ctx, w, step, stepScope, result)
+		if len(result.Failures) == 0 {
+			outputs, err := evaluateOutputs(step.Outputs, stepScope)
+			if err != nil {
+				result.Failures = append(result.Failures, fmt.Sprintf("failed to evaluate outputs: %s", err))
+			} else {
+				result.Passed = true
+				result.Outputs = outputs
+				s.steps[step.StepID] = outputs
+
+				action, err := r.successAction(w, step, stepScope)
+				if err != nil {
+					result.Passed = false
+					result.Failures = append(result.Failures, err.Error())
+					return result, transition{end: true}
+				}
+				if action == nil {
+					return result, transition{}
+				}
+				return result, transitionTo(action.Type == arazzo.SuccessActionTypeEnd, action.StepID, action.WorkflowID)
+			}
+		}
+
+		action, err := r.failureAction(w, step, stepScope)
+		if err != nil {
+			result.Failures = append(result.Failures, err.Error())
+			return result, transition{end: true}
+		}
+		if action == nil {
+			return result, transition{end: true}
+		}
+
+		switch action.Type {
+		case arazzo.FailureActionTypeRetry:
+			limit := 1
+			if action.RetryLimit != nil {
+				limit = *action.RetryLimit
+			}
+			if result.Attempts > limit {
+				return result, transition{end: true}
+			}
+			if action.RetryAfter != nil {
+				select {
+				case <-ctx.Done():
+					result.Failures = append(result.Failures, ctx.Err().Error())
+					return result, transition{end: true}
+				case <-time.After(time.Duration(*action.RetryAfter * float64(time.Second))):
+				}
+			}
+		default:
+			return result, transitionTo(action.Type == arazzo.FailureActionTypeEnd, action.StepID, action.WorkflowID)
+		}
+	}
+}
+
+func transitionTo(end bool, stepID *string, workflowID *expression.Expression) transition {
+	switch {
+	case end:
+		return transition{end: true}
+	case stepID != nil:
+		return transition{stepID: *stepID}
+	case workflowID != nil:
+		return transition{workflowID: string(*workflowID)}
+	default:
+		return transition{end: true}
+	}
+}
+
+// successAction returns the first success action of the step, or else of the
+// workflow, whose criteria are met.
+func (r *Runner) successAction(w *arazzo.Workflow, step *arazzo.Step, s *scope) (*arazzo.SuccessAction, error) {
+	actions := step.OnSuccess
+	if len(actions) == 0 {
+		actions = w.SuccessActions
+	}
+
+	for _, reusable := range actions {
+		action := reusable.Get(r.doc.Components)
+		if action == nil {
+			return nil, fmt.Errorf("success action %s not found", reusableName(reusable.Reference))
+		}
+		if s.meets(action.Criteria) {
+			return action, nil
+		}
+	}
+	return nil, nil
+}
+
+// failureAction returns the first failure action of the step, or else of the
+// workflow, whose criteria are met.
+func (r *Runner) failureAction(w *arazzo.Workflow, step *arazzo.Step, s *scope) (*arazzo.FailureAction, error) {
+	actions := step.OnFailure
+	if len(actions) == 0 {
+		actions = w.FailureActions
+	}
+
+	for _, reusable := range actions {
+		action := reusable.Get(r.doc.Components)
+		if action == nil {
+			return nil, fmt.Errorf("failure action %s not found", reusableName(reusable.Reference))
+		}
+		if s.meets(action.Criteria) {
+			return action, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *scope) meets(criteria []criterion.Criterion) bool {
+	for i := range criteria {
+		if err := s.check(&criteria[i]); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func reusableName(reference *expression.Expression) string {
+	if reference == nil {
+		return "<unnamed>"
+	}
+	return string(*reference)
+}
+
+// attempt calls the step's operation or workflow once and returns the reasons
+// it failed, if any.
+func (r *Runner) attempt(ctx context.Context, w *arazzo.Workflow, step *arazzo.Step, s *scope, result *StepResult) []string {
+	var err error
+	switch {
+	case step.WorkflowID != nil:
+		err = r.callWorkflow(ctx, w, step, s, result)
+	case step.OperationID != nil || step.OperationPath != nil:
+		err = r.callOperation(ctx, w, step, s, result)
+	default:
+		err = errors.New("step has no operationId, operationPath or workflowId")
+	}
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	var failures []string
+	if len(step.SuccessCriteria) == 0 && s.exchange != nil && (s.exchange.statusCode < 200 || s.exchange.statusCode > 299) {
+		failures = append(failures, fmt.Sprintf("unexpected status code %d", s.exchange.statusCode))
+	}
+	for _, c := range step.SuccessCriteria {
+		if err := s.check(c); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+	return failures
+}
+
+func (r *Runner) callWorkflow(ctx context.Context, w *arazzo.Workflow, step *arazzo.Step, s *scope, result *StepResult) error {
+	id := string(*step.WorkflowID)
+	result.WorkflowID = id
+	if step.WorkflowID.IsExpression() {
+		return fmt.Errorf("calling workflow %s of another source description is not supported", id)
+	}
+
+	target := r.doc.Workflows.Find(id)
+	if target == nil {
+		return fmt.Errorf("workflow %s not found", id)
+	}
+
+	params, err := r.parameters(w, step, s, nil)
+	if err != nil {
+		return err
+	}
+	inputs := map[string]any{}
+	for _, p := range params {
+		inputs[p.name] = p.value
+	}
+
+	targetResult := r.runWorkflow(ctx, target, inputs)
+	if !targetResult.Passed {
+		return fmt.Errorf("workflow %s failed: %s", id, targetResult.failure())
+	}
+	s.outputs = targetResult.Outputs
+	return nil
+}
+
+func (r *Runner) callOperation(ctx context.Context, w *arazzo.Workflow, step *arazzo.Step, s *scope, result *StepResult) error {
+	var op *operation
+	var err error
+	if step.OperationID != nil {
+		op, err = findOperationByID(r.sources, string(*step.OperationID))
+	} else {
+		op, err = findOperationByPath(r.sources, string(*step.OperationPath))
+	}
+	if err != nil {
+		return err
+	}
+	result.Operation = op.String()
+
+	params, err := r.parameters(w, step, s, op)
+	if err != nil {
+		return err
+	}
+
+	baseURL := r.opts.BaseURL
+	if baseURL == "" {
+		baseURL = op.baseURL()
+	}
+	if baseURL == "" {
+		return errors.New("no server to send requests to, set --base-url or add servers to the OpenAPI document")
+	}
+
+	e := &exchange{
+		method:         strings.ToUpper(op.method),
+		requestHeaders: r.opts.Headers.Clone(),
+		requestQuery:   url.Values{},
+		requestPath:    map[string]string{},
+	}
+	if e.requestHeaders == nil {
+		e.requestHeaders = http.Header{}
+	}
+
+	path := op.path
+	var cookies []*http.Cookie
+	for _, p := range params {
+		switch p.in {
+		case arazzo.InPath:
+			e.requestPath[p.name] = stringify(p.value)
+			path = strings.ReplaceAll(path, "{"+p.name+"}", url.PathEscape(stringify(p.value)))
+		case arazzo.InHeader:
+			e.requestHeaders.Set(p.name, stringify(p.value))
+		case arazzo.InCookie:
+			cookies = append(cookies, &http.Cookie{Name: p.name, Value: stringify(p.value)})
+		default:
+			if values, ok := p.value.([]any); ok {
+				for _, v := range values {
+					e.requestQuery.Add(p.name, stringify(v))
+				}
+			} else {
+				e.requestQuery.Set(p.name, stringify(p.value))
+			}
+		}
+	}
+
+	e.url = strings.TrimSuffix(baseURL, "/") + path
+	if len(e.requestQuery) > 0 {
+		e.url += "?" + e.requestQuery.Encode()
+	}
+
+	body, contentType, payload, err := r.requestBody(step.RequestBody, op, s)
+	if err != nil {
+		return err
+	}
+	e.requestBody = payload
+
+	req, err := http.NewRequestWithContext(ctx, e.method, e.url, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header = e.requestHeaders.Clone()
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+
+	res, err := r.opts.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	e.statusCode = res.StatusCode
+	e.responseHeaders = res.Header
+	e.responseBody = decodeBody(res.Header.Get("Content-Type"), data)
+
+	s.exchange = e
+	result.StatusCode = res.StatusCode
+	return nil
+}
+
+// parameters resolves the parameters of the workflow and step, with those of
+// the step taking precedence. Parameters without a location are placed where
+// the operation declares them, or in the query.
+func (r *Runner) parameters(w *arazzo.Workflow, step *arazzo.Step, s *scope, op *operation) ([]parameter, error) {
+	var params []parameter
+	for _, reusable := range slices.Concat(w.Parameters, step.Parameters) {
+		param := reusable.Get(r.doc.Components)
+		if param == nil {
+			return nil, fmt.Errorf("parameter %s not found", reusableName(reusable.Reference))
+		}
+
+		node := param.Value
+		if reusable.Value != nil {
+			node = reusable.Value
+		}
+		value, err := s.value(node)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", param.Name, err)
+		}
+
+		var in arazzo.In
+		if param.In != nil {
+			in = *param.In
+		} else if op != nil {
+			in, _ = op.parameterLocation(param.Name)
+		}
+		if in == "" {
+			in = arazzo.InQuery
+		}
+
+		i := slices.IndexFunc(params, func(p parameter) bool { return p.name == param.Name && p.in == in })
+		if i >= 0 {
+			params[i].value = value
+		} else {
+			params = append(params, parameter{name: param.Name, in: in, value: value})
+		}
+	}
+	return params, nil
+}
+
+// requestBody encodes the payload of a step, after applying its replacements,
+// for its content type.
+func (r *Runner) requestBody(rb *arazzo.RequestBody, op *operation, s *scope) (io.Reader, string, any, error) {
+	if rb == nil {
+		return nil, "", nil, nil
+	}
+
+	payload, err := s.value(rb.Payload)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("request body: %w", err)
+	}
+	for _, replacement := range rb.Replacements {
+		value, err := s.value(replacement.Value)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("request body replacement %s: %w", replacement.Target, err)
+		}
+		if payload, err = setPointer(payload, string(replacement.Target), value); err != nil {
+			return nil, "", nil, fmt.Errorf("request body replacement: %w", err)
+		}
+	}
+
+	contentType := ""
+	if rb.ContentType != nil {
+		contentType = *rb.ContentType
+	} else if content := op.op.GetRequestBody().GetObject().GetContent(); content != nil {
+		for mediaType := range content.Keys() {
+			contentType = mediaType
+			break
+		}
+	}
+	if contentType == "" {
+		contentType = "application/json"
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if str, ok := payload.(string); ok && mediaType != "multipart/form-data" {
+		return strings.NewReader(str), contentType, payload, nil
+	}
+
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		fields, ok := payload.(map[string]any)
+		if !ok {
+			return nil, "", nil, fmt.Errorf("request body for %s must be an object", mediaType)
+		}
+		form := url.Values{}
+		for name, value := range fields {
+			form.Set(name, stringify(value))
+		}
+		return strings.NewReader(form.Encode()), contentType, payload, nil
+	case mediaType == "multipart/form-data":
+		body, contentType, err := r.multipartBody(payload)
+		return body, contentType, payload, err
+	default:
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("failed to encode request body: %w", err)
+		}
+		return bytes.NewReader(data), contentType, payload, nil
+	}
+}
+
+// multipartBody encodes a multipart form. Files are written as {"": name},
+// as in generated test workflows, and read relative to the document if they
+// exist there; otherwise the name is sent as the file's content.
+func (r *Runner) multipartBody(payload any) (io.Reader, string, error) {
+	fields, ok := payload.(map[string]any)
+	if !ok {
+		return nil, "", errors.New("request body for multipart/form-data must be an object")
+	}
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		values, ok := fields[name].([]any)
+		if !ok {
+			values = []any{fields[name]}
+		}
+		for _, value := range values {
+			if file, ok := value.(map[string]any); ok && len(file) == 1 && file[""] != nil {
+				filename := stringify(file[""])
+				content, err := os.ReadFile(filepath.Join(r.dir, filename))
+				if err != nil {
+					content = []byte(filename)
+				}
+				part, err := mw.CreateFormFile(name, filepath.Base(filename))
+				if err != nil {
+					return nil, "", err
+				}
+				if _, err := part.Write(content); err != nil {
+					return nil, "", err
+				}
+				continue
+			}
+			if err := mw.WriteField(name, stringify(value)); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, "", err
+	}
+	return &buf, mw.FormDataContentType(), nil
+}
+
+// decodeBody decodes JSON responses, keeping numbers exact, and returns
+// anything else as a string.
+func decodeBody(contentType string, data []byte) any {
+	if len(data) == 0 {
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+		d := json.NewDecoder(bytes.NewReader(data))
+		d.UseNumber()
+		var v any
+		if err := d.Decode(&v); err == nil {
+			return v
+		}
+	}
+	return string(data)
+}
@@ -0,0 +1,184 @@
+package arazzo_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/speakeasy-api/speakeasy/internal/arazzo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPetServer(t *testing.T) (*httptest.Server, *[]string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	pets := map[string]map[string]any{}
+	var requests []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /pets", func(w http.ResponseWriter, r *http.Request) {
+		var pet map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&pet))
+
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, "POST /pets")
+		pet["id"] = fmt.Sprintf("pet-%d", len(pets)+1)
+		pets[pet["id"].(string)] = pet
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusCreated)
+		require.NoError(t, json.NewEncoder(w).Encode(pet))
+	})
+	mux.HandleFunc("GET /pets/{petId}", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, "GET /pets/"+r.PathValue("petId"))
+		pet, ok := pets[r.PathValue("petId")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(pet))
+	})
+	mux.HandleFunc("DELETE /pets/{petId}", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, fmt.Sprintf("DELETE /pets/%s (%s)", r.PathValue("petId"), r.Header.Get("X-Reason")))
+		delete(pets, r.PathValue("petId"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestExecute(t *testing.T) {
+	t.Parallel()
+
+	server, requests := newPetServer(t)
+
+	report, err := arazzo.Execute(context.Background(), arazzo.RunOptions{
+		File:    "testdata/pets.arazzo.yaml",
+		BaseURL: server.URL,
+		Schema:  "openapi.yaml",
+		Inputs:  map[string]any{"name": "Rex"},
+	})
+	require.NoError(t, err)
+
+	assert.False(t, report.Passed)
+	require.Len(t, report.Workflows, 2)
+
+	lifecycle := report.Workflows[0]
+	assert.Equal(t, "lifecycle", lifecycle.WorkflowID)
+	assert.True(t, lifecycle.Passed, lifecycle.Error)
+	require.Len(t, lifecycle.Steps, 3)
+	for _, step := range lifecycle.Steps {
+		assert.True(t, step.Passed, "step %s: %v", step.StepID, step.Failures)
+	}
+	assert.Equal(t, "POST /pets", lifecycle.Steps[0].Operation)
+	assert.Equal(t, 201, lifecycle.Steps[0].StatusCode)
+	assert.Equal(t, map[string]any{"id": "pet-1"}, lifecycle.Steps[0].Outputs)
+	assert.Equal(t, map[string]any{"name": "Rex"}, lifecycle.Steps[1].Outputs)
+	assert.Equal(t, map[string]any{"id": "pet-1"}, lifecycle.Outputs)
+
+	// The pet is gone by the time the dependent workflow looks for it, so it
+	// fails after retrying
+	missing := report.Workflows[1]
+	assert.Equal(t, "missing", missing.WorkflowID)
+	assert.False(t, missing.Passed)
+	require.Len(t, missing.Steps, 1)
+	assert.Equal(t, 3, missing.Steps[0].Attempts)
+	assert.Equal(t, 404, missing.Steps[0].StatusCode)
+	assert.Equal(t, []string{"condition $statusCode == 200 is not met"}, missing.Steps[0].Failures)
+
+	assert.Equal(t, []string{
+		"POST /pets",
+		"GET /pets/pet-1",
+		"DELETE /pets/pet-1 (cleanup of Rex)",
+		"GET /pets/pet-1",
+		"GET /pets/pet-1",
+		"GET /pets/pet-1",
+	}, *requests)
+}
+
+func TestExecuteSelectedWorkflow(t *testing.T) {
+	t.Parallel()
+
+	server, _ := newPetServer(t)
+
+	report, err := arazzo.Execute(context.Background(), arazzo.RunOptions{
+		File:        "testdata/pets.arazzo.yaml",
+		BaseURL:     server.URL,
+		WorkflowIDs: []string{"lifecycle"},
+		Sources:     map[string]string{"pets": "openapi.yaml"},
+		Inputs:      map[string]any{"name": "Rex"},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, report.Passed)
+	require.Len(t, report.Workflows, 1)
+	assert.Equal(t, "lifecycle", report.Workflows[0].WorkflowID)
+}
+
+func TestExecuteErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := arazzo.Execute(context.Background(), arazzo.RunOptions{
+		File:        "testdata/pets.arazzo.yaml",
+		Schema:      "openapi.yaml",
+		Inputs:      map[string]any{"name": "Rex"},
+		WorkflowIDs: []string{"unknown"},
+	})
+	assert.ErrorContains(t, err, "workflow unknown not found")
+
+	_, err = arazzo.Execute(context.Background(), arazzo.RunOptions{
+		File: "testdata/pets.arazzo.yaml",
+		Client: &http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+			return nil, fmt.Errorf("offline")
+		})},
+	})
+	assert.ErrorContains(t, err, "failed to load source description pets from https://TBD.com")
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestReport(t *testing.T) {
+	t.Parallel()
+
+	server, _ := newPetServer(t)
+
+	report, err := arazzo.Execute(context.Background(), arazzo.RunOptions{
+		File:    "testdata/pets.arazzo.yaml",
+		BaseURL: server.URL,
+		Schema:  "openapi.yaml",
+		Inputs:  map[string]any{"name": "Rex"},
+	})
+	require.NoError(t, err)
+
+	var junit bytes.Buffer
+	require.NoError(t, report.WriteJUnit(&junit))
+	assert.Contains(t, junit.String(), `<testsuites name="testdata/pets.arazzo.yaml" tests="4" failures="1"`)
+	assert.Contains(t, junit.String(), `<testsuite name="lifecycle" tests="3" failures="0"`)
+	assert.Contains(t, junit.String(), `<failure message="step get failed">condition $statusCode == 200 is not met</failure>`)
+
+	var out bytes.Buffer
+	require.NoError(t, report.WriteJSON(&out))
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, false, decoded["passed"])
+	assert.True(t, strings.Contains(out.String(), `"operation": "GET /pets/{petId}"`))
+}
@@ -0,0 +1,317 @@
+package arazzo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/speakeasy-api/openapi/expression"
+	"gopkg.in/yaml.v3"
+)
+
+// exchange is the request a step sent and the response it received.
+type exchange struct {
+	url    string
+	method string
+
+	requestHeaders http.Header
+	requestQuery   url.Values
+	requestPath    map[string]string
+	requestBody    any
+
+	statusCode      int
+	responseHeaders http.Header
+	responseBody    any
+}
+
+// scope holds the values runtime expressions are evaluated against while a
+// workflow runs.
+type scope struct {
+	runner *Runner
+	inputs map[string]any
+	// steps holds the outputs of the steps of the workflow run so far.
+	steps map[string]map[string]any
+	// outputs holds the outputs of the workflow the current step called.
+	outputs  map[string]any
+	exchange *exchange
+}
+
+// evaluate returns the value of a runtime expression such as $statusCode,
+// $response.body#/id or $steps.create.outputs.id.
+func (s *scope) evaluate(expr expression.Expression) (any, error) {
+	if expr == "" {
+		return nil, nil
+	}
+
+	typ, reference, parts, pointer := expr.GetParts()
+	name := strings.Join(parts, ".")
+
+	var value any
+	switch typ {
+	case expression.ExpressionTypeURL:
+		value = s.exchangeValue(func(e *exchange) any { return e.url })
+	case expression.ExpressionTypeMethod:
+		value = s.exchangeValue(func(e *exchange) any { return e.method })
+	case expression.ExpressionTypeStatusCode:
+		value = s.exchangeValue(func(e *exchange) any { return e.statusCode })
+	case expression.ExpressionTypeRequest:
+		if s.exchange == nil {
+			return nil, fmt.Errorf("%s is only available in steps calling an operation", expr)
+		}
+		switch reference {
+		case expression.ReferenceTypeHeader:
+			value = s.exchange.requestHeaders.Get(name)
+		case expression.ReferenceTypeQuery:
+			value = s.exchange.requestQuery.Get(name)
+		case expression.ReferenceTypePath:
+			value = s.exchange.requestPath[name]
+		case expression.ReferenceTypeBody:
+			value = s.exchange.requestBody
+		default:
+			return nil, fmt.Errorf("unsupported expression %s", expr)
+		}
+	case expression.ExpressionTypeResponse:
+		if s.exchange == nil {
+			return nil, fmt.Errorf("%s is only available in steps calling an operation", expr)
+		}
+		switch reference {
+		case expression.ReferenceTypeHeader:
+			value = s.exchange.responseHeaders.Get(name)
+		case expression.ReferenceTypeBody:
+			value = s.exchange.responseBody
+		default:
+			return nil, fmt.Errorf("unsupported expression %s", expr)
+		}
+	case expression.ExpressionTypeInputs:
+		v, ok := s.inputs[reference]
+		if !ok {
+			return nil, fmt.Errorf("input %s not provided", reference)
+		}
+		value = v
+	case expression.ExpressionTypeOutputs:
+		v, ok := s.outputs[reference]
+		if !ok {
+			return nil, fmt.Errorf("output %s not defined", reference)
+		}
+		value = v
+	case expression.ExpressionTypeSteps:
+		outputs, ok := s.steps[reference]
+		if !ok {
+			return nil, fmt.Errorf("step %s has not run", reference)
+		}
+		if len(parts) < 2 || parts[0] != "outputs" {
+			return nil, fmt.Errorf("unsupported expression %s", expr)
+		}
+		v, ok := outputs[strings.Join(parts[1:], ".")]
+		if !ok {
+			return nil, fmt.Errorf("step %s has no output %s", reference, strings.Join(parts[1:], "."))
+		}
+		value = v
+	case expression.ExpressionTypeWorkflows:
+		result, ok := s.runner.results[reference]
+		if !ok {
+			return nil, fmt.Errorf("workflow %s has not run", reference)
+		}
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("unsupported expression %s", expr)
+		}
+		field := strings.Join(parts[1:], ".")
+		var values map[string]any
+		switch parts[0] {
+		case "outputs":
+			values = result.Outputs
+		case "inputs":
+			values = result.inputs
+		default:
+			return nil, fmt.Errorf("unsupported expression %s", expr)
+		}
+		v, ok := values[field]
+		if !ok {
+			return nil, fmt.Errorf("workflow %s has no %s %s", reference, parts[0], field)
+		}
+		value = v
+	case expression.ExpressionTypeSourceDescriptions:
+		for _, src := range s.runner.sources {
+			if src.name == reference && name == "url" {
+				value = src.location
+			}
+		}
+		if value == nil {
+			return nil, fmt.Errorf("unsupported expression %s", expr)
+		}
+	case expression.ExpressionTypeComponents:
+		if reference != "parameters" || s.runner.doc.Components == nil {
+			return nil, fmt.Errorf("unsupported expression %s", expr)
+		}
+		param, ok := s.runner.doc.Components.Parameters.Get(name)
+		if !ok {
+			return nil, fmt.Errorf("component parameter %s not found", name)
+		}
+		v, err := s.value(param.Value)
+		if err != nil {
+			return nil, err
+		}
+		value = v
+	default:
+		return nil, fmt.Errorf("unsupported expression %s", expr)
+	}
+
+	return resolvePointer(value, string(pointer))
+}
+
+func (s *scope) exchangeValue(get func(e *exchange) any) any {
+	if s.exchange == nil {
+		return nil
+	}
+	return get(s.exchange)
+}
+
+// value returns the value of a parameter, payload or replacement, evaluating
+// it if it's a runtime expression and substituting any expressions embedded
+// in its strings, as in "Bearer {$inputs.token}".
+func (s *scope) value(node *yaml.Node) (any, error) {
+	v, expr, err := expression.GetValueOrExpressionValue(node)
+	if err != nil {
+		return nil, err
+	}
+	if expr != nil {
+		return s.evaluate(*expr)
+	}
+	if v == nil {
+		return nil, nil
+	}
+
+	var decoded any
+	if err := v.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	return s.interpolate(decoded)
+}
+
+func (s *scope) interpolate(v any) (any, error) {
+	switch v := v.(type) {
+	case string:
+		expr := expression.Expression(v)
+		if expr.IsExpression() {
+			return s.evaluate(expr)
+		}
+		for _, embedded := range expression.ExtractExpressions(v) {
+			if !embedded.IsExpression() {
+				continue
+			}
+			value, err := s.evaluate(embedded)
+			if err != nil {
+				return nil, err
+			}
+			v = strings.Replace(v, string(embedded), stringify(value), 1)
+		}
+		return v, nil
+	case map[string]any:
+		for key, value := range v {
+			interpolated, err := s.interpolate(value)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = interpolated
+		}
+		return v, nil
+	case []any:
+		for i, value := range v {
+			interpolated, err := s.interpolate(value)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = interpolated
+		}
+		return v, nil
+	default:
+		return v, nil
+	}
+}
+
+// resolvePointer returns the value at a JSON pointer within a decoded JSON or
+// YAML value.
+func resolvePointer(value any, pointer string) (any, error) {
+	for _, token := range splitPointer(pointer) {
+		switch v := value.(type) {
+		case map[string]any:
+			next, ok := v[token]
+			if !ok {
+				return nil, fmt.Errorf("%s not found", pointer)
+			}
+			value = next
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, fmt.Errorf("%s not found", pointer)
+			}
+			value = v[i]
+		default:
+			return nil, fmt.Errorf("%s not found", pointer)
+		}
+	}
+	return value, nil
+}
+
+// setPointer sets the value at a JSON pointer within a decoded JSON or YAML
+// value, returning the updated value.
+func setPointer(target any, pointer string, value any) (any, error) {
+	tokens := splitPointer(pointer)
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	parent, err := resolvePointer(target, "/"+strings.Join(escapeTokens(tokens[:len(tokens)-1]), "/"))
+	if err != nil {
+		return nil, err
+	}
+
+	last := tokens[len(tokens)-1]
+	switch p := parent.(type) {
+	case map[string]any:
+		p[last] = value
+	case []any:
+		i, err := strconv.Atoi(last)
+		if err != nil || i < 0 || i >= len(p) {
+			return nil, fmt.Errorf("%s not found", pointer)
+		}
+		p[i] = value
+	default:
+		return nil, fmt.Errorf("%s not found", pointer)
+	}
+
+	return target, nil
+}
+
+func escapeTokens(tokens []string) []string {
+	escaped := make([]string, len(tokens))
+	for i, token := range tokens {
+		escaped[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+	}
+	return escaped
+}
+
+// stringify renders a value as it appears in a URL, header or string.
+func stringify(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]any, []any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
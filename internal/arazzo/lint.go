@@ -0,0 +1,541 @@
+package arazzo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/speakeasy-api/openapi/arazzo"
+	"github.com/speakeasy-api/openapi/arazzo/criterion"
+	"github.com/speakeasy-api/openapi/expression"
+	"github.com/speakeasy-api/openapi/jsonschema/oas3"
+	"github.com/speakeasy-api/openapi/openapi"
+	"github.com/speakeasy-api/openapi/validation"
+	"gopkg.in/yaml.v3"
+)
+
+// Rules reported by the checks against the source descriptions.
+const (
+	RuleSourceUnavailable     = "semantic-source-unavailable"
+	RuleOperationNotFound     = "semantic-operation-not-found"
+	RuleUnknownParameter      = "semantic-unknown-parameter"
+	RuleMissingParameter      = "semantic-missing-parameter"
+	RuleInvalidRequestBody    = "semantic-invalid-request-body"
+	RuleInvalidResponseField  = "semantic-invalid-response-field"
+	RuleUndefinedOutput       = "semantic-undefined-output"
+	RuleUndefinedInput        = "semantic-undefined-input"
+	RuleUnknownStepOrWorkflow = "semantic-unknown-reference"
+)
+
+// Lint validates an Arazzo document against the Arazzo specification and,
+// if it is valid, checks its steps against the OpenAPI documents of its
+// source descriptions. Source descriptions are loaded as in Execute; if they
+// can't be, as remote ones can't in offline mode, a warning is reported and
+// only the structural checks run.
+func Lint(ctx context.Context, file, schema string, sources map[string]string) ([]error, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	doc, validationErrors, err := arazzo.Unmarshal(ctx, f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal file: %w", err)
+	}
+	if len(validationErrors) > 0 {
+		return validationErrors, nil
+	}
+
+	for name := range sources {
+		if doc.SourceDescriptions.Find(name) == nil {
+			return nil, fmt.Errorf("source description %s not found in %s", name, file)
+		}
+	}
+
+	loaded, err := loadSources(ctx, &http.Client{Timeout: DefaultRequestTimeout}, doc, filepath.Dir(file), schema, sources)
+	if err != nil {
+		// Sources named explicitly must load
+		if schema != "" || len(sources) > 0 {
+			return nil, err
+		}
+		return []error{validation.NewValidationError(validation.SeverityWarning, RuleSourceUnavailable, fmt.Errorf("skipped checks against the source descriptions: %w", err), doc.GetRootNode())}, nil
+	}
+
+	return lintSemantics(doc, loaded), nil
+}
+
+// linter checks the workflows of a document against its sources.
+type linter struct {
+	doc     *arazzo.Arazzo
+	sources []*source
+	errs    []error
+}
+
+type model interface {
+	GetRootNode() *yaml.Node
+	GetPropertyNode(prop string) *yaml.Node
+}
+
+func lintSemantics(doc *arazzo.Arazzo, sources []*source) []error {
+	l := &linter{doc: doc, sources: sources}
+	for _, w := range doc.Workflows {
+		l.lintWorkflow(w)
+	}
+	return l.errs
+}
+
+func (l *linter) report(severity validation.Severity, rule string, m model, prop string, format string, args ...any) {
+	node := m.GetRootNode()
+	if prop != "" {
+		if n := m.GetPropertyNode(prop); n != nil {
+			node = n
+		}
+	}
+	l.errs = append(l.errs, validation.NewValidationError(severity, rule, fmt.Errorf(format, args...), node))
+}
+
+func (l *linter) lintWorkflow(w *arazzo.Workflow) {
+	for _, p := range w.Parameters {
+		if param := p.Get(l.doc.Components); param != nil {
+			l.lintExpressions(w, nil, nil, param, param.Value)
+		}
+	}
+
+	for _, step := range w.Steps {
+		l.lintStep(w, step)
+	}
+
+	for name, expr := range w.Outputs.All() {
+		l.lintExpression(w, nil, nil, w, expr, fmt.Sprintf("output %s", name))
+	}
+}
+
+func (l *linter) lintStep(w *arazzo.Workflow, step *arazzo.Step) {
+	var op *operation
+	switch {
+	case step.OperationID != nil:
+		found, err := findOperationByID(l.sources, string(*step.OperationID))
+		if err != nil {
+			l.report(validation.SeverityError, RuleOperationNotFound, step, "OperationID", "step %s: %s", step.StepID, err)
+		}
+		op = found
+	case step.OperationPath != nil:
+		found, err := findOperationByPath(l.sources, string(*step.OperationPath))
+		if err != nil {
+			l.report(validation.SeverityError, RuleOperationNotFound, step, "OperationPath", "step %s: %s", step.StepID, err)
+		}
+		op = found
+	}
+
+	params := slices.Concat(w.Parameters, step.Parameters)
+	if op != nil {
+		l.lintParameters(step, op, params)
+		l.lintRequestBody(step, op)
+	}
+
+	for _, p := range step.Parameters {
+		param := p.Get(l.doc.Components)
+		if param == nil {
+			continue
+		}
+		value := param.Value
+		if p.Value != nil {
+			value = p.Value
+		}
+		l.lintExpressions(w, step, op, param, value)
+	}
+	if rb := step.RequestBody; rb != nil {
+		l.lintExpressions(w, step, op, rb, rb.Payload)
+		for _, replacement := range rb.Replacements {
+			l.lintExpressions(w, step, op, replacement, replacement.Value)
+		}
+	}
+	for _, c := range step.SuccessCriteria {
+		l.lintCriterion(w, step, op, c)
+	}
+	for _, a := range step.OnSuccess {
+		if action := a.Get(l.doc.Components); action != nil {
+			for i := range action.Criteria {
+				l.lintCriterion(w, step, op, &action.Criteria[i])
+			}
+		}
+	}
+	for _, a := range step.OnFailure {
+		if action := a.Get(l.doc.Components); action != nil {
+			for i := range action.Criteria {
+				l.lintCriterion(w, step, op, &action.Criteria[i])
+			}
+		}
+	}
+	for name, expr := range step.Outputs.All() {
+		l.lintExpression(w, step, op, step, expr, fmt.Sprintf("output %s", name))
+	}
+}
+
+// lintParameters checks the parameters of a step are declared by its
+// operation, and that required parameters are provided.
+func (l *linter) lintParameters(step *arazzo.Step, op *operation, params []*arazzo.ReusableParameter) {
+	provided := map[string]bool{}
+	for _, p := range params {
+		param := p.Get(l.doc.Components)
+		if param == nil {
+			continue
+		}
+
+		in, declared := op.parameterLocation(param.Name)
+		switch {
+		case !declared:
+			// Workflow parameters apply to every step, so only those of the
+			// step itself must be declared
+			if slices.Contains(step.Parameters, p) {
+				l.report(validation.SeverityError, RuleUnknownParameter, param, "", "step %s: operation %s has no parameter %s", step.StepID, op, param.Name)
+			}
+		case param.In != nil && *param.In != in:
+			l.report(validation.SeverityError, RuleUnknownParameter, param, "In", "step %s: parameter %s of operation %s is in %s, not %s", step.StepID, param.Name, op, in, *param.In)
+		default:
+			provided[string(in)+":"+param.Name] = true
+		}
+	}
+
+	for _, p := range slices.Concat(op.item.Parameters, op.op.GetParameters()) {
+		param := p.GetObject()
+		if param == nil || !param.GetRequired() || provided[string(param.GetIn())+":"+param.GetName()] {
+			continue
+		}
+		// Path parameters can't be left out, others may be sent by a client
+		// through other means, such as global headers
+		severity := validation.SeverityWarning
+		if param.GetIn() == openapi.ParameterInPath {
+			severity = validation.SeverityError
+		}
+		l.report(severity, RuleMissingParameter, step, "", "step %s: required %s parameter %s of operation %s is not set", step.StepID, param.GetIn(), param.GetName(), op)
+	}
+}
+
+// lintRequestBody checks the payload of a step against the request body
+// schema of its operation. Only literal payloads are checked.
+func (l *linter) lintRequestBody(step *arazzo.Step, op *operation) {
+	requestBody := op.op.GetRequestBody().GetObject()
+	rb := step.RequestBody
+
+	if rb == nil {
+		if requestBody != nil && requestBody.GetRequired() {
+			l.report(validation.SeverityError, RuleInvalidRequestBody, step, "", "step %s: operation %s requires a request body", step.StepID, op)
+		}
+		return
+	}
+	if requestBody == nil {
+		l.report(validation.SeverityError, RuleInvalidRequestBody, rb, "", "step %s: operation %s has no request body", step.StepID, op)
+		return
+	}
+
+	content := requestBody.GetContent()
+	var mediaType *openapi.MediaType
+	if rb.ContentType != nil {
+		var ok bool
+		if mediaType, ok = content.Get(*rb.ContentType); !ok {
+			l.report(validation.SeverityError, RuleInvalidRequestBody, rb, "ContentType", "step %s: operation %s does not accept %s request bodies", step.StepID, op, *rb.ContentType)
+			return
+		}
+	} else {
+		for _, mt := range content.All() {
+			mediaType = mt
+			break
+		}
+	}
+
+	payload, expr, err := expression.GetValueOrExpressionValue(rb.Payload)
+	if err != nil || expr != nil || payload == nil {
+		return
+	}
+	var value any
+	if err := payload.Decode(&value); err != nil {
+		return
+	}
+	if _, ok := value.(string); ok {
+		return
+	}
+
+	// Replaced fields are provided even if missing from the payload
+	replaced := map[string]bool{}
+	for _, replacement := range rb.Replacements {
+		replaced[string(replacement.Target)] = true
+	}
+
+	for _, problem := range checkPayload(value, resolveSchema(mediaType.GetSchema()), "", replaced) {
+		l.report(validation.SeverityError, RuleInvalidRequestBody, rb, "Payload", "step %s: request body %s", step.StepID, problem)
+	}
+}
+
+// lintCriterion checks the runtime expressions of a criterion.
+func (l *linter) lintCriterion(w *arazzo.Workflow, step *arazzo.Step, op *operation, c *criterion.Criterion) {
+	if c.Context != nil {
+		l.lintExpression(w, step, op, c, *c.Context, "criterion context")
+	}
+
+	condition := strings.TrimSpace(c.Condition)
+	if c.Type.GetType() != criterion.CriterionTypeSimple || (c.Context != nil && !strings.HasPrefix(condition, "$")) {
+		return
+	}
+	tokens, err := tokenize(condition)
+	if err != nil {
+		return
+	}
+	for _, t := range tokens {
+		if t.kind == tokenOperand && strings.HasPrefix(t.value, "$") {
+			l.lintExpression(w, step, op, c, expression.Expression(t.value), "criterion")
+		}
+	}
+}
+
+// lintExpressions checks the runtime expressions in a value, including
+// those embedded in strings.
+func (l *linter) lintExpressions(w *arazzo.Workflow, step *arazzo.Step, op *operation, m model, node *yaml.Node) {
+	var walk func(n *yaml.Node)
+	walk = func(n *yaml.Node) {
+		if n == nil {
+			return
+		}
+		if n.Kind == yaml.ScalarNode {
+			if expr := expression.Expression(n.Value); expr.IsExpression() {
+				l.lintExpression(w, step, op, m, expr, "value")
+				return
+			}
+			for _, embedded := range expression.ExtractExpressions(n.Value) {
+				if embedded.IsExpression() {
+					l.lintExpression(w, step, op, m, embedded, "value")
+				}
+			}
+		}
+		for _, child := range n.Content {
+			walk(child)
+		}
+	}
+	walk(node)
+}
+
+// lintExpression checks an expression refers to steps, workflows, inputs and
+// response fields that exist.
+func (l *linter) lintExpression(w *arazzo.Workflow, step *arazzo.Step, op *operation, m model, expr expression.Expression, where string) {
+	typ, reference, parts, pointer := expr.GetParts()
+
+	prefix := fmt.Sprintf("workflow %s", w.WorkflowID)
+	if step != nil {
+		prefix = fmt.Sprintf("step %s", step.StepID)
+	}
+
+	switch typ {
+	case expression.ExpressionTypeSteps:
+		target := w.Steps.Find(reference)
+		if target == nil {
+			l.report(validation.SeverityError, RuleUnknownStepOrWorkflow, m, "", "%s: %s %s refers to unknown step %s", prefix, where, expr, reference)
+			return
+		}
+		if len(parts) < 2 || parts[0] != "outputs" {
+			return
+		}
+		name := strings.Join(parts[1:], ".")
+		if _, ok := target.Outputs.Get(name); !ok {
+			l.report(validation.SeverityError, RuleUndefinedOutput, m, "", "%s: %s %s refers to output %s, which step %s does not define", prefix, where, expr, name, reference)
+		}
+	case expression.ExpressionTypeWorkflows:
+		target := l.doc.Workflows.Find(reference)
+		if target == nil {
+			l.report(validation.SeverityError, RuleUnknownStepOrWorkflow, m, "", "%s: %s %s refers to unknown workflow %s", prefix, where, expr, reference)
+			return
+		}
+		if len(parts) < 2 || parts[0] != "outputs" {
+			return
+		}
+		name := strings.Join(parts[1:], ".")
+		if _, ok := target.Outputs.Get(name); !ok {
+			l.report(validation.SeverityError, RuleUndefinedOutput, m, "", "%s: %s %s refers to output %s, which workflow %s does not define", prefix, where, expr, name, reference)
+		}
+	case expression.ExpressionTypeInputs:
+		inputs := resolveSchema(w.Inputs)
+		if inputs == nil || inputs.GetProperties().Len() == 0 {
+			return
+		}
+		if _, ok := inputs.GetProperties().Get(reference); !ok {
+			l.report(validation.SeverityError, RuleUndefinedInput, m, "", "%s: %s %s refers to input %s, which workflow %s does not define", prefix, where, expr, reference, w.WorkflowID)
+		}
+	case expression.ExpressionTypeResponse:
+		if op == nil || reference != expression.ReferenceTypeBody || pointer == "" {
+			return
+		}
+		schemas := responseSchemas(op)
+		if len(schemas) == 0 {
+			return
+		}
+		tokens := splitPointer(string(pointer))
+		if !slices.ContainsFunc(schemas, func(s *oas3.Schema) bool { return schemaHasPath(s, tokens) }) {
+			l.report(validation.SeverityError, RuleInvalidResponseField, m, "", "%s: %s %s refers to %s, which is not in any response of operation %s", prefix, where, expr, pointer, op)
+		}
+	}
+}
+
+// responseSchemas returns the schemas of every response of an operation.
+func responseSchemas(op *operation) []*oas3.Schema {
+	var schemas []*oas3.Schema
+	responses := op.op.GetResponses()
+
+	all := []*openapi.ReferencedResponse{responses.GetDefault()}
+	for _, response := range responses.All() {
+		all = append(all, response)
+	}
+
+	for _, response := range all {
+		for _, mediaType := range response.GetObject().GetContent().All() {
+			if s := resolveSchema(mediaType.GetSchema()); s != nil {
+				schemas = append(schemas, s)
+			}
+		}
+	}
+	return schemas
+}
+
+func resolveSchema(js *oas3.JSONSchema[oas3.Referenceable]) *oas3.Schema {
+	if js == nil {
+		return nil
+	}
+	resolved := js.GetResolvedSchema()
+	if resolved == nil {
+		return nil
+	}
+	return resolved.GetSchema()
+}
+
+// subschemas returns the schemas a value must or may also match.
+func subschemas(s *oas3.Schema) []*oas3.Schema {
+	var schemas []*oas3.Schema
+	for _, js := range slices.Concat(s.GetAllOf(), s.GetOneOf(), s.GetAnyOf()) {
+		if sub := resolveSchema(js); sub != nil {
+			schemas = append(schemas, sub)
+		}
+	}
+	return schemas
+}
+
+// isOpen reports whether an object schema allows properties it doesn't
+// declare. Schemas that declare properties are treated as closed unless they
+// set additionalProperties, so renamed fields are caught.
+func isOpen(s *oas3.Schema) bool {
+	if len(s.GetOneOf()) > 0 || len(s.GetAnyOf()) > 0 {
+		return true
+	}
+	additional := s.GetAdditionalProperties()
+	if additional == nil {
+		return s.GetProperties().Len() == 0 && len(s.GetAllOf()) == 0
+	}
+	if additional.IsBool() {
+		return *additional.GetBool()
+	}
+	return true
+}
+
+// property finds a property in a schema or the schemas it is composed of.
+func property(s *oas3.Schema, name string) (*oas3.Schema, bool) {
+	if js, ok := s.GetProperties().Get(name); ok {
+		return resolveSchema(js), true
+	}
+	for _, sub := range subschemas(s) {
+		if p, ok := property(sub, name); ok {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+func required(s *oas3.Schema) []string {
+	names := slices.Clone(s.GetRequired())
+	for _, js := range s.GetAllOf() {
+		if sub := resolveSchema(js); sub != nil {
+			names = append(names, required(sub)...)
+		}
+	}
+	return names
+}
+
+func schemaHasPath(s *oas3.Schema, tokens []string) bool {
+	if s == nil || len(tokens) == 0 {
+		return true
+	}
+
+	if p, ok := property(s, tokens[0]); ok {
+		return schemaHasPath(p, tokens[1:])
+	}
+	if items := resolveSchema(s.GetItems()); items != nil {
+		if _, err := strconv.Atoi(tokens[0]); err == nil {
+			return schemaHasPath(items, tokens[1:])
+		}
+	}
+	if additional := s.GetAdditionalProperties(); additional != nil && additional.IsSchema() {
+		return schemaHasPath(resolveSchema(additional), tokens[1:])
+	}
+	return isOpen(s) && s.GetItems() == nil
+}
+
+// checkPayload checks a payload has no fields its schema doesn't declare and
+// has every required field, returning the problems found.
+func checkPayload(value any, s *oas3.Schema, path string, replaced map[string]bool) []string {
+	if s == nil {
+		return nil
+	}
+
+	var problems []string
+	switch v := value.(type) {
+	case map[string]any:
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+
+		for _, name := range names {
+			child := path + "/" + escapeTokens([]string{name})[0]
+			p, ok := property(s, name)
+			if !ok {
+				if additional := s.GetAdditionalProperties(); additional != nil && additional.IsSchema() {
+					problems = append(problems, checkPayload(v[name], resolveSchema(additional), child, replaced)...)
+				} else if !isOpen(s) {
+					problems = append(problems, fmt.Sprintf("has field %s, which the schema does not define", child))
+				}
+				continue
+			}
+			problems = append(problems, checkPayload(v[name], p, child, replaced)...)
+		}
+
+		for _, name := range required(s) {
+			child := path + "/" + escapeTokens([]string{name})[0]
+			if _, ok := v[name]; !ok && !replaced[child] {
+				problems = append(problems, fmt.Sprintf("is missing required field %s", child))
+			}
+		}
+	case []any:
+		items := resolveSchema(s.GetItems())
+		for i, item := range v {
+			problems = append(problems, checkPayload(item, items, fmt.Sprintf("%s/%d", path, i), replaced)...)
+		}
+	}
+	return problems
+}
+
+// splitBySeverity splits validation errors into errors and warnings.
+func splitBySeverity(all []error) (errs, warnings []error) {
+	for _, err := range all {
+		var vErr *validation.Error
+		if errors.As(err, &vErr) && vErr.Severity != validation.SeverityError {
+			warnings = append(warnings, err)
+			continue
+		}
+		errs = append(errs, err)
+	}
+	return errs, warnings
+}
@@ -0,0 +1,84 @@
+package arazzo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/speakeasy-api/openapi/validation"
+	"github.com/speakeasy-api/speakeasy/internal/arazzo"
+	"github.com/speakeasy-api/speakeasy/internal/offline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	t.Parallel()
+
+	errs, err := arazzo.Lint(context.Background(), "testdata/pets.arazzo.yaml", "openapi.yaml", nil)
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+}
+
+func TestLintBrokenWorkflow(t *testing.T) {
+	t.Parallel()
+
+	errs, err := arazzo.Lint(context.Background(), "testdata/broken.arazzo.yaml", "", nil)
+	require.NoError(t, err)
+
+	type issue struct {
+		severity validation.Severity
+		rule     string
+		message  string
+	}
+	var issues []issue
+	for _, err := range errs {
+		var vErr *validation.Error
+		require.True(t, errors.As(err, &vErr), err.Error())
+		issues = append(issues, issue{vErr.Severity, vErr.Rule, vErr.UnderlyingError.Error()})
+	}
+
+	assert.ElementsMatch(t, []issue{
+		{validation.SeverityError, arazzo.RuleOperationNotFound, "step list: operation listPets not found in the source descriptions"},
+		{validation.SeverityError, arazzo.RuleInvalidRequestBody, "step create: request body has field /nickname, which the schema does not define"},
+		{validation.SeverityError, arazzo.RuleUndefinedInput, "step create: value $inputs.age refers to input age, which workflow lifecycle does not define"},
+		{validation.SeverityError, arazzo.RuleInvalidResponseField, "step create: criterion $response.body#/owner refers to /owner, which is not in any response of operation POST /pets"},
+		{validation.SeverityError, arazzo.RuleUnknownParameter, "step get: operation GET /pets/{petId} has no parameter petID"},
+		{validation.SeverityError, arazzo.RuleMissingParameter, "step get: required path parameter petId of operation GET /pets/{petId} is not set"},
+		{validation.SeverityError, arazzo.RuleUndefinedOutput, "step get: value $steps.create.outputs.petId refers to output petId, which step create does not define"},
+		{validation.SeverityError, arazzo.RuleUnknownParameter, "step delete: parameter petId of operation DELETE /pets/{petId} is in path, not query"},
+		{validation.SeverityError, arazzo.RuleMissingParameter, "step delete: required path parameter petId of operation DELETE /pets/{petId} is not set"},
+		{validation.SeverityError, arazzo.RuleUnknownStepOrWorkflow, "step delete: value $steps.nope.outputs.id refers to unknown step nope"},
+	}, issues)
+}
+
+func TestLintUnavailableSource(t *testing.T) {
+	t.Parallel()
+
+	errs, err := arazzo.Lint(context.Background(), "testdata/missing-source.arazzo.yaml", "", nil)
+	require.NoError(t, err)
+	require.Len(t, errs, 1)
+
+	var vErr *validation.Error
+	require.True(t, errors.As(errs[0], &vErr))
+	assert.Equal(t, validation.SeverityWarning, vErr.Severity)
+	assert.Equal(t, arazzo.RuleSourceUnavailable, vErr.Rule)
+
+	// Sources named explicitly must load
+	_, err = arazzo.Lint(context.Background(), "testdata/missing-source.arazzo.yaml", "missing.yaml", nil)
+	assert.ErrorContains(t, err, "failed to load source description pets")
+}
+
+func TestLintRemoteSourceOffline(t *testing.T) {
+	t.Setenv("SPEAKEASY_OFFLINE", "true")
+
+	errs, err := arazzo.Lint(context.Background(), "testdata/pets.arazzo.yaml", "", nil)
+	require.NoError(t, err)
+	require.Len(t, errs, 1)
+
+	var vErr *validation.Error
+	require.True(t, errors.As(errs[0], &vErr))
+	assert.Equal(t, validation.SeverityWarning, vErr.Severity)
+	assert.Equal(t, arazzo.RuleSourceUnavailable, vErr.Rule)
+	assert.ErrorIs(t, vErr.UnderlyingError, offline.ErrOffline)
+}
@@ -0,0 +1,165 @@
+package arazzo
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Report is the result of running the workflows of an Arazzo document.
+type Report struct {
+	File      string            `json:"file"`
+	BaseURL   string            `json:"baseUrl,omitempty"`
+	Passed    bool              `json:"passed"`
+	Workflows []*WorkflowResult `json:"workflows"`
+	// Duration is in seconds.
+	Duration float64 `json:"duration"`
+}
+
+type WorkflowResult struct {
+	WorkflowID string         `json:"workflowId"`
+	Passed     bool           `json:"passed"`
+	Error      string         `json:"error,omitempty"`
+	Steps      []*StepResult  `json:"steps"`
+	Outputs    map[string]any `json:"outputs,omitempty"`
+	Duration   float64        `json:"duration"`
+
+	inputs map[string]any
+}
+
+type StepResult struct {
+	StepID string `json:"stepId"`
+	// Operation is the method and path called, for steps calling an
+	// operation.
+	Operation string `json:"operation,omitempty"`
+	// WorkflowID is the workflow called, for steps calling a workflow.
+	WorkflowID string         `json:"workflowId,omitempty"`
+	StatusCode int            `json:"statusCode,omitempty"`
+	Passed     bool           `json:"passed"`
+	Attempts   int            `json:"attempts"`
+	Failures   []string       `json:"failures,omitempty"`
+	Outputs    map[string]any `json:"outputs,omitempty"`
+	Duration   float64        `json:"duration"`
+}
+
+func (w *WorkflowResult) fail(err error) {
+	w.Passed = false
+	w.Error = err.Error()
+}
+
+// failure describes why the workflow failed.
+func (w *WorkflowResult) failure() string {
+	if w.Error != "" {
+		return w.Error
+	}
+	for _, step := range w.Steps {
+		if !step.Passed {
+			return fmt.Sprintf("step %s: %s", step.StepID, strings.Join(step.Failures, "; "))
+		}
+	}
+	return "unknown failure"
+}
+
+// Failed returns the number of workflows that failed.
+func (r *Report) Failed() int {
+	failed := 0
+	for _, w := range r.Workflows {
+		if !w.Passed {
+			failed++
+		}
+	}
+	return failed
+}
+
+func (r *Report) WriteJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(r)
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the report as JUnit XML, with a test suite per workflow
+// and a test case per step.
+func (r *Report) WriteJUnit(w io.Writer) error {
+	suites := junitTestSuites{Name: r.File, Time: seconds(r.Duration)}
+
+	for _, workflow := range r.Workflows {
+		suite := junitTestSuite{Name: workflow.WorkflowID, Time: seconds(workflow.Duration)}
+
+		for _, step := range workflow.Steps {
+			testCase := junitTestCase{Name: step.StepID, ClassName: workflow.WorkflowID, Time: seconds(step.Duration)}
+			if !step.Passed {
+				testCase.Failure = &junitFailure{
+					Message: fmt.Sprintf("step %s failed", step.StepID),
+					Text:    strings.Join(step.Failures, "\n"),
+				}
+			}
+			suite.Cases = append(suite.Cases, testCase)
+		}
+
+		// Failures outside of any step, such as a failed dependency
+		if workflow.Error != "" {
+			suite.Cases = append(suite.Cases, junitTestCase{
+				Name:      "workflow",
+				ClassName: workflow.WorkflowID,
+				Time:      seconds(0),
+				Failure:   &junitFailure{Message: workflow.Error, Text: workflow.Error},
+			})
+		}
+
+		for _, testCase := range suite.Cases {
+			suite.Tests++
+			if testCase.Failure != nil {
+				suite.Failures++
+			}
+		}
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	if err := e.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func seconds(d float64) string {
+	return fmt.Sprintf("%.3f", d)
+}
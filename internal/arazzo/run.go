@@ -0,0 +1,104 @@
+package arazzo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/speakeasy-api/speakeasy/internal/charm/styles"
+	"github.com/speakeasy-api/speakeasy/internal/log"
+)
+
+type ReportFormat string
+
+const (
+	// ReportFormatAuto infers the format from the extension of the report.
+	ReportFormatAuto  ReportFormat = "auto"
+	ReportFormatJUnit ReportFormat = "junit"
+	ReportFormatJSON  ReportFormat = "json"
+)
+
+// Run runs the workflows of an Arazzo document, logging each step, and writes
+// a report to reportPath if set. The format is inferred from the extension of
+// reportPath when not set.
+func Run(ctx context.Context, opts RunOptions, reportPath string, format ReportFormat) error {
+	logger := log.From(ctx)
+	logger.Infof("Running Arazzo workflows from %s...\n", opts.File)
+
+	opts.OnStep = func(workflowID string, step *StepResult) {
+		target := step.Operation
+		if step.WorkflowID != "" {
+			target = "workflow " + step.WorkflowID
+		}
+		if step.StatusCode != 0 {
+			target = fmt.Sprintf("%s → %d", target, step.StatusCode)
+		}
+
+		if step.Passed {
+			logger.Println(fmt.Sprintf("%s %s.%s %s", styles.Success.Render("✓"), workflowID, step.StepID, styles.Dimmed.Render(target)))
+			return
+		}
+		logger.Println(fmt.Sprintf("%s %s.%s %s", styles.Error.Render("✖"), workflowID, step.StepID, styles.Dimmed.Render(target)))
+		for _, failure := range step.Failures {
+			logger.Println(styles.Error.Render("    - " + failure))
+		}
+	}
+
+	report, err := Execute(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	for _, w := range report.Workflows {
+		if w.Error != "" {
+			logger.Println(fmt.Sprintf("%s %s %s", styles.Error.Render("✖"), w.WorkflowID, styles.Error.Render(w.Error)))
+		}
+	}
+
+	if reportPath != "" {
+		if err := writeReport(report, reportPath, format); err != nil {
+			return err
+		}
+		logger.Infof("Report written to %s", reportPath)
+	}
+
+	summary := fmt.Sprintf("%d workflows, %d failed (%.2fs)", len(report.Workflows), report.Failed(), report.Duration)
+	if !report.Passed {
+		logger.Println(styles.RenderErrorMessage("Arazzo workflows failed", lipgloss.Center, summary))
+		return fmt.Errorf("%d of %d workflows failed", report.Failed(), len(report.Workflows))
+	}
+
+	logger.Println(styles.RenderSuccessMessage("Arazzo workflows passed ✓", summary))
+	return nil
+}
+
+func writeReport(report *Report, path string, format ReportFormat) error {
+	if format == "" || format == ReportFormatAuto {
+		format = ReportFormatJUnit
+		if strings.EqualFold(filepath.Ext(path), ".json") {
+			format = ReportFormatJSON
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+	defer f.Close()
+
+	switch format {
+	case ReportFormatJSON:
+		err = report.WriteJSON(f)
+	case ReportFormatJUnit:
+		err = report.WriteJUnit(f)
+	default:
+		return fmt.Errorf("unsupported report format %s", format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
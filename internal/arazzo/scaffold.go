@@ -0,0 +1,503 @@
+package arazzo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/speakeasy-api/openapi/arazzo"
+	"github.com/speakeasy-api/openapi/arazzo/criterion"
+	"github.com/speakeasy-api/openapi/expression"
+	"github.com/speakeasy-api/openapi/jsonschema/oas3"
+	"github.com/speakeasy-api/openapi/openapi"
+	"github.com/speakeasy-api/openapi/pointer"
+	"github.com/speakeasy-api/openapi/sequencedmap"
+	"gopkg.in/yaml.v3"
+)
+
+// crudStep is the role of an operation within a resource's workflow, in the
+// order the steps run.
+type crudStep string
+
+const (
+	crudCreate crudStep = "create"
+	crudGet    crudStep = "get"
+	crudUpdate crudStep = "update"
+	crudList   crudStep = "list"
+	crudDelete crudStep = "delete"
+)
+
+var crudOrder = []crudStep{crudCreate, crudGet, crudUpdate, crudList, crudDelete}
+
+// resource is a collection path and its item path, as in /pets and
+// /pets/{petId}.
+type resource struct {
+	collection string
+	item       string
+	// idParam is the path parameter of the item path that identifies it.
+	idParam    string
+	operations map[crudStep]*operation
+}
+
+// Scaffold derives Arazzo workflows from an OpenAPI document. Each resource
+// that can be created and then fetched, updated or deleted by id gets a
+// workflow chaining create → get → update → list → delete, linked by the id
+// in the create response. Every other operation gets a workflow of its own.
+// Payloads and parameters are filled in from schema examples, defaults and
+// types. sourceURL is the location of the document as the workflows refer to
+// it.
+func Scaffold(ctx context.Context, schemaPath, sourceURL string) (*arazzo.Arazzo, error) {
+	location := resolveLocation(".", schemaPath)
+	data, err := readLocation(ctx, http.DefaultClient, location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", schemaPath, err)
+	}
+
+	doc, _, err := openapi.Unmarshal(ctx, bytes.NewReader(data), openapi.WithSkipValidation())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", schemaPath, err)
+	}
+	if _, err := doc.ResolveAllReferences(ctx, openapi.ResolveAllOptions{OpenAPILocation: location}); err != nil {
+		return nil, fmt.Errorf("failed to resolve references in %s: %w", schemaPath, err)
+	}
+
+	name := identifier(doc.Info.Title)
+	if name == "" {
+		name = "api"
+	}
+	src := &source{name: name, location: sourceURL, doc: doc}
+
+	a := &arazzo.Arazzo{
+		Arazzo: arazzo.Version,
+		Info: arazzo.Info{
+			Title:   doc.Info.Title + " Workflows",
+			Version: "0.0.1",
+		},
+		SourceDescriptions: arazzo.SourceDescriptions{
+			{Name: name, URL: sourceURL, Type: arazzo.SourceDescriptionTypeOpenAPI},
+		},
+	}
+
+	resources, remaining := findResources(src)
+	for _, r := range resources {
+		a.Workflows = append(a.Workflows, crudWorkflow(r))
+	}
+	for _, op := range remaining {
+		a.Workflows = append(a.Workflows, singleStepWorkflow(op))
+	}
+
+	// Workflow IDs must be unique
+	seen := map[string]int{}
+	for _, w := range a.Workflows {
+		if n := seen[w.WorkflowID]; n > 0 {
+			seen[w.WorkflowID]++
+			w.WorkflowID = w.WorkflowID + strconv.Itoa(n+1)
+			continue
+		}
+		seen[w.WorkflowID] = 1
+	}
+
+	return a, nil
+}
+
+// WriteScaffold writes the workflows Scaffold derives from an OpenAPI
+// document to out, or to stdout if out is empty.
+func WriteScaffold(ctx context.Context, schemaPath, sourceURL, out string) error {
+	a, err := Scaffold(ctx, schemaPath, sourceURL)
+	if err != nil {
+		return err
+	}
+
+	if out == "" {
+		return arazzo.Marshal(ctx, a, os.Stdout)
+	}
+
+	f, err := os.Create(out)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", out, err)
+	}
+	defer f.Close()
+
+	return arazzo.Marshal(ctx, a, f)
+}
+
+// findResources groups the operations of a source into resources, returning
+// the operations that aren't part of any.
+func findResources(src *source) ([]*resource, []*operation) {
+	var all []*operation
+	for op := range iterateOperations(src) {
+		all = append(all, op)
+	}
+
+	var resources []*resource
+	byCollection := map[string]*resource{}
+	get := func(collection string) *resource {
+		if r, ok := byCollection[collection]; ok {
+			return r
+		}
+		r := &resource{collection: collection, operations: map[crudStep]*operation{}}
+		byCollection[collection] = r
+		resources = append(resources, r)
+		return r
+	}
+
+	for _, op := range all {
+		collection, param, isItem := splitItemPath(op.path)
+
+		var role crudStep
+		switch {
+		case !isItem && op.method == "post":
+			role = crudCreate
+		case !isItem && op.method == "get":
+			role = crudList
+		case isItem && op.method == "get":
+			role = crudGet
+		case isItem && (op.method == "put" || op.method == "patch"):
+			role = crudUpdate
+		case isItem && op.method == "delete":
+			role = crudDelete
+		default:
+			continue
+		}
+
+		r := get(collection)
+		if isItem {
+			if r.item != "" && r.item != op.path {
+				continue
+			}
+			r.item, r.idParam = op.path, param
+		}
+		if _, ok := r.operations[role]; !ok {
+			r.operations[role] = op
+		}
+	}
+
+	var crud []*resource
+	var used []*operation
+	for _, r := range resources {
+		_, canCreate := r.operations[crudCreate]
+		_, canGet := r.operations[crudGet]
+		_, canUpdate := r.operations[crudUpdate]
+		_, canDelete := r.operations[crudDelete]
+		if !canCreate || !(canGet || canUpdate || canDelete) {
+			continue
+		}
+		crud = append(crud, r)
+		for _, op := range r.operations {
+			used = append(used, op)
+		}
+	}
+
+	var remaining []*operation
+	for _, op := range all {
+		if !slices.Contains(used, op) {
+			remaining = append(remaining, op)
+		}
+	}
+	return crud, remaining
+}
+
+// splitItemPath splits a path ending in a parameter into its collection
+// path and parameter name.
+func splitItemPath(path string) (string, string, bool) {
+	i := strings.LastIndex(path, "/")
+	last := path[i+1:]
+	if i < 0 || !strings.HasPrefix(last, "{") || !strings.HasSuffix(last, "}") {
+		return path, "", false
+	}
+	return path[:i], strings.Trim(last, "{}"), true
+}
+
+var pathParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+func crudWorkflow(r *resource) *arazzo.Workflow {
+	resourceName := r.collection[strings.LastIndex(r.collection, "/")+1:]
+	w := &arazzo.Workflow{
+		WorkflowID: identifier(resourceName + " lifecycle"),
+		Summary:    pointer.From(fmt.Sprintf("Create, read, update, list and delete %s", resourceName)),
+	}
+
+	// Parameters of the collection path, as in /users/{userId}/pets, are
+	// inputs of the workflow
+	var inputs []string
+	for _, m := range pathParam.FindAllStringSubmatch(r.collection, -1) {
+		inputs = append(inputs, m[1])
+	}
+	if len(inputs) > 0 {
+		properties := sequencedmap.New[string, *oas3.JSONSchema[oas3.Referenceable]]()
+		for _, input := range inputs {
+			properties.Set(input, oas3.NewJSONSchemaFromSchema[oas3.Referenceable](&oas3.Schema{Type: oas3.NewTypeFromString(oas3.SchemaTypeString)}))
+		}
+		w.Inputs = oas3.NewJSONSchemaFromSchema[oas3.Referenceable](&oas3.Schema{
+			Type:       oas3.NewTypeFromString(oas3.SchemaTypeObject),
+			Properties: properties,
+			Required:   inputs,
+		})
+	}
+
+	create := r.operations[crudCreate]
+	idField := idField(create, r.idParam)
+
+	for _, role := range crudOrder {
+		op, ok := r.operations[role]
+		if !ok {
+			continue
+		}
+
+		step := newStep(string(role), op, func(name string) *yaml.Node {
+			switch {
+			case slices.Contains(inputs, name):
+				return stringNode("$inputs." + name)
+			case name == r.idParam && role != crudCreate && role != crudList:
+				return stringNode("$steps.create.outputs.id")
+			default:
+				return nil
+			}
+		})
+		if role == crudCreate {
+			step.Outputs = sequencedmap.New(sequencedmap.NewElem("id", expression.Expression("$response.body#/"+idField)))
+		}
+		w.Steps = append(w.Steps, step)
+	}
+
+	w.Outputs = sequencedmap.New(sequencedmap.NewElem("id", expression.Expression("$steps.create.outputs.id")))
+	return w
+}
+
+func singleStepWorkflow(op *operation) *arazzo.Workflow {
+	id := op.op.GetOperationID()
+	if id == "" {
+		id = identifier(op.method + " " + strings.NewReplacer("{", " by ", "}", "").Replace(op.path))
+	}
+	return &arazzo.Workflow{
+		WorkflowID: id,
+		Steps:      []*arazzo.Step{newStep("test", op, func(string) *yaml.Node { return nil })},
+	}
+}
+
+// newStep creates a step calling op, with its required parameters and
+// request body filled in from examples. linked returns the value of
+// parameters provided by the workflow, if any.
+func newStep(id string, op *operation, linked func(name string) *yaml.Node) *arazzo.Step {
+	step := &arazzo.Step{StepID: id}
+	if operationID := op.op.GetOperationID(); operationID != "" {
+		step.OperationID = pointer.From(expression.Expression(operationID))
+	} else {
+		step.OperationPath = pointer.From(expression.Expression(fmt.Sprintf("{$sourceDescriptions.%s.url}#/paths/%s/%s", op.source.name, escapeTokens([]string{op.path})[0], op.method)))
+	}
+
+	for _, p := range slices.Concat(op.item.Parameters, op.op.GetParameters()) {
+		param := p.GetObject()
+		if param == nil {
+			continue
+		}
+		value := linked(param.GetName())
+		if value == nil {
+			if !param.GetRequired() {
+				continue
+			}
+			value = param.GetExample()
+			if value == nil {
+				value = exampleNode(resolveSchema(param.GetSchema()), nil)
+			}
+		}
+		step.Parameters = append(step.Parameters, &arazzo.ReusableParameter{Object: &arazzo.Parameter{
+			Name:  param.GetName(),
+			In:    pointer.From(arazzo.In(param.GetIn())),
+			Value: value,
+		}})
+	}
+
+	if requestBody := op.op.GetRequestBody().GetObject(); requestBody != nil {
+		for contentType, mediaType := range requestBody.GetContent().All() {
+			payload := mediaType.GetExample()
+			if payload == nil {
+				payload = exampleNode(resolveSchema(mediaType.GetSchema()), nil)
+			}
+			step.RequestBody = &arazzo.RequestBody{ContentType: pointer.From(contentType), Payload: payload}
+			break
+		}
+	}
+
+	status, jsonResponse := successResponse(op)
+	step.SuccessCriteria = []*criterion.Criterion{{Condition: "$statusCode == " + status}}
+	if jsonResponse {
+		step.SuccessCriteria = append(step.SuccessCriteria, &criterion.Criterion{Condition: "$response.header.Content-Type == application/json"})
+	}
+
+	return step
+}
+
+// successResponse returns the first success status code of an operation and
+// whether it responds with JSON.
+func successResponse(op *operation) (string, bool) {
+	for code, response := range op.op.GetResponses().All() {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		_, isJSON := response.GetObject().GetContent().Get("application/json")
+		return strings.ToUpper(code), isJSON
+	}
+	return "2XX", false
+}
+
+// idField returns the field of the create response that holds the id of the
+// new item, preferring one named after the item's path parameter.
+func idField(create *operation, idParam string) string {
+	status, _ := successResponse(create)
+	response, ok := create.op.GetResponses().Get(status)
+	if !ok {
+		return "id"
+	}
+
+	var properties []string
+	for _, mediaType := range response.GetObject().GetContent().All() {
+		for name := range resolveSchema(mediaType.GetSchema()).GetProperties().Keys() {
+			properties = append(properties, name)
+		}
+	}
+
+	for _, candidate := range []string{idParam, "id"} {
+		for _, name := range properties {
+			if strings.EqualFold(name, candidate) {
+				return name
+			}
+		}
+	}
+	for _, name := range properties {
+		if strings.HasSuffix(strings.ToLower(name), "id") {
+			return name
+		}
+	}
+	return "id"
+}
+
+// exampleNode returns an example value for a schema, preferring its example,
+// default, const or first enum value, and otherwise built from its type.
+// Read-only properties are left out, as examples are request payloads, and so
+// are properties of schemas that are already being expanded, so recursive
+// schemas terminate.
+func exampleNode(s *oas3.Schema, expanding []*oas3.Schema) *yaml.Node {
+	if s == nil {
+		return stringNode("<value>")
+	}
+
+	if example := s.GetExample(); example != nil {
+		return example
+	}
+	if examples := s.GetExamples(); len(examples) > 0 {
+		return examples[0]
+	}
+	if def := s.GetDefault(); def != nil {
+		return def
+	}
+	if c := s.GetConst(); c != nil {
+		return c
+	}
+	if enum := s.GetEnum(); len(enum) > 0 {
+		return enum[0]
+	}
+
+	for _, js := range slices.Concat(s.GetOneOf(), s.GetAnyOf()) {
+		if sub := resolveSchema(js); sub != nil {
+			return exampleNode(sub, expanding)
+		}
+	}
+
+	types := s.GetType()
+	switch {
+	case slices.Contains(types, oas3.SchemaTypeObject) || s.GetProperties().Len() > 0 || len(s.GetAllOf()) > 0:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		if !slices.Contains(expanding, s) {
+			addProperties(node, s, append(expanding, s))
+		}
+		return node
+	case slices.Contains(types, oas3.SchemaTypeArray):
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		if items := resolveSchema(s.GetItems()); !slices.Contains(expanding, items) {
+			node.Content = append(node.Content, exampleNode(items, expanding))
+		}
+		return node
+	case slices.Contains(types, oas3.SchemaTypeInteger):
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: "1"}
+	case slices.Contains(types, oas3.SchemaTypeNumber):
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: "1.5"}
+	case slices.Contains(types, oas3.SchemaTypeBoolean):
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"}
+	}
+
+	switch s.GetFormat() {
+	case "date-time":
+		return stringNode("2025-01-01T00:00:00Z")
+	case "date":
+		return stringNode("2025-01-01")
+	case "email":
+		return stringNode("user@example.com")
+	case "uuid":
+		return stringNode("3fa85f64-5717-4562-b3fc-2c963f66afa6")
+	case "uri", "url":
+		return stringNode("https://example.com")
+	case "binary":
+		// Files, as written by generated test workflows
+		return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{stringNode(""), stringNode("example.file")}}
+	}
+	return stringNode("<value>")
+}
+
+// addProperties adds example values for the properties of an object schema
+// and the schemas it is composed of.
+func addProperties(node *yaml.Node, s *oas3.Schema, expanding []*oas3.Schema) {
+	for _, js := range s.GetAllOf() {
+		if sub := resolveSchema(js); sub != nil {
+			addProperties(node, sub, expanding)
+		}
+	}
+
+	for name, js := range s.GetProperties().All() {
+		property := resolveSchema(js)
+		if property.GetReadOnly() || slices.Contains(expanding, property) {
+			continue
+		}
+		exists := false
+		for i := 0; i < len(node.Content); i += 2 {
+			if node.Content[i].Value == name {
+				exists = true
+			}
+		}
+		if exists {
+			continue
+		}
+		node.Content = append(node.Content, stringNode(name), exampleNode(property, expanding))
+	}
+}
+
+func stringNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// identifier converts text such as "pets lifecycle" or "Pet Store API" into
+// a camelCase identifier.
+func identifier(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for i, word := range words {
+		runes := []rune(word)
+		if i == 0 {
+			runes[0] = unicode.ToLower(runes[0])
+		} else {
+			runes[0] = unicode.ToUpper(runes[0])
+		}
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
@@ -0,0 +1,33 @@
+package arazzo_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/speakeasy-api/speakeasy/internal/arazzo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScaffold(t *testing.T) {
+	t.Parallel()
+
+	schema, err := filepath.Abs("testdata/scaffold.yaml")
+	require.NoError(t, err)
+
+	out := filepath.Join(t.TempDir(), "tests.arazzo.yaml")
+	require.NoError(t, arazzo.WriteScaffold(context.Background(), "testdata/scaffold.yaml", "scaffold.yaml", out))
+
+	actual, err := os.ReadFile(out)
+	require.NoError(t, err)
+	expected, err := os.ReadFile("testdata/scaffold_expected.arazzo.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(actual))
+
+	// The scaffold is consistent with the document it was derived from
+	errs, err := arazzo.Lint(context.Background(), out, schema, nil)
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+}
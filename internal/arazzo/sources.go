@@ -0,0 +1,229 @@
+package arazzo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/speakeasy-api/openapi/arazzo"
+	"github.com/speakeasy-api/openapi/openapi"
+	"github.com/speakeasy-api/speakeasy/internal/offline"
+)
+
+// source is an OpenAPI document referenced by the source descriptions of an
+// Arazzo document.
+type source struct {
+	name     string
+	location string
+	doc      *openapi.OpenAPI
+}
+
+// operation is an operation of a source that a step calls.
+type operation struct {
+	source *source
+	path   string
+	method string
+	op     *openapi.Operation
+	item   *openapi.PathItem
+}
+
+func (o *operation) String() string {
+	return fmt.Sprintf("%s %s", strings.ToUpper(o.method), o.path)
+}
+
+// loadSources loads the OpenAPI source descriptions of doc. Locations are
+// resolved relative to dir, with schema and overrides taking precedence over
+// the URLs in the document.
+func loadSources(ctx context.Context, client *http.Client, doc *arazzo.Arazzo, dir, schema string, overrides map[string]string) ([]*source, error) {
+	var sources []*source
+	for _, sd := range doc.SourceDescriptions {
+		if sd.Type != arazzo.SourceDescriptionTypeOpenAPI {
+			continue
+		}
+
+		location := sd.URL
+		if schema != "" {
+			location = schema
+		}
+		if override, ok := overrides[sd.Name]; ok {
+			location = override
+		}
+
+		location = resolveLocation(dir, location)
+		data, err := readLocation(ctx, client, location)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load source description %s from %s: %w (use --schema or --source to point at the OpenAPI document)", sd.Name, location, err)
+		}
+
+		openapiDoc, _, err := openapi.Unmarshal(ctx, bytes.NewReader(data), openapi.WithSkipValidation())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse source description %s: %w", sd.Name, err)
+		}
+		if _, err := openapiDoc.ResolveAllReferences(ctx, openapi.ResolveAllOptions{OpenAPILocation: location}); err != nil {
+			return nil, fmt.Errorf("failed to resolve references in source description %s: %w", sd.Name, err)
+		}
+
+		sources = append(sources, &source{name: sd.Name, location: location, doc: openapiDoc})
+	}
+
+	return sources, nil
+}
+
+// resolveLocation resolves a file location relative to dir, leaving URLs
+// unchanged.
+func resolveLocation(dir, location string) string {
+	if isURL(location) || filepath.IsAbs(location) {
+		return location
+	}
+	if abs, err := filepath.Abs(filepath.Join(dir, location)); err == nil {
+		return abs
+	}
+	return filepath.Join(dir, location)
+}
+
+func isURL(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+func readLocation(ctx context.Context, client *http.Client, location string) ([]byte, error) {
+	if !isURL(location) {
+		return os.ReadFile(location)
+	}
+	if err := offline.RequireNetwork("fetching a remote source description"); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+	return io.ReadAll(res.Body)
+}
+
+var sourceReference = regexp.MustCompile(`^\$sourceDescriptions\.([^.]+)\.(.+)$`)
+
+// findOperationByID finds an operation by its operationId, which may be
+// qualified with a source as in $sourceDescriptions.petstore.getPet.
+func findOperationByID(sources []*source, operationID string) (*operation, error) {
+	sourceName := ""
+	if m := sourceReference.FindStringSubmatch(operationID); m != nil {
+		sourceName, operationID = m[1], m[2]
+	}
+
+	for _, s := range sources {
+		if sourceName != "" && s.name != sourceName {
+			continue
+		}
+		for op := range iterateOperations(s) {
+			if op.op.GetOperationID() == operationID {
+				return op, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("operation %s not found in the source descriptions", operationID)
+}
+
+var operationPathSource = regexp.MustCompile(`\$sourceDescriptions\.([^.}]+)\.url`)
+
+// findOperationByPath finds an operation from a reference like
+// {$sourceDescriptions.petstore.url}#/paths/~1pets~1{petId}/get.
+func findOperationByPath(sources []*source, operationPath string) (*operation, error) {
+	prefix, pointer, ok := strings.Cut(operationPath, "#")
+	if !ok {
+		return nil, fmt.Errorf("operationPath %s has no JSON pointer to an operation", operationPath)
+	}
+
+	tokens := splitPointer(pointer)
+	if len(tokens) != 3 || tokens[0] != "paths" {
+		return nil, fmt.Errorf("operationPath %s does not point at an operation", operationPath)
+	}
+	path, method := tokens[1], strings.ToLower(tokens[2])
+
+	sourceName := ""
+	if m := operationPathSource.FindStringSubmatch(prefix); m != nil {
+		sourceName = m[1]
+	}
+
+	for _, s := range sources {
+		if sourceName != "" && s.name != sourceName {
+			continue
+		}
+		for op := range iterateOperations(s) {
+			if op.path == path && op.method == method {
+				return op, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("operation %s %s not found in the source descriptions", strings.ToUpper(method), path)
+}
+
+func iterateOperations(s *source) iter.Seq[*operation] {
+	return func(yield func(*operation) bool) {
+		for path, item := range s.doc.GetPaths().All() {
+			pathItem := item.GetObject()
+			if pathItem == nil {
+				continue
+			}
+			for method, op := range pathItem.All() {
+				if !yield(&operation{source: s, path: path, method: strings.ToLower(string(method)), op: op, item: pathItem}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// parameterLocation returns where the operation expects a parameter, if it
+// declares it.
+func (o *operation) parameterLocation(name string) (arazzo.In, bool) {
+	params := slices.Concat(o.item.Parameters, o.op.GetParameters())
+	for _, p := range params {
+		param := p.GetObject()
+		if param != nil && param.GetName() == name {
+			return arazzo.In(param.GetIn()), true
+		}
+	}
+	return "", false
+}
+
+// baseURL returns the first server of the operation's source.
+func (o *operation) baseURL() string {
+	for _, servers := range [][]*openapi.Server{o.op.GetServers(), o.item.Servers, o.source.doc.GetServers()} {
+		if len(servers) > 0 && servers[0] != nil {
+			return servers[0].URL
+		}
+	}
+	return ""
+}
+
+func splitPointer(pointer string) []string {
+	pointer = strings.TrimPrefix(pointer, "/")
+	if pointer == "" {
+		return nil
+	}
+
+	tokens := strings.Split(pointer, "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/speakeasy-api/speakeasy/internal/ci/environment"
+	"github.com/speakeasy-api/speakeasy/internal/ci/fanout"
 	cigit "github.com/speakeasy-api/speakeasy/internal/ci/git"
 	"github.com/speakeasy-api/speakeasy/internal/ci/logging"
 	sharedgit "github.com/speakeasy-api/speakeasy/internal/git"
@@ -25,6 +26,7 @@ type FanoutFinalizeInputs struct {
 	CleanupPaths       string
 	PostGenerateScript string
 	CommitMessage      string
+	MergeStrategies    string
 	CleanupWorkers     bool
 	CreateRelease      bool
 	CommentTestResults bool
@@ -55,6 +57,11 @@ func FanoutFinalize(ctx context.Context, inputs FanoutFinalizeInputs) error {
 	}
 	reportsPath := resolvePathFromWorkingDirectory(reportDirInput)
 
+	mergeRules, err := fanout.ParseMergeRules(parseListInput(inputs.MergeStrategies))
+	if err != nil {
+		return err
+	}
+
 	cleanupPaths := parseListInput(inputs.CleanupPaths)
 	if len(cleanupPaths) == 0 {
 		cleanupPaths = []string{reportDirInput, ".speakeasy/logs/changes"}
@@ -87,8 +94,8 @@ func FanoutFinalize(ctx context.Context, inputs FanoutFinalizeInputs) error {
 			return fmt.Errorf("failed to resolve worker branch %s head: %w", workerBranch, err)
 		}
 
-		if _, err := runGit(repoDir, "cherry-pick", "-X", "ours", workerCommit); err != nil {
-			return fmt.Errorf("failed to cherry-pick worker commit %s from %s: %w", workerCommit, workerBranch, err)
+		if err := cherryPickWorkerCommit(g.GetRepoRoot(), workerBranch, workerCommit, mergeRules); err != nil {
+			return err
 		}
 	}
 
@@ -208,6 +215,100 @@ func FanoutFinalize(ctx context.Context, inputs FanoutFinalizeInputs) error {
 	return nil
 }
 
+// cherryPickWorkerCommit applies a worker commit onto the current branch. Files
+// every worker touches (lockfiles, RELEASES.md) are resolved with structured
+// merges; a conflict in any other file aborts the cherry-pick.
+func cherryPickWorkerCommit(repoRoot, workerBranch, workerCommit string, rules []fanout.MergeRule) error {
+	_, cherryPickErr := runGit(repoRoot, "cherry-pick", workerCommit)
+	if cherryPickErr == nil {
+		return nil
+	}
+
+	conflicted, err := conflictedFiles(repoRoot)
+	if err != nil || len(conflicted) == 0 {
+		abortCherryPick(repoRoot)
+		return fmt.Errorf("failed to cherry-pick worker commit %s from %s: %w", workerCommit, workerBranch, cherryPickErr)
+	}
+
+	var unresolved []string
+	for _, file := range conflicted {
+		strategy := fanout.StrategyFor(rules, file)
+		if strategy == fanout.MergeStrategyFail {
+			unresolved = append(unresolved, file)
+			continue
+		}
+
+		if err := resolveConflictedFile(repoRoot, file, strategy); err != nil {
+			logging.Info("failed to resolve %s with %s merge strategy: %v", file, strategy, err)
+			unresolved = append(unresolved, file)
+			continue
+		}
+		logging.Info("Resolved conflict in %s from %s using %s merge strategy", file, workerBranch, strategy)
+	}
+
+	if len(unresolved) > 0 {
+		abortCherryPick(repoRoot)
+		return fmt.Errorf("worker branch %s conflicts with previously collected workers in: %s", workerBranch, strings.Join(unresolved, ", "))
+	}
+
+	if _, err := runGit(repoRoot, "-c", "core.editor=true", "cherry-pick", "--continue"); err != nil {
+		abortCherryPick(repoRoot)
+		return fmt.Errorf("failed to continue cherry-pick of worker commit %s from %s: %w", workerCommit, workerBranch, err)
+	}
+
+	return nil
+}
+
+// conflictedFiles lists unmerged paths relative to the repository root.
+func conflictedFiles(repoRoot string) ([]string, error) {
+	out, err := runGit(repoRoot, "diff", "--name-only", "--diff-filter=U")
+	if err != nil {
+		return nil, err
+	}
+	return parseListInput(out), nil
+}
+
+// resolveConflictedFile merges the index stages of a conflicted file using the
+// given strategy and stages the result.
+func resolveConflictedFile(repoRoot, file string, strategy fanout.MergeStrategy) error {
+	// A missing stage means the file was added (base) or deleted (ours/theirs) on that side.
+	stage := func(n int) []byte {
+		out, err := runGit(repoRoot, "show", fmt.Sprintf(":%d:%s", n, file))
+		if err != nil {
+			return nil
+		}
+		return []byte(out)
+	}
+
+	result, err := fanout.MergeFile(strategy, stage(1), stage(2), stage(3))
+	if err != nil {
+		return err
+	}
+	for _, location := range result.Overridden {
+		logging.Info("%s: %s changed on both sides, using the worker value", file, location)
+	}
+
+	fullPath := filepath.Join(repoRoot, file)
+	perm := os.FileMode(0o644)
+	if info, err := os.Stat(fullPath); err == nil {
+		perm = info.Mode().Perm()
+	}
+	if err := os.WriteFile(fullPath, result.Content, perm); err != nil {
+		return fmt.Errorf("failed to write merged %s: %w", file, err)
+	}
+
+	if _, err := runGit(repoRoot, "add", "--", file); err != nil {
+		return fmt.Errorf("failed to stage merged %s: %w", file, err)
+	}
+	return nil
+}
+
+func abortCherryPick(repoRoot string) {
+	if _, err := runGit(repoRoot, "cherry-pick", "--abort"); err != nil {
+		logging.Debug("failed to abort cherry-pick: %v", err)
+	}
+}
+
 // collectTestReports reads all test report JSON files from the given directory.
 func collectTestReports(dir string) map[string]TargetTestReportFile {
 	entries, err := os.ReadDir(dir)
@@ -0,0 +1,247 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	config "github.com/speakeasy-api/sdk-gen-config"
+	"github.com/speakeasy-api/sdk-gen-config/workflow"
+	"github.com/speakeasy-api/speakeasy/internal/ci/environment"
+	"github.com/speakeasy-api/speakeasy/internal/ci/fanout"
+	cigit "github.com/speakeasy-api/speakeasy/internal/ci/git"
+	"github.com/speakeasy-api/speakeasy/internal/ci/logging"
+)
+
+type FanoutPlanInputs struct {
+	BaseBranch string
+	Targets    string
+	Format     string
+	OutputFile string
+	Force      bool
+}
+
+// FanoutPlan determines which workflow targets need regeneration and emits a
+// CI matrix with one worker per target, along with the worker branch names
+// that FanoutFinalize later collects.
+func FanoutPlan(ctx context.Context, inputs FanoutPlanInputs) error {
+	baseBranch := strings.TrimSpace(inputs.BaseBranch)
+	if baseBranch == "" {
+		baseBranch = environment.GetSourceBranch()
+	}
+	if baseBranch == "" {
+		return fmt.Errorf("base branch is required")
+	}
+
+	format := fanout.Format(strings.TrimSpace(inputs.Format))
+	if format == "" {
+		format = fanout.FormatGitHub
+	}
+
+	projectDir := filepath.Join(environment.GetWorkspace(), environment.GetWorkingDirectory())
+	if projectDir == "" {
+		projectDir = "."
+	}
+
+	wf, _, err := workflow.Load(projectDir)
+	if err != nil {
+		return fmt.Errorf("failed to load workflow.yaml: %w", err)
+	}
+
+	// A missing lockfile simply means every target needs generating.
+	lf, err := workflow.LoadLockfile(projectDir)
+	if err != nil {
+		logging.Debug("failed to load workflow.lock, planning all targets: %v", err)
+		lf = nil
+	}
+
+	requestedTargets := parseListInput(inputs.Targets)
+	for _, targetID := range requestedTargets {
+		if _, ok := wf.Targets[targetID]; !ok {
+			return fmt.Errorf("target %s not found in workflow.yaml", targetID)
+		}
+	}
+
+	changedSources := changedLocalSources(projectDir, wf)
+
+	var targets []fanout.Target
+	for targetID, target := range wf.Targets {
+		if len(requestedTargets) > 0 && !slices.Contains(requestedTargets, targetID) {
+			continue
+		}
+
+		trackedFiles := 0
+		if target.Output != nil {
+			trackedFiles = countTrackedFiles(filepath.Join(projectDir, *target.Output))
+		} else {
+			trackedFiles = countTrackedFiles(projectDir)
+		}
+
+		reasons := regenerationReasons(targetID, target, wf, lf, changedSources)
+		if inputs.Force || environment.ForceGeneration() {
+			reasons = append([]string{"forced"}, reasons...)
+		}
+
+		targets = append(targets, fanout.Target{
+			ID:       targetID,
+			Language: target.Target,
+			Source:   target.Source,
+			Cost:     fanout.EstimateCost(target.Target, trackedFiles),
+			Reasons:  reasons,
+		})
+	}
+
+	plan := fanout.NewPlan(cigit.BranchPrefixFanout, baseBranch, targets)
+
+	for _, w := range plan.Workers {
+		logging.Info("%s (%s, cost %d): %s -> %s", w.Target, w.Tier, w.Cost, strings.Join(w.Reasons, "; "), w.Branch)
+	}
+	for _, skipped := range plan.Skipped {
+		logging.Info("%s: up to date, skipping", skipped)
+	}
+
+	matrix, err := plan.Matrix(format)
+	if err != nil {
+		return err
+	}
+
+	if outputFile := strings.TrimSpace(inputs.OutputFile); outputFile != "" {
+		planJSON, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal fanout plan: %w", err)
+		}
+		if err := os.WriteFile(outputFile, planJSON, 0o644); err != nil {
+			return fmt.Errorf("failed to write fanout plan to %s: %w", outputFile, err)
+		}
+	}
+
+	if os.Getenv("GITHUB_OUTPUT") != "" {
+		return setOutputs(map[string]string{
+			"matrix":          string(matrix),
+			"worker_branches": strings.Join(plan.WorkerBranches(), ","),
+			"has_workers":     fmt.Sprintf("%t", len(plan.Workers) > 0),
+		})
+	}
+
+	fmt.Println(string(matrix))
+
+	return nil
+}
+
+// regenerationReasons compares the workflow against its lockfile and returns
+// why a target needs regenerating. An empty result means the target is up to date.
+func regenerationReasons(targetID string, target workflow.Target, wf *workflow.Workflow, lf *workflow.LockFile, changedSources map[string]bool) []string {
+	if lf == nil {
+		return []string{"no workflow.lock found"}
+	}
+
+	targetLock, ok := lf.Targets[targetID]
+	if !ok {
+		return []string{"target not in workflow.lock"}
+	}
+
+	var reasons []string
+
+	if sourceLock, ok := lf.Sources[target.Source]; !ok {
+		if _, isSource := wf.Sources[target.Source]; isSource {
+			reasons = append(reasons, "source not in workflow.lock")
+		}
+	} else if sourceLock.SourceRevisionDigest != "" && sourceLock.SourceRevisionDigest != targetLock.SourceRevisionDigest {
+		reasons = append(reasons, "source revision changed since last generation")
+	}
+
+	if changedSources[target.Source] {
+		reasons = append(reasons, "local source inputs changed")
+	}
+
+	if lockedTarget, ok := lf.Workflow.Targets[targetID]; !ok || !workflowTargetsEqual(lockedTarget, target) {
+		reasons = append(reasons, "target configuration changed")
+	}
+
+	if lockedSource, ok := lf.Workflow.Sources[target.Source]; ok {
+		if currentSource, ok := wf.Sources[target.Source]; ok && !workflowSourcesEqual(lockedSource, currentSource) {
+			reasons = append(reasons, "source configuration changed")
+		}
+	}
+
+	return reasons
+}
+
+// changedLocalSources reports which sources have local inputs or overlays that
+// changed since workflow.lock was last committed (including uncommitted changes).
+func changedLocalSources(projectDir string, wf *workflow.Workflow) map[string]bool {
+	changed := make(map[string]bool)
+
+	lockCommit, err := runGit(projectDir, "log", "-1", "--format=%H", "--", filepath.Join(".speakeasy", "workflow.lock"))
+	lockCommit = strings.TrimSpace(lockCommit)
+	if err != nil || lockCommit == "" {
+		logging.Debug("could not resolve last workflow.lock commit, skipping local input comparison: %v", err)
+		return changed
+	}
+
+	for sourceID, source := range wf.Sources {
+		var paths []string
+		for _, input := range source.Inputs {
+			if !input.IsRemote() && !input.IsSpeakeasyRegistry() {
+				paths = append(paths, input.Location.Resolve())
+			}
+		}
+		for _, overlay := range source.Overlays {
+			if overlay.Document != nil && !overlay.Document.IsRemote() && !overlay.Document.IsSpeakeasyRegistry() {
+				paths = append(paths, overlay.Document.Location.Resolve())
+			}
+		}
+		if len(paths) == 0 {
+			continue
+		}
+
+		out, err := runGit(projectDir, append([]string{"diff", "--name-only", lockCommit, "--"}, paths...)...)
+		if err != nil {
+			logging.Debug("failed to diff inputs of source %s: %v", sourceID, err)
+			continue
+		}
+		if strings.TrimSpace(out) != "" {
+			changed[sourceID] = true
+		}
+	}
+
+	return changed
+}
+
+// countTrackedFiles returns the number of files tracked in the gen.lock of a
+// target output directory, or zero if no gen.lock exists yet.
+func countTrackedFiles(outDir string) int {
+	cfg, err := config.Load(outDir)
+	if err != nil || cfg.LockFile == nil {
+		return 0
+	}
+
+	count := 0
+	for range cfg.LockFile.TrackedFiles.Keys() {
+		count++
+	}
+	return count
+}
+
+func workflowTargetsEqual(a, b workflow.Target) bool {
+	return marshalledEqual(a, b)
+}
+
+func workflowSourcesEqual(a, b workflow.Source) bool {
+	return marshalledEqual(a, b)
+}
+
+// marshalledEqual compares two workflow values by their serialized form, so
+// optional (pointer) fields are compared by value rather than identity.
+func marshalledEqual(a, b any) bool {
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return string(aJSON) == string(bJSON)
+}
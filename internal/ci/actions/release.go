@@ -2,9 +2,12 @@ package actions
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -15,9 +18,19 @@ import (
 	"github.com/speakeasy-api/speakeasy/internal/ci/run"
 	"github.com/speakeasy-api/speakeasy/internal/ci/tagbridge"
 	"github.com/speakeasy-api/speakeasy/internal/ci/utils"
+	"github.com/speakeasy-api/versioning-reports/versioning"
 )
 
-func Release(ctx context.Context) error {
+type ReleaseInputs struct {
+	// Preview renders the release notes locally without creating any releases.
+	Preview bool
+}
+
+func Release(ctx context.Context, inputs ReleaseInputs) error {
+	if inputs.Preview {
+		return previewRelease()
+	}
+
 	accessToken := environment.GetAccessToken()
 	if accessToken == "" {
 		return errors.New("github access token is required")
@@ -28,25 +41,11 @@ func Release(ctx context.Context) error {
 		return err
 	}
 
-	dir := "."
-	usingReleasesMd := false
-	var providesExplicitTarget bool
-	logging.Info("specificTarget: %s", environment.SpecifiedTarget())
-	if specificTarget := environment.SpecifiedTarget(); specificTarget != "" {
-		workflow, err := configuration.GetWorkflowAndValidateLanguages(true)
-		if err != nil {
-			logging.Error("error: %v", err)
-			return err
-		}
-		if target, ok := workflow.Targets[specificTarget]; ok {
-			if target.Output != nil {
-				dir = strings.TrimPrefix(*target.Output, "./")
-			}
-
-			dir = filepath.Join(environment.GetWorkingDirectory(), dir)
-			providesExplicitTarget = true
-		}
+	dir, providesExplicitTarget, err := getSpecifiedTargetDir()
+	if err != nil {
+		return err
 	}
+	usingReleasesMd := false
 
 	logging.Info("providesExplicitTarget is set as: %v", providesExplicitTarget)
 
@@ -67,33 +66,16 @@ func Release(ctx context.Context) error {
 
 	}
 
-	var languages map[string]releases.LanguageReleaseInfo
-	var latestRelease *releases.ReleasesInfo
-	var targetSpecificReleaseNotes releases.TargetReleaseNotes = nil
-
-	// Old way of getting release Info (uses RELEASES.md)
-	if usingReleasesMd {
-		logging.Info("Using RELEASES.md to get release info")
-		latestRelease, err = releases.GetLastReleaseInfo(dir)
-	} else {
-		logging.Info("Using gen lockfile to get release info")
-		latestRelease, err = releases.GetReleaseInfoFromGenerationFiles(dir)
-		if err != nil {
-			fmt.Printf("Error getting release info from generation files: %v\n", err)
-			return err
-		}
-		// targetSpecificReleaseNotes variable is present only if INPUT_ENABLE_SDK_CHANGELOG env is true
-		targetSpecificReleaseNotes, err = releases.GetTargetSpecificReleaseNotes(dir)
-		if err != nil {
-			fmt.Printf("Error getting target specific release notes: %v\n", err)
-		}
-
+	latestRelease, targetSpecificReleaseNotes, err := getReleaseInfo(dir, usingReleasesMd)
+	if err != nil {
+		return err
 	}
+	languages := latestRelease.Languages
+
+	oldReleaseContent, targetSpecificReleaseNotes, err := renderReleaseNotes(latestRelease, targetSpecificReleaseNotes, loadReleaseNotesExtras())
 	if err != nil {
 		return err
 	}
-	languages = latestRelease.Languages
-	oldReleaseContent := latestRelease.String()
 
 	outputs := map[string]string{}
 	for lang, info := range languages {
@@ -122,6 +104,172 @@ func Release(ctx context.Context) error {
 	return nil
 }
 
+// getSpecifiedTargetDir resolves the output directory of the target specified
+// via INPUT_TARGET. The boolean result reports whether such a target was found.
+func getSpecifiedTargetDir() (string, bool, error) {
+	dir := "."
+	logging.Info("specificTarget: %s", environment.SpecifiedTarget())
+	if specificTarget := environment.SpecifiedTarget(); specificTarget != "" {
+		workflow, err := configuration.GetWorkflowAndValidateLanguages(true)
+		if err != nil {
+			logging.Error("error: %v", err)
+			return "", false, err
+		}
+		if target, ok := workflow.Targets[specificTarget]; ok {
+			if target.Output != nil {
+				dir = strings.TrimPrefix(*target.Output, "./")
+			}
+
+			return filepath.Join(environment.GetWorkingDirectory(), dir), true, nil
+		}
+	}
+
+	return dir, false, nil
+}
+
+func getReleaseInfo(dir string, usingReleasesMd bool) (*releases.ReleasesInfo, releases.TargetReleaseNotes, error) {
+	// Old way of getting release Info (uses RELEASES.md)
+	if usingReleasesMd {
+		logging.Info("Using RELEASES.md to get release info")
+		latestRelease, err := releases.GetLastReleaseInfo(dir)
+		return latestRelease, nil, err
+	}
+
+	logging.Info("Using gen lockfile to get release info")
+	latestRelease, err := releases.GetReleaseInfoFromGenerationFiles(dir)
+	if err != nil {
+		fmt.Printf("Error getting release info from generation files: %v\n", err)
+		return nil, nil, err
+	}
+	// targetSpecificReleaseNotes variable is present only if INPUT_ENABLE_SDK_CHANGELOG env is true
+	targetSpecificReleaseNotes, err := releases.GetTargetSpecificReleaseNotes(dir)
+	if err != nil {
+		fmt.Printf("Error getting target specific release notes: %v\n", err)
+	}
+
+	return latestRelease, targetSpecificReleaseNotes, err
+}
+
+// renderReleaseNotes returns the combined and per-target release bodies. When
+// release notes templates are configured they replace the built-in format, and
+// only targets with a rendered template get a body of their own.
+func renderReleaseNotes(info *releases.ReleasesInfo, targetNotes releases.TargetReleaseNotes, extras releases.ReleaseNotesExtras) (string, releases.TargetReleaseNotes, error) {
+	templatesDir := environment.GetReleaseNotesTemplates()
+	if templatesDir == "" {
+		return info.String(), targetNotes, nil
+	}
+
+	if !filepath.IsAbs(templatesDir) {
+		templatesDir = filepath.Join(environment.GetWorkspace(), resolvePathFromWorkingDirectory(templatesDir))
+	}
+
+	templates, err := releases.LoadReleaseNotesTemplates(templatesDir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	data := releases.NewReleaseNotesData(*info, extras)
+
+	combined, ok, err := templates.RenderRelease(data)
+	if err != nil {
+		return "", nil, err
+	}
+	if !ok {
+		combined = releases.GeneratedReleaseBody(info.String())
+	}
+
+	// Targets without a template of their own use the combined body.
+	rendered, err := templates.RenderTargets(data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return combined, rendered, nil
+}
+
+// loadReleaseNotesExtras collects version reports and OpenAPI change summaries
+// from generation reports, when they are still present in the working tree.
+func loadReleaseNotesExtras() releases.ReleaseNotesExtras {
+	var extras releases.ReleaseNotesExtras
+
+	dir := filepath.Join(environment.GetWorkspace(), resolvePathFromWorkingDirectory(reportsDir))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		logging.Debug("no generation reports found at %s: %v", dir, err)
+		return extras
+	}
+
+	var summaries []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		var report TargetGenerationReport
+		if err := json.Unmarshal(data, &report); err != nil {
+			logging.Debug("skipping %s: %v", entry.Name(), err)
+			continue
+		}
+
+		if report.VersionReport != nil {
+			if extras.VersionReport == nil {
+				extras.VersionReport = &versioning.MergedVersionReport{}
+			}
+			extras.VersionReport.Reports = append(extras.VersionReport.Reports, report.VersionReport.Reports...)
+		}
+		if report.OpenAPIChangeSummary != "" && !slices.Contains(summaries, report.OpenAPIChangeSummary) {
+			summaries = append(summaries, report.OpenAPIChangeSummary)
+		}
+		if extras.ChangesReportURL == "" {
+			extras.ChangesReportURL = report.ChangesReportURL
+		}
+	}
+	extras.OpenAPIChanges = strings.Join(summaries, "\n\n")
+
+	return extras
+}
+
+// previewRelease renders the release notes for the specified target (or the
+// current directory) and prints them without creating any releases.
+func previewRelease() error {
+	dir, _, err := getSpecifiedTargetDir()
+	if err != nil {
+		return err
+	}
+
+	usingReleasesMd := false
+	if _, err := os.Stat(filepath.Join(dir, ".speakeasy", "gen.lock")); err != nil {
+		if _, err := os.Stat(releases.GetReleasesPath(dir)); err == nil {
+			usingReleasesMd = true
+		}
+	}
+
+	latestRelease, targetSpecificReleaseNotes, err := getReleaseInfo(dir, usingReleasesMd)
+	if err != nil {
+		return err
+	}
+
+	combined, targetNotes, err := renderReleaseNotes(latestRelease, targetSpecificReleaseNotes, loadReleaseNotesExtras())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("=== GitHub release ===\n%s\n", combined)
+
+	langs := slices.Sorted(maps.Keys(latestRelease.Languages))
+	for _, lang := range langs {
+		if !targetNotes.HasReleaseNotesForTarget(lang) {
+			continue
+		}
+		fmt.Printf("\n=== %s release ===\n%s\n", lang, targetNotes.GetReleaseNotesForTarget(lang))
+	}
+
+	return nil
+}
+
 func GetDirAndShouldUseReleasesMD(files []string, dir string, usingReleasesMd bool) (string, bool) {
 	for _, file := range files {
 		if strings.Contains(file, "gen.lock") {
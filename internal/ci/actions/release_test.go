@@ -1,9 +1,12 @@
 package actions
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/speakeasy-api/speakeasy/internal/ci/environment"
+	"github.com/speakeasy-api/speakeasy/internal/ci/releases"
 )
 
 // TestBranchNameSanitizationForOCITags verifies that branch names are properly
@@ -144,3 +147,63 @@ func TestGetDirAndShouldUseReleasesMD(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderReleaseNotesOnlyReturnsRenderedTargets(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, releases.ReleaseTemplateFile), []byte("Release {{ .Title }}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.md.tmpl"), []byte("Go {{ .Version }}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("INPUT_RELEASE_NOTES_TEMPLATES", dir)
+
+	info := &releases.ReleasesInfo{
+		ReleaseTitle: "2025-01-01",
+		Languages: map[string]releases.LanguageReleaseInfo{
+			"go":         {PackageName: "github.com/acme/sdk", Version: "1.2.0"},
+			"typescript": {PackageName: "acme", Version: "2.0.0"},
+		},
+	}
+	targetNotes := releases.TargetReleaseNotes{
+		"go":         "gen.lock go notes",
+		"typescript": "gen.lock typescript notes",
+	}
+
+	combined, notes, err := renderReleaseNotes(info, targetNotes, releases.ReleaseNotesExtras{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if combined != "Release 2025-01-01" {
+		t.Errorf("combined = %q", combined)
+	}
+	if got := notes.GetReleaseNotesForTarget("go"); got != "Go 1.2.0" {
+		t.Errorf("go notes = %q, want the rendered template", got)
+	}
+	if notes.HasReleaseNotesForTarget("typescript") {
+		t.Errorf("typescript notes = %q, want none so the combined body is used", notes.GetReleaseNotesForTarget("typescript"))
+	}
+}
+
+func TestRenderReleaseNotesKeepsHeaderWithoutReleaseTemplate(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.md.tmpl"), []byte("Go {{ .Version }}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("INPUT_RELEASE_NOTES_TEMPLATES", dir)
+
+	info := &releases.ReleasesInfo{
+		ReleaseTitle: "2025-01-01",
+		Languages: map[string]releases.LanguageReleaseInfo{
+			"go": {PackageName: "github.com/acme/sdk", Version: "1.2.0"},
+		},
+	}
+
+	combined, _, err := renderReleaseNotes(info, nil, releases.ReleaseNotesExtras{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := releases.GeneratedReleaseBody(info.String()); combined != want {
+		t.Errorf("combined = %q, want %q", combined, want)
+	}
+}
@@ -370,6 +370,15 @@ func finalize(ctx context.Context, inputs finalizeInputs) error {
 				targetSpecificReleaseNotes = inputs.releaseNotes
 			}
 
+			oldReleaseInfo, targetSpecificReleaseNotes, err = renderReleaseNotes(releaseInfo, targetSpecificReleaseNotes, releases.ReleaseNotesExtras{
+				VersionReport:    inputs.VersioningInfo.VersionReport,
+				OpenAPIChanges:   inputs.OpenAPIChangeSummary,
+				ChangesReportURL: inputs.ChangesReportURL,
+			})
+			if err != nil {
+				return err
+			}
+
 			// We still read from releases info for terraform generations since they use the goreleaser
 			// Read from Releases.md for terraform generations
 			if inputs.Outputs[utils.OutputTargetRegenerated("terraform")] == "true" {
@@ -26,6 +26,10 @@ type ValidateSpecsInputs struct {
 	MaxValidationWarnings int
 	Ruleset               string
 	FailOnSkipped         bool
+	// Format, if set, is the format of a report of the findings written to
+	// Output, or stdout, instead of the comment.
+	Format validation.OutputFormat
+	Output string
 }
 
 // ValidateSpecs discovers specs via glob patterns, validates each, posts a PR comment, and writes a step summary.
@@ -56,8 +60,14 @@ func ValidateSpecs(ctx context.Context, inputs ValidateSpecsInputs) error {
 		}
 	}
 
+	baseline, err := validation.LoadProjectBaseline(".")
+	if err != nil {
+		return err
+	}
+
 	// Validate each spec
 	var results []github.SpecValidationResult
+	var findings []validation.Finding
 	totalErrors := 0
 
 	for _, specPath := range uniquePaths {
@@ -67,16 +77,19 @@ func ValidateSpecs(ctx context.Context, inputs ValidateSpecsInputs) error {
 		// per-spec step summaries (we build our own consolidated summary).
 		isRemote, schema, err := openapi.GetSchemaContents(ctx, specPath, "", "")
 		if err != nil {
+			err = fmt.Errorf("failed to read spec: %w", err)
+			findings = append(findings, validation.Finding{File: specPath, Severity: validation.SeverityError, Message: err.Error()})
 			results = append(results, github.SpecValidationResult{
 				SpecPath: specPath,
-				Errors:   []error{fmt.Errorf("failed to read spec: %w", err)},
+				Errors:   []error{err},
 			})
 			totalErrors++
 			continue
 		}
 
-		res, err := validation.Validate(ctx, logger, schema, specPath, limits, isRemote, inputs.Ruleset, ".", true, true, "")
+		res, err := validation.Validate(ctx, logger, schema, specPath, limits, isRemote, inputs.Ruleset, ".", true, true, "", baseline.Document(validation.DocumentKey(".", specPath)))
 		if err != nil {
+			findings = append(findings, validation.Finding{File: specPath, Severity: validation.SeverityError, Message: err.Error()})
 			results = append(results, github.SpecValidationResult{
 				SpecPath: specPath,
 				Errors:   []error{err},
@@ -85,6 +98,7 @@ func ValidateSpecs(ctx context.Context, inputs ValidateSpecsInputs) error {
 			continue
 		}
 
+		findings = append(findings, validation.FindingsOf(specPath, res)...)
 		results = append(results, github.SpecValidationResult{
 			SpecPath:          specPath,
 			Errors:            res.Errors,
@@ -108,8 +122,14 @@ func ValidateSpecs(ctx context.Context, inputs ValidateSpecsInputs) error {
 		}
 	}
 
-	// Print to stdout for local usage
-	fmt.Println(commentBody)
+	if inputs.Format != "" {
+		if err := writeFindings(inputs, uniquePaths, findings); err != nil {
+			return err
+		}
+	} else {
+		// Print to stdout for local usage
+		fmt.Println(commentBody)
+	}
 
 	if totalErrors > 0 {
 		return fmt.Errorf("validation failed with %d %s across %d %s",
@@ -128,6 +148,23 @@ func ValidateSpecs(ctx context.Context, inputs ValidateSpecsInputs) error {
 	return nil
 }
 
+func writeFindings(inputs ValidateSpecsInputs, specPaths []string, findings []validation.Finding) error {
+	w := os.Stdout
+	if inputs.Output != "" {
+		f, err := os.Create(inputs.Output)
+		if err != nil {
+			return fmt.Errorf("failed to create report: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := validation.WriteFindings(w, inputs.Format, specPaths, findings); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
 func discoverSpecPaths(patterns []string) ([]string, error) {
 	var specPaths []string
 	for _, pattern := range patterns {
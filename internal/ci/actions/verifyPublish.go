@@ -0,0 +1,136 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	config "github.com/speakeasy-api/sdk-gen-config"
+	"github.com/speakeasy-api/speakeasy/internal/ci/environment"
+	"github.com/speakeasy-api/speakeasy/internal/ci/logging"
+	"github.com/speakeasy-api/speakeasy/internal/ci/publishing"
+	"github.com/speakeasy-api/speakeasy/internal/ci/utils"
+)
+
+type VerifyPublishInputs struct {
+	TargetDirectory string
+	RegistryName    string
+	// PackageName and Version override the values read from gen.yaml and gen.lock.
+	PackageName string
+	Version     string
+	Endpoints   string
+	Timeout     time.Duration
+	// WarnOnly annotates missing packages as warnings instead of failing.
+	WarnOnly bool
+}
+
+// registryLanguages maps publishing registry names to the gen.yaml language
+// holding the package configuration.
+var registryLanguages = map[string]string{
+	"npm":       "typescript",
+	"pypi":      "python",
+	"go":        "go",
+	"sonatype":  "java",
+	"nuget":     "csharp",
+	"gems":      "ruby",
+	"packagist": "php",
+}
+
+// VerifyPublish polls the package registry until the version released from
+// the target directory is available, so that failed publishes are surfaced
+// by the workflow rather than by users.
+func VerifyPublish(ctx context.Context, inputs VerifyPublishInputs) error {
+	registry := strings.TrimSpace(inputs.RegistryName)
+	if !publishing.IsSupported(registry) {
+		logging.Info("Publish verification is not supported for registry %q, skipping", registry)
+		return nil
+	}
+
+	pkg, err := publishedPackage(registry, inputs)
+	if err != nil {
+		return err
+	}
+
+	endpoints, err := publishing.ParseEndpoints(parseListInput(inputs.Endpoints))
+	if err != nil {
+		return err
+	}
+
+	verifier := publishing.NewVerifier()
+	verifier.Endpoints = endpoints
+	if inputs.Timeout > 0 {
+		verifier.Timeout = inputs.Timeout
+	}
+
+	logging.Info("Verifying %s is available from %s", pkg, verifier.Endpoint(registry))
+
+	verifyErr := verifier.Verify(ctx, pkg)
+	if os.Getenv("GITHUB_OUTPUT") != "" {
+		if err := setOutputs(map[string]string{
+			"publish_verified": fmt.Sprintf("%t", verifyErr == nil),
+		}); err != nil {
+			return err
+		}
+	}
+
+	if verifyErr == nil {
+		logging.Info("Verified %s", pkg)
+		return nil
+	}
+
+	if inputs.WarnOnly {
+		fmt.Printf("::warning title=Publish verification failed::%s\n", verifyErr)
+		return nil
+	}
+
+	fmt.Printf("::error title=Publish verification failed::%s\n", verifyErr)
+	return verifyErr
+}
+
+func publishedPackage(registry string, inputs VerifyPublishInputs) (publishing.Package, error) {
+	pkg := publishing.Package{
+		Registry: registry,
+		Name:     strings.TrimSpace(inputs.PackageName),
+		Version:  strings.TrimSpace(inputs.Version),
+	}
+	if pkg.Name != "" && pkg.Version != "" {
+		return pkg, nil
+	}
+
+	path := filepath.Join(environment.GetWorkspace(), inputs.TargetDirectory)
+	loadedCfg, err := config.Load(path)
+	if err != nil {
+		return pkg, fmt.Errorf("failed to load config in directory %s: %w", path, err)
+	}
+
+	if pkg.Version == "" {
+		if loadedCfg.LockFile == nil || loadedCfg.LockFile.Management.ReleaseVersion == "" {
+			return pkg, fmt.Errorf("no release version found in gen.lock in directory %s", path)
+		}
+		pkg.Version = loadedCfg.LockFile.Management.ReleaseVersion
+	}
+
+	if pkg.Name == "" {
+		lang := registryLanguages[registry]
+		if loadedCfg.Config == nil {
+			return pkg, fmt.Errorf("empty config for %s language target in directory %s", lang, path)
+		}
+		langCfg, ok := loadedCfg.Config.Languages[lang]
+		if !ok {
+			return pkg, fmt.Errorf("no %s config in directory %s", lang, path)
+		}
+
+		if lang == "java" {
+			groupID, _ := langCfg.Cfg["groupID"].(string)
+			artifactID, _ := langCfg.Cfg["artifactID"].(string)
+			pkg.Name = groupID + ":" + artifactID
+		} else {
+			pkg.Name = utils.GetPackageName(lang, &langCfg)
+		}
+	}
+
+	return pkg, nil
+}
@@ -229,6 +229,12 @@ func GetSDKChangelog() string {
 	return os.Getenv("INPUT_ENABLE_SDK_CHANGELOG")
 }
 
+// GetReleaseNotesTemplates returns the directory containing user supplied
+// release notes templates, if configured.
+func GetReleaseNotesTemplates() string {
+	return os.Getenv("INPUT_RELEASE_NOTES_TEMPLATES")
+}
+
 func SkipRelease() bool {
 	return os.Getenv("INPUT_SKIP_RELEASE") == "true"
 }
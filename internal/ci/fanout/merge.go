@@ -0,0 +1,307 @@
+package fanout
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"gopkg.in/yaml.v3"
+)
+
+// MergeStrategy describes how a conflicted file is resolved when a worker
+// commit is applied on top of the commits collected so far. In the terms of
+// a cherry-pick, "ours" is the accumulated branch and "theirs" is the worker.
+type MergeStrategy string
+
+const (
+	// MergeStrategyLockfile performs a key-wise three-way merge of YAML
+	// lockfiles (workflow.lock, gen.lock), taking the union of entries.
+	MergeStrategyLockfile MergeStrategy = "lockfile"
+	// MergeStrategyReleases concatenates the release sections appended to
+	// RELEASES.md by each worker.
+	MergeStrategyReleases MergeStrategy = "releases"
+	MergeStrategyOurs     MergeStrategy = "ours"
+	MergeStrategyTheirs   MergeStrategy = "theirs"
+	// MergeStrategyFail leaves the conflict unresolved, failing finalization.
+	MergeStrategyFail MergeStrategy = "fail"
+)
+
+// MergeRule assigns a strategy to files matching a glob pattern. Patterns are
+// matched against slash-separated paths relative to the repository root.
+type MergeRule struct {
+	Pattern  string
+	Strategy MergeStrategy
+}
+
+// DefaultMergeRules covers the files every worker touches. Anything else,
+// i.e. generated code, is a genuine conflict.
+var DefaultMergeRules = []MergeRule{
+	{Pattern: "**/.speakeasy/workflow.lock", Strategy: MergeStrategyLockfile},
+	{Pattern: "**/.speakeasy/gen.lock", Strategy: MergeStrategyLockfile},
+	{Pattern: "**/RELEASES.md", Strategy: MergeStrategyReleases},
+}
+
+// ParseMergeRules parses `pattern=strategy` pairs. Parsed rules take
+// precedence over DefaultMergeRules, which are appended after them.
+func ParseMergeRules(pairs []string) ([]MergeRule, error) {
+	rules := make([]MergeRule, 0, len(pairs)+len(DefaultMergeRules))
+	for _, pair := range pairs {
+		pattern, strategy, ok := strings.Cut(pair, "=")
+		pattern = strings.TrimSpace(pattern)
+		strategy = strings.TrimSpace(strategy)
+		if !ok || pattern == "" || strategy == "" {
+			return nil, fmt.Errorf("invalid merge strategy %q: expected pattern=strategy", pair)
+		}
+		if !doublestar.ValidatePattern(pattern) {
+			return nil, fmt.Errorf("invalid merge strategy pattern %q", pattern)
+		}
+
+		switch s := MergeStrategy(strategy); s {
+		case MergeStrategyLockfile, MergeStrategyReleases, MergeStrategyOurs, MergeStrategyTheirs, MergeStrategyFail:
+			rules = append(rules, MergeRule{Pattern: pattern, Strategy: s})
+		default:
+			return nil, fmt.Errorf("unknown merge strategy %q for %s", strategy, pattern)
+		}
+	}
+
+	return append(rules, DefaultMergeRules...), nil
+}
+
+// StrategyFor returns the strategy of the first rule matching the file, or
+// MergeStrategyFail if none match.
+func StrategyFor(rules []MergeRule, file string) MergeStrategy {
+	file = path.Clean(strings.TrimPrefix(file, "./"))
+	for _, rule := range rules {
+		if matched, _ := doublestar.Match(rule.Pattern, file); matched {
+			return rule.Strategy
+		}
+	}
+	return MergeStrategyFail
+}
+
+// MergeResult is the outcome of a structured merge. Overridden lists the
+// locations where both sides changed the same value differently; these are
+// resolved in favour of the worker ("theirs") and reported for visibility.
+type MergeResult struct {
+	Content    []byte
+	Overridden []string
+}
+
+// MergeFile resolves a conflicted file from its three index stages. A nil
+// base means the file was added on both sides.
+func MergeFile(strategy MergeStrategy, base, ours, theirs []byte) (*MergeResult, error) {
+	switch strategy {
+	case MergeStrategyLockfile:
+		return MergeLockfile(base, ours, theirs)
+	case MergeStrategyReleases:
+		return &MergeResult{Content: MergeReleases(base, ours, theirs)}, nil
+	case MergeStrategyOurs:
+		return &MergeResult{Content: ours}, nil
+	case MergeStrategyTheirs:
+		return &MergeResult{Content: theirs}, nil
+	default:
+		return nil, fmt.Errorf("no structured merge available")
+	}
+}
+
+// MergeLockfile performs a three-way merge of two YAML lockfiles. Mappings are
+// merged key by key, so entries added or updated by either side (e.g. one
+// target lock each) are all retained.
+func MergeLockfile(base, ours, theirs []byte) (*MergeResult, error) {
+	baseNode, err := parseYAMLDocument(base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse base lockfile: %w", err)
+	}
+	oursNode, err := parseYAMLDocument(ours)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse our lockfile: %w", err)
+	}
+	theirsNode, err := parseYAMLDocument(theirs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse their lockfile: %w", err)
+	}
+
+	result := &MergeResult{}
+	merged := mergeYAMLNodes(baseNode, oursNode, theirsNode, "", &result.Overridden)
+	if merged == nil {
+		return result, nil
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(merged); err != nil {
+		return nil, fmt.Errorf("failed to encode merged lockfile: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode merged lockfile: %w", err)
+	}
+
+	result.Content = buf.Bytes()
+	return result, nil
+}
+
+func parseYAMLDocument(data []byte) (*yaml.Node, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
+		return doc.Content[0], nil
+	}
+	return &doc, nil
+}
+
+func mergeYAMLNodes(base, ours, theirs *yaml.Node, location string, overridden *[]string) *yaml.Node {
+	switch {
+	case yamlNodesEqual(ours, theirs):
+		return ours
+	case base != nil && yamlNodesEqual(base, ours):
+		return theirs
+	case base != nil && yamlNodesEqual(base, theirs):
+		return ours
+	case base == nil && ours == nil:
+		return theirs
+	case base == nil && theirs == nil:
+		return ours
+	}
+
+	if ours != nil && theirs != nil && ours.Kind == yaml.MappingNode && theirs.Kind == yaml.MappingNode {
+		if base != nil && base.Kind != yaml.MappingNode {
+			base = nil
+		}
+		return mergeYAMLMappings(base, ours, theirs, location, overridden)
+	}
+
+	if location == "" {
+		location = "."
+	}
+	*overridden = append(*overridden, location)
+	// Prefer the worker's value, but never drop an entry we still modify.
+	if theirs == nil {
+		return ours
+	}
+	return theirs
+}
+
+func mergeYAMLMappings(base, ours, theirs *yaml.Node, location string, overridden *[]string) *yaml.Node {
+	merged := &yaml.Node{
+		Kind:        yaml.MappingNode,
+		Tag:         ours.Tag,
+		Style:       ours.Style,
+		HeadComment: ours.HeadComment,
+		LineComment: ours.LineComment,
+		FootComment: ours.FootComment,
+	}
+
+	appendEntry := func(key, value *yaml.Node) {
+		if value != nil {
+			merged.Content = append(merged.Content, key, value)
+		}
+	}
+
+	for i := 0; i+1 < len(ours.Content); i += 2 {
+		key := ours.Content[i]
+		appendEntry(key, mergeYAMLNodes(
+			yamlMappingValue(base, key.Value),
+			ours.Content[i+1],
+			yamlMappingValue(theirs, key.Value),
+			location+"."+key.Value,
+			overridden,
+		))
+	}
+
+	for i := 0; i+1 < len(theirs.Content); i += 2 {
+		key := theirs.Content[i]
+		if yamlMappingValue(ours, key.Value) != nil {
+			continue
+		}
+		appendEntry(key, mergeYAMLNodes(
+			yamlMappingValue(base, key.Value),
+			nil,
+			theirs.Content[i+1],
+			location+"."+key.Value,
+			overridden,
+		))
+	}
+
+	return merged
+}
+
+func yamlMappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func yamlNodesEqual(a, b *yaml.Node) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	aYAML, errA := yaml.Marshal(a)
+	bYAML, errB := yaml.Marshal(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return bytes.Equal(aYAML, bYAML)
+}
+
+// MergeReleases merges RELEASES.md, to which every worker appends its own
+// release section. The sections added by the worker are appended after ours.
+func MergeReleases(base, ours, theirs []byte) []byte {
+	if bytes.HasPrefix(ours, base) && bytes.HasPrefix(theirs, base) {
+		added := theirs[len(base):]
+		if bytes.Contains(ours[len(base):], added) {
+			return ours
+		}
+		return append(append([]byte{}, ours...), added...)
+	}
+
+	// One side rewrote history; fall back to a union of whole sections.
+	merged := append([]byte{}, ours...)
+	oursSections := splitReleaseSections(ours)
+	for _, section := range splitReleaseSections(theirs) {
+		if !containsSection(oursSections, section) {
+			merged = append(merged, section...)
+		}
+	}
+	return merged
+}
+
+// splitReleaseSections splits RELEASES.md into its "## <title>" sections,
+// each including its leading blank lines.
+func splitReleaseSections(data []byte) [][]byte {
+	var sections [][]byte
+	rest := data
+	for len(rest) > 0 {
+		idx := bytes.Index(rest[1:], []byte("\n\n## "))
+		if idx < 0 {
+			sections = append(sections, rest)
+			break
+		}
+		sections = append(sections, rest[:idx+1])
+		rest = rest[idx+1:]
+	}
+	return sections
+}
+
+func containsSection(sections [][]byte, section []byte) bool {
+	for _, s := range sections {
+		if bytes.Equal(bytes.TrimSpace(s), bytes.TrimSpace(section)) {
+			return true
+		}
+	}
+	return false
+}
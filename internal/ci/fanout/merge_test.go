@@ -0,0 +1,128 @@
+package fanout
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrategyFor(t *testing.T) {
+	rules, err := ParseMergeRules([]string{"docs/**=theirs"})
+	require.NoError(t, err)
+
+	assert.Equal(t, MergeStrategyLockfile, StrategyFor(rules, ".speakeasy/workflow.lock"))
+	assert.Equal(t, MergeStrategyLockfile, StrategyFor(rules, "sdks/go/.speakeasy/gen.lock"))
+	assert.Equal(t, MergeStrategyReleases, StrategyFor(rules, "./sdks/python/RELEASES.md"))
+	assert.Equal(t, MergeStrategyTheirs, StrategyFor(rules, "docs/models/pet.md"))
+	assert.Equal(t, MergeStrategyFail, StrategyFor(rules, "sdks/go/pets.go"))
+}
+
+func TestParseMergeRules_Invalid(t *testing.T) {
+	_, err := ParseMergeRules([]string{"README.md"})
+	assert.Error(t, err)
+
+	_, err = ParseMergeRules([]string{"README.md=magic"})
+	assert.Error(t, err)
+}
+
+func TestMergeLockfile_UnionOfTargets(t *testing.T) {
+	base := []byte(`speakeasyVersion: 1.500.0
+sources:
+  api:
+    sourceRevisionDigest: sha256:aaa
+targets:
+  go:
+    source: api
+    sourceRevisionDigest: sha256:aaa
+  python:
+    source: api
+    sourceRevisionDigest: sha256:aaa
+`)
+	ours := []byte(`speakeasyVersion: 1.500.0
+sources:
+  api:
+    sourceRevisionDigest: sha256:bbb
+targets:
+  go:
+    source: api
+    sourceRevisionDigest: sha256:bbb
+  python:
+    source: api
+    sourceRevisionDigest: sha256:aaa
+`)
+	theirs := []byte(`speakeasyVersion: 1.500.0
+sources:
+  api:
+    sourceRevisionDigest: sha256:bbb
+targets:
+  go:
+    source: api
+    sourceRevisionDigest: sha256:aaa
+  python:
+    source: api
+    sourceRevisionDigest: sha256:bbb
+  typescript:
+    source: api
+    sourceRevisionDigest: sha256:bbb
+`)
+
+	result, err := MergeLockfile(base, ours, theirs)
+	require.NoError(t, err)
+	assert.Empty(t, result.Overridden)
+	assert.Equal(t, `speakeasyVersion: 1.500.0
+sources:
+  api:
+    sourceRevisionDigest: sha256:bbb
+targets:
+  go:
+    source: api
+    sourceRevisionDigest: sha256:bbb
+  python:
+    source: api
+    sourceRevisionDigest: sha256:bbb
+  typescript:
+    source: api
+    sourceRevisionDigest: sha256:bbb
+`, string(result.Content))
+}
+
+func TestMergeLockfile_ConflictingValues(t *testing.T) {
+	base := []byte("management:\n  speakeasyVersion: 1.0.0\n")
+	ours := []byte("management:\n  speakeasyVersion: 1.1.0\n")
+	theirs := []byte("management:\n  speakeasyVersion: 1.2.0\n")
+
+	result, err := MergeLockfile(base, ours, theirs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{".management.speakeasyVersion"}, result.Overridden)
+	assert.Equal(t, "management:\n  speakeasyVersion: 1.2.0\n", string(result.Content))
+}
+
+func TestMergeLockfile_AddedOnBothSides(t *testing.T) {
+	result, err := MergeLockfile(nil, []byte("targets:\n  go: {}\n"), []byte("targets:\n  python: {}\n"))
+	require.NoError(t, err)
+	assert.Empty(t, result.Overridden)
+	assert.Equal(t, "targets:\n  go: {}\n  python: {}\n", string(result.Content))
+}
+
+func TestMergeLockfile_Invalid(t *testing.T) {
+	_, err := MergeLockfile(nil, []byte("a: b"), []byte("a: [b"))
+	assert.Error(t, err)
+}
+
+func TestMergeReleases(t *testing.T) {
+	base := "\n\n## 2024-01-01 00:00:00\n### Changes\n- old"
+	ours := base + "\n\n## 2024-02-01 00:00:00\n### Changes\n- go"
+	theirs := base + "\n\n## 2024-02-01 00:00:01\n### Changes\n- python"
+
+	merged := MergeReleases([]byte(base), []byte(ours), []byte(theirs))
+	assert.Equal(t, ours+"\n\n## 2024-02-01 00:00:01\n### Changes\n- python", string(merged))
+
+	// Reapplying the same section is a no-op.
+	assert.Equal(t, ours, string(MergeReleases([]byte(base), []byte(ours), []byte(ours))))
+
+	// Without a common prefix, sections are unioned.
+	rewritten := "\n\n## 2024-01-01 00:00:00\n### Changes\n- legacy"
+	merged = MergeReleases([]byte(base), []byte(rewritten), []byte(theirs))
+	assert.Equal(t, rewritten+"\n\n## 2024-01-01 00:00:00\n### Changes\n- old\n\n## 2024-02-01 00:00:01\n### Changes\n- python", string(merged))
+}
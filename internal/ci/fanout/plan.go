@@ -0,0 +1,227 @@
+// Package fanout computes the worker matrix used by parallel (matrix mode)
+// generation. The resulting worker branch names are the ones consumed by
+// `speakeasy ci fanout-finalize`.
+package fanout
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/speakeasy-api/speakeasy/internal/ci/environment"
+)
+
+type Format string
+
+const (
+	FormatGitHub Format = "github"
+	FormatGitLab Format = "gitlab"
+)
+
+type Tier string
+
+const (
+	TierLarge  Tier = "large"
+	TierMedium Tier = "medium"
+	TierSmall  Tier = "small"
+)
+
+// Relative generation cost per target language. Compiled languages with
+// heavier toolchains (and longer test/compile steps) are weighted higher.
+var languageCosts = map[string]int{
+	"java":           8,
+	"csharp":         8,
+	"terraform":      8,
+	"go":             6,
+	"typescript":     6,
+	"python":         5,
+	"php":            5,
+	"ruby":           4,
+	"unity":          4,
+	"swift":          4,
+	"mcp-typescript": 6,
+	"postman":        1,
+	"docs":           2,
+}
+
+const defaultLanguageCost = 5
+
+// trackedFilesPerCostUnit is how many tracked (generated) files add one unit
+// of cost on top of the language weight.
+const trackedFilesPerCostUnit = 250
+
+// EstimateCost returns a relative cost for generating a target, based on the
+// target language and the number of files it generated last time.
+func EstimateCost(language string, trackedFiles int) int {
+	cost, ok := languageCosts[language]
+	if !ok {
+		cost = defaultLanguageCost
+	}
+	if trackedFiles > 0 {
+		cost += trackedFiles / trackedFilesPerCostUnit
+	}
+	return cost
+}
+
+// Target describes a workflow target considered for the plan.
+type Target struct {
+	ID       string
+	Language string
+	Source   string
+	Cost     int
+	// Reasons lists why the target needs regeneration. Targets without
+	// reasons are considered up to date and are skipped.
+	Reasons []string
+}
+
+// Worker is a single matrix entry, generating one target on its own branch.
+type Worker struct {
+	Target   string   `json:"target"`
+	Language string   `json:"language"`
+	Source   string   `json:"source"`
+	Branch   string   `json:"branch"`
+	Cost     int      `json:"cost"`
+	Tier     Tier     `json:"tier"`
+	Reasons  []string `json:"reasons"`
+}
+
+type Plan struct {
+	BaseBranch string   `json:"base_branch"`
+	Workers    []Worker `json:"workers"`
+	Skipped    []string `json:"skipped"`
+}
+
+// NewPlan builds a deterministic plan from the given targets. Workers are
+// ordered by descending cost (then target ID), so that the most expensive
+// targets are scheduled first when the CI system limits parallelism.
+func NewPlan(branchPrefix, baseBranch string, targets []Target) Plan {
+	plan := Plan{BaseBranch: baseBranch, Workers: []Worker{}, Skipped: []string{}}
+
+	for _, t := range targets {
+		if len(t.Reasons) == 0 {
+			plan.Skipped = append(plan.Skipped, t.ID)
+			continue
+		}
+		plan.Workers = append(plan.Workers, Worker{
+			Target:   t.ID,
+			Language: t.Language,
+			Source:   t.Source,
+			Branch:   WorkerBranchName(branchPrefix, baseBranch, t.ID),
+			Cost:     t.Cost,
+			Reasons:  t.Reasons,
+		})
+	}
+
+	slices.SortFunc(plan.Workers, func(a, b Worker) int {
+		if a.Cost != b.Cost {
+			return b.Cost - a.Cost
+		}
+		return strings.Compare(a.Target, b.Target)
+	})
+	slices.Sort(plan.Skipped)
+
+	assignTiers(plan.Workers)
+
+	return plan
+}
+
+// assignTiers splits cost-sorted workers into large/medium/small thirds.
+func assignTiers(workers []Worker) {
+	if len(workers) == 0 {
+		return
+	}
+
+	maxCost := workers[0].Cost
+	for i := range workers {
+		switch {
+		case workers[i].Cost*3 > maxCost*2:
+			workers[i].Tier = TierLarge
+		case workers[i].Cost*3 > maxCost:
+			workers[i].Tier = TierMedium
+		default:
+			workers[i].Tier = TierSmall
+		}
+	}
+}
+
+// WorkerBranchName returns the deterministic branch name a worker pushes its
+// generation commit to. The same base branch and target always produce the
+// same name, so the finalize job can derive it without extra state.
+func WorkerBranchName(branchPrefix, baseBranch, targetID string) string {
+	sanitizedBase := environment.SanitizeBranchName(baseBranch)
+	sanitizedTarget := environment.SanitizeBranchName(targetID)
+
+	name := fmt.Sprintf("%s-%s-%s", branchPrefix, sanitizedBase, sanitizedTarget)
+	// Keep branch names well below the ref length limits of common git hosts.
+	if len(name) > 200 {
+		sum := sha256.Sum256([]byte(baseBranch + "\x00" + targetID))
+		name = fmt.Sprintf("%s-%s", branchPrefix, hex.EncodeToString(sum[:])[:16])
+	}
+	return name
+}
+
+// WorkerBranches returns the worker branch names in plan order.
+func (p Plan) WorkerBranches() []string {
+	branches := make([]string, 0, len(p.Workers))
+	for _, w := range p.Workers {
+		branches = append(branches, w.Branch)
+	}
+	return branches
+}
+
+// Matrix renders the plan as a CI matrix definition in the given format.
+func (p Plan) Matrix(format Format) ([]byte, error) {
+	switch format {
+	case FormatGitHub:
+		return p.gitHubMatrix()
+	case FormatGitLab:
+		return p.gitLabMatrix()
+	default:
+		return nil, fmt.Errorf("unsupported matrix format: %s", format)
+	}
+}
+
+type gitHubMatrixEntry struct {
+	Target   string `json:"target"`
+	Language string `json:"language"`
+	Source   string `json:"source"`
+	Branch   string `json:"branch"`
+	Cost     int    `json:"cost"`
+	Tier     Tier   `json:"tier"`
+}
+
+// gitHubMatrix renders a `strategy.matrix` value, usable via
+// `fromJSON(needs.plan.outputs.matrix)`.
+func (p Plan) gitHubMatrix() ([]byte, error) {
+	include := make([]gitHubMatrixEntry, 0, len(p.Workers))
+	for _, w := range p.Workers {
+		include = append(include, gitHubMatrixEntry{
+			Target:   w.Target,
+			Language: w.Language,
+			Source:   w.Source,
+			Branch:   w.Branch,
+			Cost:     w.Cost,
+			Tier:     w.Tier,
+		})
+	}
+
+	return json.Marshal(map[string]any{"include": include})
+}
+
+// gitLabMatrix renders a `parallel:matrix` value. Each entry is a single
+// combination, expressed as one-element variable lists.
+func (p Plan) gitLabMatrix() ([]byte, error) {
+	matrix := make([]map[string][]string, 0, len(p.Workers))
+	for _, w := range p.Workers {
+		matrix = append(matrix, map[string][]string{
+			"SPEAKEASY_TARGET":        {w.Target},
+			"SPEAKEASY_WORKER_BRANCH": {w.Branch},
+			"SPEAKEASY_COST_TIER":     {string(w.Tier)},
+		})
+	}
+
+	return json.Marshal(map[string]any{"parallel": map[string]any{"matrix": matrix}})
+}
@@ -0,0 +1,78 @@
+package fanout
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPlan(t *testing.T) {
+	targets := []Target{
+		{ID: "my-python", Language: "python", Source: "api", Cost: EstimateCost("python", 0), Reasons: []string{"source revision changed"}},
+		{ID: "my-java", Language: "java", Source: "api", Cost: EstimateCost("java", 1000), Reasons: []string{"forced"}},
+		{ID: "docs", Language: "postman", Source: "api", Cost: EstimateCost("postman", 0), Reasons: []string{"not in workflow.lock"}},
+		{ID: "my-go", Language: "go", Source: "api", Cost: EstimateCost("go", 0)},
+	}
+
+	plan := NewPlan("speakeasy-fanout", "feature/new-api", targets)
+
+	require.Len(t, plan.Workers, 3)
+	assert.Equal(t, []string{"my-java", "my-python", "docs"}, []string{plan.Workers[0].Target, plan.Workers[1].Target, plan.Workers[2].Target})
+	assert.Equal(t, []string{"my-go"}, plan.Skipped)
+
+	assert.Equal(t, TierLarge, plan.Workers[0].Tier)
+	assert.Equal(t, TierMedium, plan.Workers[1].Tier)
+	assert.Equal(t, TierSmall, plan.Workers[2].Tier)
+
+	assert.Equal(t, []string{
+		"speakeasy-fanout-feature-new-api-my-java",
+		"speakeasy-fanout-feature-new-api-my-python",
+		"speakeasy-fanout-feature-new-api-docs",
+	}, plan.WorkerBranches())
+
+	// Planning is deterministic regardless of input order.
+	reversed := []Target{targets[3], targets[2], targets[1], targets[0]}
+	assert.Equal(t, plan, NewPlan("speakeasy-fanout", "feature/new-api", reversed))
+}
+
+func TestWorkerBranchName_Long(t *testing.T) {
+	name := WorkerBranchName("speakeasy-fanout", strings.Repeat("a", 150), strings.Repeat("b", 100))
+	assert.Equal(t, name, WorkerBranchName("speakeasy-fanout", strings.Repeat("a", 150), strings.Repeat("b", 100)))
+	assert.True(t, strings.HasPrefix(name, "speakeasy-fanout-"))
+	assert.Less(t, len(name), 64)
+}
+
+func TestPlan_Matrix(t *testing.T) {
+	plan := NewPlan("speakeasy-fanout", "main", []Target{
+		{ID: "ts", Language: "typescript", Source: "api", Cost: 6, Reasons: []string{"forced"}},
+	})
+
+	out, err := plan.Matrix(FormatGitHub)
+	require.NoError(t, err)
+
+	var gh struct {
+		Include []map[string]any `json:"include"`
+	}
+	require.NoError(t, json.Unmarshal(out, &gh))
+	require.Len(t, gh.Include, 1)
+	assert.Equal(t, "ts", gh.Include[0]["target"])
+	assert.Equal(t, "speakeasy-fanout-main-ts", gh.Include[0]["branch"])
+
+	out, err = plan.Matrix(FormatGitLab)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"parallel":{"matrix":[{"SPEAKEASY_TARGET":["ts"],"SPEAKEASY_WORKER_BRANCH":["speakeasy-fanout-main-ts"],"SPEAKEASY_COST_TIER":["large"]}]}}`, string(out))
+
+	_, err = plan.Matrix("jenkins")
+	assert.Error(t, err)
+}
+
+func TestPlan_EmptyMatrix(t *testing.T) {
+	plan := NewPlan("speakeasy-fanout", "main", nil)
+
+	out, err := plan.Matrix(FormatGitHub)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"include":[]}`, string(out))
+}
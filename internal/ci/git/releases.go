@@ -125,16 +125,26 @@ func (g *Git) CreateRelease(oldReleaseContent string, languages map[string]relea
 			logging.Info("INPUT_ENABLE_SDK_CHANGELOG: %s", environment.GetSDKChangelog())
 			logging.Info("targetSpecificReleaseNotes: %v", targetSpecificReleaseNotes)
 			logging.Info("targetSpecificReleaseNotes.HasReleaseNotesForTarget(lang): %v", targetSpecificReleaseNotes.HasReleaseNotesForTarget(lang))
-			if environment.GetSDKChangelog() == "true" && targetSpecificReleaseNotes.HasReleaseNotesForTarget(lang) {
-				releaseBody = targetSpecificReleaseNotes.GetReleaseNotesForTarget(lang)
-				fmt.Printf("Release Notes Body: \n%s\n\n", releaseBody)
+			if environment.GetReleaseNotesTemplates() != "" {
+				// The combined body is already rendered, and only targets with a
+				// rendered template of their own carry release notes.
+				if targetSpecificReleaseNotes.HasReleaseNotesForTarget(lang) {
+					releaseBody = targetSpecificReleaseNotes.GetReleaseNotesForTarget(lang)
+					fmt.Printf("Release Notes Body: \n%s\n\n", releaseBody)
+				}
+			} else {
+				if environment.GetSDKChangelog() == "true" && targetSpecificReleaseNotes.HasReleaseNotesForTarget(lang) {
+					releaseBody = targetSpecificReleaseNotes.GetReleaseNotesForTarget(lang)
+					fmt.Printf("Release Notes Body: \n%s\n\n", releaseBody)
+				}
+				releaseBody = releases.GeneratedReleaseBody(releaseBody)
 			}
 
 			release := &github.RepositoryRelease{
 				TagName:         tagName,
 				TargetCommitish: github.String(commitHash),
 				Name:            github.String(fmt.Sprintf("%s - %s - %s", lang, tag, environment.GetInvokeTime().Format("2006-01-02 15:04:05"))),
-				Body:            github.String(truncateReleaseBody(fmt.Sprintf(`# Generated by Speakeasy CLI%s`, releaseBody), len(publishingCompletedSuffix))),
+				Body:            github.String(truncateReleaseBody(releaseBody, len(publishingCompletedSuffix))),
 			}
 			if info.IsPrerelease() {
 				release.Prerelease = github.Bool(true)
@@ -0,0 +1,313 @@
+package publishing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrNotFound is returned by a Checker when the registry does not (yet) list
+// the requested package version.
+var ErrNotFound = errors.New("package version not found")
+
+// statusError is returned for an unexpected response from a registry.
+type statusError struct {
+	StatusCode int
+	Status     string
+	URL        string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status %s from %s", e.Status, e.URL)
+}
+
+// permanentError marks an error that retrying can't resolve, such as a
+// malformed package name.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// isRetryable reports whether a failed check may succeed later: the version
+// isn't listed yet, the registry failed or timed out, or the request didn't
+// reach it. Other client errors, such as 401, 403 or 400 for a malformed
+// package name, won't change by retrying.
+func isRetryable(err error) bool {
+	var permanent *permanentError
+	if errors.As(err, &permanent) {
+		return false
+	}
+
+	var status *statusError
+	if errors.As(err, &status) && status.StatusCode >= 400 && status.StatusCode < 500 {
+		return status.StatusCode == http.StatusRequestTimeout || status.StatusCode == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// DefaultEndpoints are the public metadata APIs for each supported registry,
+// keyed by the registry names used for publishing events.
+var DefaultEndpoints = map[string]string{
+	"npm":       "https://registry.npmjs.org",
+	"pypi":      "https://pypi.org",
+	"go":        "https://proxy.golang.org",
+	"sonatype":  "https://repo1.maven.org/maven2",
+	"nuget":     "https://api.nuget.org",
+	"gems":      "https://rubygems.org",
+	"packagist": "https://repo.packagist.org",
+}
+
+// Package identifies a published package version. For Maven packages Name is
+// "groupID:artifactID"; for Go it is the module path.
+type Package struct {
+	Registry string
+	Name     string
+	Version  string
+}
+
+func (p Package) String() string {
+	return fmt.Sprintf("%s@%s (%s)", p.Name, p.Version, p.Registry)
+}
+
+// Checker looks up a single package version, returning ErrNotFound if the
+// registry does not list it.
+type Checker func(ctx context.Context, client *http.Client, baseURL string, pkg Package) error
+
+var checkers = map[string]Checker{
+	"npm":       checkNPM,
+	"pypi":      checkPyPI,
+	"go":        checkGoProxy,
+	"sonatype":  checkMaven,
+	"nuget":     checkNuGet,
+	"gems":      checkRubyGems,
+	"packagist": checkPackagist,
+}
+
+// IsSupported reports whether the registry can be verified.
+func IsSupported(registry string) bool {
+	_, ok := checkers[registry]
+	return ok
+}
+
+// Verifier polls registry metadata APIs until a package version appears.
+type Verifier struct {
+	Client *http.Client
+	// Endpoints overrides DefaultEndpoints per registry, e.g. to point at a local stub.
+	Endpoints map[string]string
+	// Timeout bounds the total time spent polling a single package.
+	Timeout time.Duration
+	// InitialInterval is the first delay between attempts; it doubles on every
+	// attempt up to MaxInterval.
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+}
+
+// NewVerifier returns a Verifier with the default endpoints and backoff.
+func NewVerifier() *Verifier {
+	return &Verifier{
+		Client:          &http.Client{Timeout: 30 * time.Second},
+		Endpoints:       map[string]string{},
+		Timeout:         10 * time.Minute,
+		InitialInterval: 5 * time.Second,
+		MaxInterval:     time.Minute,
+	}
+}
+
+// ParseEndpoints parses `registry=url` pairs into endpoint overrides.
+func ParseEndpoints(pairs []string) (map[string]string, error) {
+	endpoints := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		registry, endpoint, ok := strings.Cut(pair, "=")
+		registry = strings.TrimSpace(registry)
+		endpoint = strings.TrimSpace(endpoint)
+		if !ok || registry == "" || endpoint == "" {
+			return nil, fmt.Errorf("invalid registry endpoint %q: expected registry=url", pair)
+		}
+		if !IsSupported(registry) {
+			return nil, fmt.Errorf("unsupported registry %q", registry)
+		}
+		if _, err := url.ParseRequestURI(endpoint); err != nil {
+			return nil, fmt.Errorf("invalid endpoint for registry %s: %w", registry, err)
+		}
+		endpoints[registry] = endpoint
+	}
+	return endpoints, nil
+}
+
+// Endpoint returns the base URL used for the registry.
+func (v *Verifier) Endpoint(registry string) string {
+	if endpoint, ok := v.Endpoints[registry]; ok {
+		return strings.TrimSuffix(endpoint, "/")
+	}
+	return DefaultEndpoints[registry]
+}
+
+// Verify polls the registry until the package version is listed or the
+// timeout elapses. Transient errors (network failures, 5xx responses) are
+// retried like a missing version; the last error is returned on timeout.
+// Client errors that retrying can't resolve are returned immediately.
+func (v *Verifier) Verify(ctx context.Context, pkg Package) error {
+	check, ok := checkers[pkg.Registry]
+	if !ok {
+		return fmt.Errorf("verification is not supported for registry %q", pkg.Registry)
+	}
+	if pkg.Name == "" || pkg.Version == "" {
+		return fmt.Errorf("package name and version are required to verify %s", pkg.Registry)
+	}
+
+	client := v.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	if v.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, v.Timeout)
+		defer cancel()
+	}
+
+	interval := v.InitialInterval
+	for {
+		err := check(ctx, client, v.Endpoint(pkg.Registry), pkg)
+		if err == nil {
+			return nil
+		}
+		if !isRetryable(err) {
+			return fmt.Errorf("failed to verify %s: %w", pkg, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s was not found within %s: %w", pkg, v.Timeout, err)
+		case <-time.After(interval):
+		}
+
+		if interval *= 2; v.MaxInterval > 0 && interval > v.MaxInterval {
+			interval = v.MaxInterval
+		}
+	}
+}
+
+// get fetches url, returning ErrNotFound for 404 responses. If out is non-nil
+// the JSON response body is decoded into it.
+func get(ctx context.Context, client *http.Client, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case res.StatusCode != http.StatusOK:
+		return &statusError{StatusCode: res.StatusCode, Status: res.Status, URL: url}
+	case out == nil:
+		_, _ = io.Copy(io.Discard, res.Body)
+		return nil
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response from %s: %w", url, err)
+	}
+	return nil
+}
+
+func checkNPM(ctx context.Context, client *http.Client, baseURL string, pkg Package) error {
+	// Scoped packages are addressed as @scope%2Fname.
+	var packument struct {
+		Versions map[string]json.RawMessage `json:"versions"`
+	}
+	if err := get(ctx, client, baseURL+"/"+strings.Replace(pkg.Name, "/", "%2F", 1), &packument); err != nil {
+		return err
+	}
+	if _, ok := packument.Versions[pkg.Version]; !ok {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func checkPyPI(ctx context.Context, client *http.Client, baseURL string, pkg Package) error {
+	return get(ctx, client, fmt.Sprintf("%s/pypi/%s/%s/json", baseURL, url.PathEscape(pkg.Name), url.PathEscape(pkg.Version)), nil)
+}
+
+func checkGoProxy(ctx context.Context, client *http.Client, baseURL string, pkg Package) error {
+	version := pkg.Version
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	return get(ctx, client, fmt.Sprintf("%s/%s/@v/%s.info", baseURL, escapeGoPath(pkg.Name), escapeGoPath(version)), nil)
+}
+
+// escapeGoPath applies the module proxy case encoding, replacing every upper
+// case letter with an exclamation mark followed by its lower case equivalent.
+func escapeGoPath(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsUpper(r) {
+			b.WriteByte('!')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func checkMaven(ctx context.Context, client *http.Client, baseURL string, pkg Package) error {
+	groupID, artifactID, ok := strings.Cut(pkg.Name, ":")
+	if !ok || groupID == "" || artifactID == "" {
+		return &permanentError{fmt.Errorf("maven package name must be groupID:artifactID, got %q", pkg.Name)}
+	}
+	return get(ctx, client, fmt.Sprintf("%s/%s/%s/%s/%s-%s.pom", baseURL, strings.ReplaceAll(groupID, ".", "/"), artifactID, pkg.Version, artifactID, pkg.Version), nil)
+}
+
+func checkNuGet(ctx context.Context, client *http.Client, baseURL string, pkg Package) error {
+	var index struct {
+		Versions []string `json:"versions"`
+	}
+	if err := get(ctx, client, fmt.Sprintf("%s/v3-flatcontainer/%s/index.json", baseURL, strings.ToLower(pkg.Name)), &index); err != nil {
+		return err
+	}
+	for _, v := range index.Versions {
+		if strings.EqualFold(v, pkg.Version) {
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func checkRubyGems(ctx context.Context, client *http.Client, baseURL string, pkg Package) error {
+	return get(ctx, client, fmt.Sprintf("%s/api/v2/rubygems/%s/versions/%s.json", baseURL, url.PathEscape(pkg.Name), url.PathEscape(pkg.Version)), nil)
+}
+
+func checkPackagist(ctx context.Context, client *http.Client, baseURL string, pkg Package) error {
+	var metadata struct {
+		Packages map[string][]struct {
+			Version string `json:"version"`
+		} `json:"packages"`
+	}
+	if err := get(ctx, client, fmt.Sprintf("%s/p2/%s.json", baseURL, pkg.Name), &metadata); err != nil {
+		return err
+	}
+	for _, v := range metadata.Packages[pkg.Name] {
+		if strings.TrimPrefix(v.Version, "v") == strings.TrimPrefix(pkg.Version, "v") {
+			return nil
+		}
+	}
+	return ErrNotFound
+}
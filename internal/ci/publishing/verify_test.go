@@ -0,0 +1,146 @@
+package publishing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStubRegistry(t *testing.T, routes map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := routes[r.URL.EscapedPath()]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestVerifier(endpoint string) *Verifier {
+	v := NewVerifier()
+	v.Timeout = 200 * time.Millisecond
+	v.InitialInterval = 10 * time.Millisecond
+	v.MaxInterval = 20 * time.Millisecond
+	for registry := range DefaultEndpoints {
+		v.Endpoints[registry] = endpoint
+	}
+	return v
+}
+
+func TestVerify_Registries(t *testing.T) {
+	server := newStubRegistry(t, map[string]string{
+		"/@acme%2Fsdk":                                  `{"versions":{"1.2.3":{}}}`,
+		"/pypi/acme-sdk/1.2.3/json":                     `{}`,
+		"/github.com/!acme/sdk/@v/v1.2.3.info":          `{}`,
+		"/com/acme/sdk/1.2.3/sdk-1.2.3.pom":             `<project/>`,
+		"/v3-flatcontainer/acme.sdk/index.json":         `{"versions":["1.2.2","1.2.3"]}`,
+		"/api/v2/rubygems/acme-sdk/versions/1.2.3.json": `{}`,
+		"/p2/acme/sdk.json":                             `{"packages":{"acme/sdk":[{"version":"v1.2.3"}]}}`,
+	})
+	v := newTestVerifier(server.URL)
+
+	for _, pkg := range []Package{
+		{Registry: "npm", Name: "@acme/sdk", Version: "1.2.3"},
+		{Registry: "pypi", Name: "acme-sdk", Version: "1.2.3"},
+		{Registry: "go", Name: "github.com/Acme/sdk", Version: "1.2.3"},
+		{Registry: "sonatype", Name: "com.acme:sdk", Version: "1.2.3"},
+		{Registry: "nuget", Name: "Acme.Sdk", Version: "1.2.3"},
+		{Registry: "gems", Name: "acme-sdk", Version: "1.2.3"},
+		{Registry: "packagist", Name: "acme/sdk", Version: "1.2.3"},
+	} {
+		t.Run(pkg.Registry, func(t *testing.T) {
+			assert.NoError(t, v.Verify(context.Background(), pkg))
+		})
+	}
+}
+
+func TestVerify_Missing(t *testing.T) {
+	server := newStubRegistry(t, map[string]string{
+		"/acme-sdk":                             `{"versions":{"1.2.2":{}}}`,
+		"/v3-flatcontainer/acme.sdk/index.json": `{"versions":["1.2.2"]}`,
+	})
+	v := newTestVerifier(server.URL)
+
+	for _, pkg := range []Package{
+		{Registry: "npm", Name: "acme-sdk", Version: "1.2.3"},
+		{Registry: "nuget", Name: "Acme.Sdk", Version: "1.2.3"},
+		{Registry: "pypi", Name: "acme-sdk", Version: "1.2.3"},
+	} {
+		err := v.Verify(context.Background(), pkg)
+		assert.ErrorIs(t, err, ErrNotFound, pkg.Registry)
+	}
+}
+
+func TestVerify_RetriesUntilAvailable(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch attempts.Add(1) {
+		case 1:
+			http.NotFound(w, r)
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	v := newTestVerifier(server.URL)
+	require.NoError(t, v.Verify(context.Background(), Package{Registry: "pypi", Name: "acme-sdk", Version: "1.2.3"}))
+	assert.Equal(t, int32(3), attempts.Load())
+}
+
+func TestVerify_FailsFastOnClientErrors(t *testing.T) {
+	for _, status := range []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden} {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.WriteHeader(status)
+		}))
+
+		v := newTestVerifier(server.URL)
+		v.Timeout = time.Minute
+		err := v.Verify(context.Background(), Package{Registry: "pypi", Name: "acme-sdk", Version: "1.2.3"})
+		server.Close()
+
+		assert.ErrorContains(t, err, http.StatusText(status))
+		assert.Equal(t, int32(1), attempts.Load(), "status %d should not be retried", status)
+	}
+
+	v := newTestVerifier("http://localhost")
+	v.Timeout = time.Minute
+	err := v.Verify(context.Background(), Package{Registry: "sonatype", Name: "sdk", Version: "1.2.3"})
+	assert.ErrorContains(t, err, "groupID:artifactID")
+}
+
+func TestVerify_Unsupported(t *testing.T) {
+	err := NewVerifier().Verify(context.Background(), Package{Registry: "terraform", Name: "acme/acme", Version: "1.0.0"})
+	assert.Error(t, err)
+}
+
+func TestParseEndpoints(t *testing.T) {
+	endpoints, err := ParseEndpoints([]string{"npm=http://localhost:4873/", " pypi = http://localhost:8080"})
+	require.NoError(t, err)
+
+	v := NewVerifier()
+	v.Endpoints = endpoints
+	assert.Equal(t, "http://localhost:4873", v.Endpoint("npm"))
+	assert.Equal(t, "http://localhost:8080", v.Endpoint("pypi"))
+	assert.Equal(t, DefaultEndpoints["gems"], v.Endpoint("gems"))
+
+	_, err = ParseEndpoints([]string{"npm"})
+	assert.Error(t, err)
+
+	_, err = ParseEndpoints([]string{"cargo=http://localhost"})
+	assert.Error(t, err)
+}
@@ -21,6 +21,8 @@ type LanguageReleaseInfo struct {
 	Version         string
 	PreviousVersion string
 	URL             string
+	// ReleaseNotes holds the changelog entries recorded in gen.lock, if any.
+	ReleaseNotes string
 }
 
 type GenerationInfo struct {
@@ -28,6 +30,12 @@ type GenerationInfo struct {
 	Path    string
 }
 
+// GeneratedReleaseBody prefixes release notes that were not rendered from a
+// template with the standard release header.
+func GeneratedReleaseBody(notes string) string {
+	return "# Generated by Speakeasy CLI" + notes
+}
+
 // TargetReleaseNotes maps workflow target name to their specific release content
 type TargetReleaseNotes map[string]string
 
@@ -85,53 +93,8 @@ func (r ReleasesInfo) String() string {
 	}
 
 	for lang, info := range r.Languages {
-		pkgID := ""
-		pkgURL := ""
-		switch lang {
-		case "go":
-			pkgID = "Go"
-			repoPath := os.Getenv("GITHUB_REPOSITORY")
-			tag := fmt.Sprintf("v%s", info.Version)
-			if info.Path != "." {
-				tag = fmt.Sprintf("%s/%s", info.Path, tag)
-			}
-
-			pkgURL = fmt.Sprintf("https://github.com/%s/releases/tag/%s", repoPath, tag)
-		case "typescript":
-			pkgID = "NPM"
-			pkgURL = fmt.Sprintf("https://www.npmjs.com/package/%s/v/%s", info.PackageName, info.Version)
-		case "python":
-			pkgID = "PyPI"
-			pkgURL = fmt.Sprintf("https://pypi.org/project/%s/%s", info.PackageName, info.Version)
-		case "php":
-			pkgID = "Composer"
-			pkgURL = fmt.Sprintf("https://packagist.org/packages/%s#v%s", info.PackageName, info.Version)
-		case "terraform":
-			pkgID = "Terraform"
-			pkgURL = fmt.Sprintf("https://registry.terraform.io/providers/%s/%s", info.PackageName, info.Version)
-		case "java":
-			pkgID = "Maven Central"
-			lastDotIndex := strings.LastIndex(info.PackageName, ".")
-			groupID := info.PackageName[:lastDotIndex]      // everything before last occurrence of '.'
-			artifactID := info.PackageName[lastDotIndex+1:] // everything after last occurrence of '.'
-			pkgURL = fmt.Sprintf("https://central.sonatype.com/artifact/%s/%s/%s", groupID, artifactID, info.Version)
-		case "ruby":
-			pkgID = "Ruby Gems"
-			pkgURL = fmt.Sprintf("https://rubygems.org/gems/%s/versions/%s", info.PackageName, info.Version)
-		case "csharp":
-			pkgID = "NuGet"
-			pkgURL = fmt.Sprintf("https://www.nuget.org/packages/%s/%s", info.PackageName, info.Version)
-		case "swift":
-			pkgID = "Swift Package Manager"
-			repoPath := os.Getenv("GITHUB_REPOSITORY")
-
-			tag := fmt.Sprintf("v%s", info.Version)
-			if info.Path != "." {
-				tag = fmt.Sprintf("%s/%s", info.Path, tag)
-			}
-
-			pkgURL = fmt.Sprintf("https://github.com/%s/releases/tag/%s", repoPath, tag)
-		}
+		pkgID := registryDisplayNames[lang]
+		pkgURL := PublishURL(lang, info.PackageName, info.Version, info.Path)
 
 		if pkgID != "" {
 			releasesOutput = append(releasesOutput, fmt.Sprintf("- [%s v%s] %s - %s", pkgID, info.Version, pkgURL, info.Path))
@@ -149,6 +112,56 @@ Based on:
 - Speakeasy CLI %s (%s) https://github.com/speakeasy-api/speakeasy%s%s`, "\n\n", r.ReleaseTitle, r.DocVersion, r.DocLocation, r.SpeakeasyVersion, r.GenerationVersion, strings.Join(generationOutput, "\n"), strings.Join(releasesOutput, "\n"))
 }
 
+// registryDisplayNames maps a target language to the registry name shown in release notes.
+var registryDisplayNames = map[string]string{
+	"go":         "Go",
+	"typescript": "NPM",
+	"python":     "PyPI",
+	"php":        "Composer",
+	"terraform":  "Terraform",
+	"java":       "Maven Central",
+	"ruby":       "Ruby Gems",
+	"csharp":     "NuGet",
+	"swift":      "Swift Package Manager",
+}
+
+// PublishURL returns the registry (or GitHub release) URL a package version is published to.
+// Returns an empty string for languages without a known registry.
+func PublishURL(lang, packageName, version, path string) string {
+	switch lang {
+	case "go", "swift":
+		repoPath := os.Getenv("GITHUB_REPOSITORY")
+		tag := fmt.Sprintf("v%s", version)
+		if path != "." {
+			tag = fmt.Sprintf("%s/%s", path, tag)
+		}
+
+		return fmt.Sprintf("https://github.com/%s/releases/tag/%s", repoPath, tag)
+	case "typescript":
+		return fmt.Sprintf("https://www.npmjs.com/package/%s/v/%s", packageName, version)
+	case "python":
+		return fmt.Sprintf("https://pypi.org/project/%s/%s", packageName, version)
+	case "php":
+		return fmt.Sprintf("https://packagist.org/packages/%s#v%s", packageName, version)
+	case "terraform":
+		return fmt.Sprintf("https://registry.terraform.io/providers/%s/%s", packageName, version)
+	case "java":
+		lastDotIndex := strings.LastIndex(packageName, ".")
+		if lastDotIndex < 0 {
+			return ""
+		}
+		groupID := packageName[:lastDotIndex]      // everything before last occurrence of '.'
+		artifactID := packageName[lastDotIndex+1:] // everything after last occurrence of '.'
+		return fmt.Sprintf("https://central.sonatype.com/artifact/%s/%s/%s", groupID, artifactID, version)
+	case "ruby":
+		return fmt.Sprintf("https://rubygems.org/gems/%s/versions/%s", packageName, version)
+	case "csharp":
+		return fmt.Sprintf("https://www.nuget.org/packages/%s/%s", packageName, version)
+	default:
+		return ""
+	}
+}
+
 func UpdateReleasesFile(releaseInfo ReleasesInfo, dir string) error {
 	releasesPath := GetReleasesPath(dir)
 
@@ -221,9 +234,10 @@ func GetReleaseInfoFromGenerationFiles(path string) (*ReleasesInfo, error) {
 
 	for lang, info := range cfgFile.Languages {
 		releaseInfo.Languages[lang] = LanguageReleaseInfo{
-			PackageName: utils.GetPackageName(lang, &info),
-			Version:     lockFile.Management.ReleaseVersion,
-			Path:        path,
+			PackageName:  utils.GetPackageName(lang, &info),
+			Version:      lockFile.Management.ReleaseVersion,
+			Path:         path,
+			ReleaseNotes: lockFile.ReleaseNotes,
 		}
 
 		releaseInfo.LanguagesGenerated[lang] = GenerationInfo{
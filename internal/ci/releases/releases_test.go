@@ -1,6 +1,8 @@
 package releases_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/speakeasy-api/speakeasy/internal/ci/releases"
@@ -476,3 +478,53 @@ func TestLanguageReleaseInfo_IsPrerelease(t *testing.T) {
 		assert.Equal(t, c.want, got, "Version %s: expected %v, got %v", c.version, c.want, got)
 	}
 }
+
+func TestReleaseNotesTemplates_Render(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY", "test/repo")
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, releases.ReleaseTemplateFile), []byte(`## {{ .Title }}
+{{ range .Targets }}- {{ .Language }} {{ .PackageName }}@{{ .Version }}: {{ .PublishURL }}
+{{ end }}{{ default "No API changes" .OpenAPIChanges }}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, releases.TargetTemplateFile), []byte(`{{ .PackageName }} {{ .Version }}{{ if .Prerelease }} (prerelease){{ end }}
+{{ trim .Changelog }}
+Based on {{ .Release.DocVersion }}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.md.tmpl"), []byte(`Go {{ .Version }} from {{ .Release.Repository }}`), 0o644))
+
+	templates, err := releases.LoadReleaseNotesTemplates(dir)
+	require.NoError(t, err)
+
+	data := releases.NewReleaseNotesData(releases.ReleasesInfo{
+		ReleaseTitle: "2024-01-01 00:00:00",
+		DocVersion:   "1.0.0",
+		Languages: map[string]releases.LanguageReleaseInfo{
+			"typescript": {PackageName: "@org/sdk", Version: "1.2.3-beta.1", Path: ".", ReleaseNotes: "\n- feat: added pets\n"},
+			"go":         {PackageName: "github.com/test/repo", Version: "0.4.0", Path: "."},
+		},
+	}, releases.ReleaseNotesExtras{})
+
+	release, ok, err := templates.RenderRelease(data)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, `## 2024-01-01 00:00:00
+- go github.com/test/repo@0.4.0: https://github.com/test/repo/releases/tag/v0.4.0
+- typescript @org/sdk@1.2.3-beta.1: https://www.npmjs.com/package/@org/sdk/v/1.2.3-beta.1
+No API changes`, release)
+
+	targets, err := templates.RenderTargets(data)
+	require.NoError(t, err)
+	assert.Equal(t, releases.TargetReleaseNotes{
+		"go":         "Go 0.4.0 from test/repo",
+		"typescript": "@org/sdk 1.2.3-beta.1 (prerelease)\n- feat: added pets\nBased on 1.0.0",
+	}, targets)
+}
+
+func TestLoadReleaseNotesTemplates_Errors(t *testing.T) {
+	_, err := releases.LoadReleaseNotesTemplates(t.TempDir())
+	assert.Error(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, releases.ReleaseTemplateFile), []byte(`{{ .Title `), 0o644))
+	_, err = releases.LoadReleaseNotesTemplates(dir)
+	assert.Error(t, err)
+}
@@ -0,0 +1,230 @@
+package releases
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	version "github.com/hashicorp/go-version"
+	"github.com/speakeasy-api/versioning-reports/versioning"
+)
+
+const (
+	// ReleaseTemplateFile renders the body of the combined GitHub release.
+	ReleaseTemplateFile = "release.md.tmpl"
+	// TargetTemplateFile renders the body of each per-target GitHub release,
+	// unless a language specific <language>.md.tmpl exists.
+	TargetTemplateFile = "target.md.tmpl"
+)
+
+// TargetNotesData is the data available to per-target release note templates.
+type TargetNotesData struct {
+	Language        string
+	PackageName     string
+	Version         string
+	PreviousVersion string
+	Path            string
+	PublishURL      string
+	// Changelog holds the changelog entries recorded in gen.lock.
+	Changelog  string
+	Prerelease bool
+	// Release is the release the target is part of.
+	Release *ReleaseNotesData
+}
+
+// ReleaseNotesData is the data available to release note templates.
+type ReleaseNotesData struct {
+	Title             string
+	DocVersion        string
+	DocLocation       string
+	SpeakeasyVersion  string
+	GenerationVersion string
+	Repository        string
+	// Targets are sorted by language.
+	Targets       []TargetNotesData
+	VersionReport *versioning.MergedVersionReport
+	// OpenAPIChanges is the markdown summary of changes to the OpenAPI document.
+	OpenAPIChanges   string
+	ChangesReportURL string
+}
+
+// ReleaseNotesExtras carries generation report data that is not part of ReleasesInfo.
+type ReleaseNotesExtras struct {
+	VersionReport    *versioning.MergedVersionReport
+	OpenAPIChanges   string
+	ChangesReportURL string
+}
+
+// NewReleaseNotesData builds template data from release info.
+func NewReleaseNotesData(info ReleasesInfo, extras ReleaseNotesExtras) *ReleaseNotesData {
+	data := &ReleaseNotesData{
+		Title:             info.ReleaseTitle,
+		DocVersion:        info.DocVersion,
+		DocLocation:       info.DocLocation,
+		SpeakeasyVersion:  info.SpeakeasyVersion,
+		GenerationVersion: info.GenerationVersion,
+		Repository:        os.Getenv("GITHUB_REPOSITORY"),
+		VersionReport:     extras.VersionReport,
+		OpenAPIChanges:    extras.OpenAPIChanges,
+		ChangesReportURL:  extras.ChangesReportURL,
+	}
+
+	languages := make([]string, 0, len(info.Languages))
+	for lang := range info.Languages {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+
+	for _, lang := range languages {
+		langInfo := info.Languages[lang]
+		publishURL := langInfo.URL
+		if publishURL == "" {
+			publishURL = PublishURL(lang, langInfo.PackageName, langInfo.Version, langInfo.Path)
+		}
+
+		data.Targets = append(data.Targets, TargetNotesData{
+			Language:        lang,
+			PackageName:     langInfo.PackageName,
+			Version:         langInfo.Version,
+			PreviousVersion: langInfo.PreviousVersion,
+			Path:            langInfo.Path,
+			PublishURL:      publishURL,
+			Changelog:       langInfo.ReleaseNotes,
+			Prerelease:      isPrereleaseVersion(langInfo.Version),
+			Release:         data,
+		})
+	}
+
+	return data
+}
+
+func isPrereleaseVersion(v string) bool {
+	parsed, err := version.NewVersion(v)
+	return err == nil && parsed.Prerelease() != ""
+}
+
+// ReleaseNotesTemplates holds user supplied release note templates, loaded
+// from a directory containing release.md.tmpl, target.md.tmpl and/or
+// <language>.md.tmpl files.
+type ReleaseNotesTemplates struct {
+	release *template.Template
+	target  *template.Template
+	byLang  map[string]*template.Template
+}
+
+var templateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"trim":  strings.TrimSpace,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"indent": func(spaces int, s string) string {
+		pad := strings.Repeat(" ", spaces)
+		return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+	},
+	"default": func(fallback, value string) string {
+		if strings.TrimSpace(value) == "" {
+			return fallback
+		}
+		return value
+	},
+}
+
+// LoadReleaseNotesTemplates parses all templates in dir. It is an error for
+// the directory to contain no templates.
+func LoadReleaseNotesTemplates(dir string) (*ReleaseNotesTemplates, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read release notes templates directory: %w", err)
+	}
+
+	t := &ReleaseNotesTemplates{byLang: map[string]*template.Template{}}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md.tmpl") {
+			continue
+		}
+
+		tmpl, err := parseTemplateFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		switch name := entry.Name(); name {
+		case ReleaseTemplateFile:
+			t.release = tmpl
+		case TargetTemplateFile:
+			t.target = tmpl
+		default:
+			t.byLang[strings.TrimSuffix(name, ".md.tmpl")] = tmpl
+		}
+	}
+
+	if t.release == nil && t.target == nil && len(t.byLang) == 0 {
+		return nil, fmt.Errorf("no *.md.tmpl release notes templates found in %s", dir)
+	}
+
+	return t, nil
+}
+
+func parseTemplateFile(path string) (*template.Template, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read release notes template %s: %w", path, err)
+	}
+
+	tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse release notes template %s: %w", path, err)
+	}
+
+	return tmpl, nil
+}
+
+// RenderRelease renders the combined release body. The boolean result is
+// false if no release template was supplied.
+func (t *ReleaseNotesTemplates) RenderRelease(data *ReleaseNotesData) (string, bool, error) {
+	if t == nil || t.release == nil {
+		return "", false, nil
+	}
+
+	out, err := execute(t.release, data)
+	return out, true, err
+}
+
+// RenderTargets renders the per-target release bodies, keyed by language.
+// Languages without an applicable template are omitted.
+func (t *ReleaseNotesTemplates) RenderTargets(data *ReleaseNotesData) (TargetReleaseNotes, error) {
+	notes := TargetReleaseNotes{}
+	if t == nil {
+		return notes, nil
+	}
+
+	for _, target := range data.Targets {
+		tmpl, ok := t.byLang[target.Language]
+		if !ok {
+			tmpl = t.target
+		}
+		if tmpl == nil {
+			continue
+		}
+
+		out, err := execute(tmpl, target)
+		if err != nil {
+			return nil, err
+		}
+		notes[target.Language] = out
+	}
+
+	return notes, nil
+}
+
+func execute(tmpl *template.Template, data any) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render release notes template %s: %w", tmpl.Name(), err)
+	}
+	return buf.String(), nil
+}
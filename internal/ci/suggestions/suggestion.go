@@ -39,7 +39,7 @@ func Suggest(ctx context.Context, docPath, maxSuggestions string) (string, error
 	defer outFile.Close()
 
 	yamlOut := utils.HasYAMLExt(outputPath)
-	if err := suggest.SuggestAndWrite(ctx, "method-names", docPath, false, yamlOut, outFile); err != nil {
+	if err := suggest.SuggestAndWrite(ctx, "method-names", docPath, false, yamlOut, false, outFile); err != nil {
 		return "", err
 	}
 
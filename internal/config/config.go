@@ -42,6 +42,18 @@ func Load() error {
 		}
 	}
 
+	s, err := NewCredentialStore()
+	if err != nil {
+		return err
+	}
+	store = s
+
+	// Keys are only migrated once, so a failure is left to the next run rather
+	// than failing the command.
+	if err := migrateCredentials(); err != nil {
+		warnf("Failed to move API keys out of %s: %s", vCfg.ConfigFileUsed(), err.Error())
+	}
+
 	return nil
 }
 
@@ -52,7 +64,7 @@ func GetCustomerID() string {
 func GetSpeakeasyAPIKey() string {
 	apiKey := os.Getenv("SPEAKEASY_API_KEY")
 	if apiKey == "" {
-		return vCfg.GetString("speakeasy_api_key")
+		return getCredential(speakeasyAPIKeyKey)
 	}
 
 	return apiKey
@@ -67,23 +79,25 @@ func GetStudioSecret() string {
 }
 
 func GetWorkspaceAPIKey(orgSlug, workspaceSlug string) string {
-	keys := vCfg.GetStringMapString(workspaceKeysKey)
-	if keys == nil {
-		keys = make(map[string]string)
-	}
-
-	return keys[getWorkspaceKey(orgSlug, workspaceSlug)]
+	return getCredential(workspaceCredential(getWorkspaceKey(orgSlug, workspaceSlug)))
 }
 
 func SetWorkspaceAPIKey(orgSlug, workspaceSlug, key string) error {
+	workspaceKey := getWorkspaceKey(orgSlug, workspaceSlug)
+	if err := setCredential(workspaceCredential(workspaceKey), key); err != nil {
+		return err
+	}
+
+	// The config lists the authenticated workspaces, even where their keys are
+	// kept in the credential store.
 	keys := vCfg.GetStringMapString(workspaceKeysKey)
 	if keys == nil {
 		keys = make(map[string]string)
 	}
-
-	keys[getWorkspaceKey(orgSlug, workspaceSlug)] = key
-
-	vCfg.Set(workspaceKeysKey, keys)
+	if _, ok := keys[workspaceKey]; !ok {
+		keys[workspaceKey] = ""
+		vCfg.Set(workspaceKeysKey, keys)
+	}
 
 	return save()
 }
@@ -104,7 +118,9 @@ func SetStudioSecret(secret string) error {
 func SetSpeakeasyAuthInfo(ctx context.Context, info core.SpeakeasyAuthInfo) error {
 	// Keep speakeasy-self as default workspace
 	if vCfg.GetString("speakeasy_workspace_id") != "self" {
-		vCfg.Set("speakeasy_api_key", info.APIKey)
+		if err := setCredential(speakeasyAPIKeyKey, info.APIKey); err != nil {
+			return err
+		}
 		vCfg.Set("speakeasy_workspace_id", info.WorkspaceID)
 		vCfg.Set("speakeasy_customer_id", info.CustomerID)
 	} else if info.WorkspaceID != "self" {
@@ -119,12 +135,14 @@ func SetSpeakeasyAuthInfo(ctx context.Context, info core.SpeakeasyAuthInfo) erro
 }
 
 func SetSpeakeasyAPIKey(apiKey string) error {
-	vCfg.Set("speakeasy_api_key", apiKey)
-	return save()
+	return setCredential(speakeasyAPIKeyKey, apiKey)
 }
 
 func ClearSpeakeasyAuthInfo() error {
-	vCfg.Set("speakeasy_api_key", "")
+	if err := eraseCredential(speakeasyAPIKeyKey); err != nil {
+		return err
+	}
+	vCfg.Set(speakeasyAPIKeyKey, "")
 	vCfg.Set("speakeasy_workspace_id", "")
 	vCfg.Set("speakeasy_customer_id", "")
 	vCfg.Set("speakeasy_studio_secret", "")
@@ -0,0 +1,190 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/speakeasy-api/speakeasy/internal/charm/styles"
+)
+
+// CredentialStore keeps the API keys of the CLI. Credentials are identified by
+// name, such as speakeasy_api_key or workspace:<org>@<workspace>.
+type CredentialStore interface {
+	// Name is the name the store is selected by.
+	Name() string
+	// Get returns the credential, or an empty string if there's none.
+	Get(name string) (string, error)
+	Set(name, secret string) error
+	// Erase removes the credential, if there is one.
+	Erase(name string) error
+}
+
+const (
+	CredentialStoreAuto      = "auto"
+	CredentialStoreKeyring   = "keyring"
+	CredentialStoreFile      = "file"
+	CredentialStoreHelper    = "helper"
+	CredentialStorePlaintext = "plaintext"
+
+	credentialStoreKey  = "credential_store"
+	credentialHelperKey = "credential_helper"
+
+	speakeasyAPIKeyKey        = "speakeasy_api_key"
+	workspaceCredentialPrefix = "workspace:"
+)
+
+var store CredentialStore
+
+// NewCredentialStore returns the credential store selected by
+// SPEAKEASY_CREDENTIAL_STORE or credential_store in the config:
+//   - keyring: the OS keyring, the Secret Service on Linux or the Keychain on macOS.
+//   - file: a file in ~/.speakeasy encrypted with SPEAKEASY_CREDENTIALS_PASSPHRASE,
+//     prompted for if unset.
+//   - helper: an external credential helper set by SPEAKEASY_CREDENTIAL_HELPER or
+//     credential_helper, see helperStore.
+//   - plaintext: the config file itself.
+//
+// auto, the default, is keyring where there is one and plaintext elsewhere.
+func NewCredentialStore() (CredentialStore, error) {
+	name := os.Getenv("SPEAKEASY_CREDENTIAL_STORE")
+	if name == "" {
+		name = vCfg.GetString(credentialStoreKey)
+	}
+
+	switch name {
+	case "", CredentialStoreAuto:
+		if keyringAvailable() {
+			return keyringStore{}, nil
+		}
+		return plaintextStore{}, nil
+	case CredentialStoreKeyring:
+		if !keyringAvailable() {
+			return nil, fmt.Errorf("no OS keyring available, the keyring credential store needs secret-tool with a Secret Service on Linux or the Keychain on macOS")
+		}
+		return keyringStore{}, nil
+	case CredentialStoreFile:
+		return newFileStore(), nil
+	case CredentialStoreHelper:
+		helper := os.Getenv("SPEAKEASY_CREDENTIAL_HELPER")
+		if helper == "" {
+			helper = vCfg.GetString(credentialHelperKey)
+		}
+		if helper == "" {
+			return nil, fmt.Errorf("the helper credential store needs a credential helper, set %s in the config or SPEAKEASY_CREDENTIAL_HELPER", credentialHelperKey)
+		}
+		return helperStore{helper: helper}, nil
+	case CredentialStorePlaintext:
+		return plaintextStore{}, nil
+	default:
+		return nil, fmt.Errorf("unknown credential store %s, expected one of %s", name, strings.Join([]string{CredentialStoreAuto, CredentialStoreKeyring, CredentialStoreFile, CredentialStoreHelper, CredentialStorePlaintext}, ", "))
+	}
+}
+
+func credentials() CredentialStore {
+	if store == nil {
+		return plaintextStore{}
+	}
+	return store
+}
+
+func getCredential(name string) string {
+	secret, err := credentials().Get(name)
+	if err != nil {
+		warnf("Failed to read %s from the %s credential store: %s", name, credentials().Name(), err.Error())
+		return ""
+	}
+	return secret
+}
+
+func setCredential(name, secret string) error {
+	if err := credentials().Set(name, secret); err != nil {
+		return fmt.Errorf("failed to write %s to the %s credential store: %w", name, credentials().Name(), err)
+	}
+	return nil
+}
+
+func eraseCredential(name string) error {
+	if err := credentials().Erase(name); err != nil {
+		return fmt.Errorf("failed to erase %s from the %s credential store: %w", name, credentials().Name(), err)
+	}
+	return nil
+}
+
+func workspaceCredential(workspaceKey string) string {
+	return workspaceCredentialPrefix + workspaceKey
+}
+
+// migrateCredentials moves API keys left in plaintext in the config into the
+// credential store. The config keeps the authenticated workspaces, without
+// their keys.
+func migrateCredentials() error {
+	if _, ok := credentials().(plaintextStore); ok {
+		return nil
+	}
+
+	migrated := false
+
+	if apiKey := vCfg.GetString(speakeasyAPIKeyKey); apiKey != "" {
+		if err := setCredential(speakeasyAPIKeyKey, apiKey); err != nil {
+			return err
+		}
+		vCfg.Set(speakeasyAPIKeyKey, "")
+		migrated = true
+	}
+
+	keys := vCfg.GetStringMapString(workspaceKeysKey)
+	for workspaceKey, apiKey := range keys {
+		if apiKey == "" {
+			continue
+		}
+		if err := setCredential(workspaceCredential(workspaceKey), apiKey); err != nil {
+			return err
+		}
+		keys[workspaceKey] = ""
+		migrated = true
+	}
+
+	if !migrated {
+		return nil
+	}
+
+	vCfg.Set(workspaceKeysKey, keys)
+	return save()
+}
+
+func warnf(format string, a ...any) {
+	fmt.Fprintln(os.Stderr, styles.Warning.Render(fmt.Sprintf(format, a...)))
+}
+
+// plaintextStore keeps credentials in the config file, as the CLI always has.
+type plaintextStore struct{}
+
+func (plaintextStore) Name() string {
+	return CredentialStorePlaintext
+}
+
+func (plaintextStore) Get(name string) (string, error) {
+	if workspaceKey, ok := strings.CutPrefix(name, workspaceCredentialPrefix); ok {
+		return vCfg.GetStringMapString(workspaceKeysKey)[workspaceKey], nil
+	}
+	return vCfg.GetString(name), nil
+}
+
+func (plaintextStore) Set(name, secret string) error {
+	if workspaceKey, ok := strings.CutPrefix(name, workspaceCredentialPrefix); ok {
+		keys := vCfg.GetStringMapString(workspaceKeysKey)
+		if keys == nil {
+			keys = make(map[string]string)
+		}
+		keys[workspaceKey] = secret
+		vCfg.Set(workspaceKeysKey, keys)
+	} else {
+		vCfg.Set(name, secret)
+	}
+	return save()
+}
+
+func (s plaintextStore) Erase(name string) error {
+	return s.Set(name, "")
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), credentialsFileName)
+	s := &fileStore{path: path, passphrase: func() (string, error) { return "correct horse", nil }}
+
+	secret, err := s.Get(speakeasyAPIKeyKey)
+	require.NoError(t, err)
+	assert.Empty(t, secret)
+
+	require.NoError(t, s.Set(speakeasyAPIKeyKey, "key-1"))
+	require.NoError(t, s.Set(workspaceCredential("org@workspace"), "key-2"))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "key-1")
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(credentialsFilePermission), info.Mode().Perm())
+	}
+
+	reopened := &fileStore{path: path, passphrase: func() (string, error) { return "correct horse", nil }}
+	secret, err = reopened.Get(workspaceCredential("org@workspace"))
+	require.NoError(t, err)
+	assert.Equal(t, "key-2", secret)
+
+	require.NoError(t, reopened.Erase(speakeasyAPIKeyKey))
+	secret, err = reopened.Get(speakeasyAPIKeyKey)
+	require.NoError(t, err)
+	assert.Empty(t, secret)
+
+	wrong := &fileStore{path: path, passphrase: func() (string, error) { return "wrong", nil }}
+	_, err = wrong.Get(speakeasyAPIKeyKey)
+	assert.ErrorContains(t, err, "is the passphrase correct?")
+}
+
+const testCredentialHelper = `#!/bin/sh
+dir=$(dirname "$0")
+while IFS= read -r line && [ -n "$line" ]; do
+	case "$line" in
+	name=*) name=${line#name=} ;;
+	secret=*) secret=${line#secret=} ;;
+	esac
+done
+case "$1" in
+get) [ -f "$dir/$name" ] && printf 'secret=%s\n' "$(cat "$dir/$name")" ;;
+store) printf '%s' "$secret" >"$dir/$name" ;;
+erase) rm -f "$dir/$name" ;;
+esac
+exit 0
+`
+
+func TestHelperStore(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test helper is a shell script")
+	}
+
+	helper := filepath.Join(t.TempDir(), "helper")
+	require.NoError(t, os.WriteFile(helper, []byte(testCredentialHelper), 0o755))
+
+	for name, s := range map[string]helperStore{
+		"path":  {helper: helper},
+		"shell": {helper: "!" + helper},
+	} {
+		t.Run(name, func(t *testing.T) {
+			secret, err := s.Get(speakeasyAPIKeyKey)
+			require.NoError(t, err)
+			assert.Empty(t, secret)
+
+			require.NoError(t, s.Set(speakeasyAPIKeyKey, "key-1"))
+			secret, err = s.Get(speakeasyAPIKeyKey)
+			require.NoError(t, err)
+			assert.Equal(t, "key-1", secret)
+
+			require.NoError(t, s.Erase(speakeasyAPIKeyKey))
+			secret, err = s.Get(speakeasyAPIKeyKey)
+			require.NoError(t, err)
+			assert.Empty(t, secret)
+		})
+	}
+
+	assert.Error(t, helperStore{helper: helper}.Set(speakeasyAPIKeyKey, "multi\nline"))
+}
+
+func TestLoadMigratesCredentials(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("SPEAKEASY_API_KEY", "")
+	t.Setenv("SPEAKEASY_CREDENTIAL_STORE", CredentialStoreFile)
+	t.Setenv(credentialsPassphraseEnv, "correct horse")
+
+	vCfg = viper.New()
+	t.Cleanup(func() {
+		vCfg = viper.New()
+		store = nil
+	})
+
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".speakeasy"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".speakeasy", "config.yaml"), []byte(`speakeasy_api_key: key-1
+speakeasy_workspace_id: workspace-id
+workspace_api_keys:
+  org@workspace: key-2
+`), 0o644))
+
+	require.NoError(t, Load())
+
+	assert.Equal(t, "key-1", GetSpeakeasyAPIKey())
+	assert.Equal(t, "key-2", GetWorkspaceAPIKey("org", "workspace"))
+	assert.Equal(t, []string{"org@workspace"}, GetAuthenticatedWorkspaces())
+	assert.Equal(t, "workspace-id", GetWorkspaceID())
+
+	data, err := os.ReadFile(filepath.Join(home, ".speakeasy", "config.yaml"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "key-1")
+	assert.NotContains(t, string(data), "key-2")
+	assert.Contains(t, string(data), "org@workspace")
+
+	require.NoError(t, SetWorkspaceAPIKey("org", "other", "key-3"))
+	assert.Equal(t, "key-3", GetWorkspaceAPIKey("org", "other"))
+	assert.ElementsMatch(t, []string{"org@workspace", "org@other"}, GetAuthenticatedWorkspaces())
+
+	require.NoError(t, ClearSpeakeasyAuthInfo())
+	assert.Empty(t, GetSpeakeasyAPIKey())
+}
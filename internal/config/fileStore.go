@@ -0,0 +1,230 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+const (
+	credentialsFileName       = "credentials.enc"
+	credentialsFileVersion    = 1
+	credentialsPBKDF2Iter     = 600_000
+	credentialsPassphraseEnv  = "SPEAKEASY_CREDENTIALS_PASSPHRASE"
+	credentialsKeyLength      = 32
+	credentialsSaltLength     = 16
+	credentialsFilePermission = 0o600
+)
+
+// fileStore keeps credentials in a file encrypted with AES-256-GCM, with a key
+// derived from a passphrase using PBKDF2.
+type fileStore struct {
+	path       string
+	passphrase func() (string, error)
+
+	// key caches the key derived for salt, as deriving it is deliberately slow.
+	key  []byte
+	salt []byte
+}
+
+type credentialsFile struct {
+	Version    int    `json:"version"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func newFileStore() *fileStore {
+	return &fileStore{
+		path:       filepath.Join(cfgDir, credentialsFileName),
+		passphrase: credentialsPassphrase,
+	}
+}
+
+// promptedPassphrase is the passphrase prompted for, kept so it is only
+// asked for once per command.
+var (
+	promptedPassphraseMu sync.Mutex
+	promptedPassphrase   string
+)
+
+// credentialsPassphrase reads the passphrase of the credentials file from
+// SPEAKEASY_CREDENTIALS_PASSPHRASE, or prompts for it once.
+func credentialsPassphrase() (string, error) {
+	if passphrase := os.Getenv(credentialsPassphraseEnv); passphrase != "" {
+		return passphrase, nil
+	}
+
+	promptedPassphraseMu.Lock()
+	defer promptedPassphraseMu.Unlock()
+	if promptedPassphrase != "" {
+		return promptedPassphrase, nil
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("the file credential store needs a passphrase, set %s", credentialsPassphraseEnv)
+	}
+
+	fmt.Fprint(os.Stderr, "Passphrase for the Speakeasy credentials file: ")
+	passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	if len(passphrase) == 0 {
+		return "", fmt.Errorf("the file credential store needs a passphrase")
+	}
+
+	// Don't prompt again for the rest of the command, without exposing the
+	// passphrase to the processes it starts.
+	promptedPassphrase = string(passphrase)
+	return promptedPassphrase, nil
+}
+
+func (s *fileStore) Name() string {
+	return CredentialStoreFile
+}
+
+func (s *fileStore) Get(name string) (string, error) {
+	secrets, err := s.read()
+	if err != nil {
+		return "", err
+	}
+	return secrets[name], nil
+}
+
+func (s *fileStore) Set(name, secret string) error {
+	secrets, err := s.read()
+	if err != nil {
+		return err
+	}
+	secrets[name] = secret
+	return s.write(secrets)
+}
+
+func (s *fileStore) Erase(name string) error {
+	secrets, err := s.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := secrets[name]; !ok {
+		return nil
+	}
+	delete(secrets, name)
+	return s.write(secrets)
+}
+
+func (s *fileStore) read() (map[string]string, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+
+	var f credentialsFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials file %s: %w", s.path, err)
+	}
+	if f.Version != credentialsFileVersion {
+		return nil, fmt.Errorf("unsupported credentials file version %d", f.Version)
+	}
+
+	gcm, err := s.cipher(f.Salt)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := gcm.Open(nil, f.Nonce, f.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt credentials file %s, is the passphrase correct?", s.path)
+	}
+
+	secrets := map[string]string{}
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials file %s: %w", s.path, err)
+	}
+	return secrets, nil
+}
+
+func (s *fileStore) write(secrets map[string]string) error {
+	salt := s.salt
+	if salt == nil {
+		salt = make([]byte, credentialsSaltLength)
+		if _, err := rand.Read(salt); err != nil {
+			return err
+		}
+	}
+
+	gcm, err := s.cipher(salt)
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(credentialsFile{
+		Version:    credentialsFileVersion,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, nil),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+
+	// Write to a temporary file and rename it over the credentials file, so an
+	// interrupted write can't lose every credential.
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, credentialsFilePermission); err != nil {
+		return fmt.Errorf("failed to write credentials file: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write credentials file: %w", err)
+	}
+	return nil
+}
+
+func (s *fileStore) cipher(salt []byte) (cipher.AEAD, error) {
+	if s.key == nil || string(s.salt) != string(salt) {
+		passphrase, err := s.passphrase()
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := pbkdf2.Key(sha256.New, passphrase, salt, credentialsPBKDF2Iter, credentialsKeyLength)
+		if err != nil {
+			return nil, err
+		}
+		s.key, s.salt = key, salt
+	}
+
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
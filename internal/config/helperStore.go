@@ -0,0 +1,93 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// helperStore delegates credentials to an external credential helper, with a
+// protocol modelled on git's. The helper is run with an action, get, store or
+// erase, and reads the credential's attributes from stdin as key=value lines
+// ending with a blank line:
+//
+//	name=speakeasy_api_key
+//	secret=<secret>
+//
+// secret is only sent to store. For get, the helper writes secret=<secret> to
+// stdout, or nothing if it has no such credential.
+//
+// As with git, a helper starting with ! is a shell command, an absolute path
+// is run as is, and any other helper <name> runs speakeasy-credential-<name>
+// from the PATH.
+type helperStore struct {
+	helper string
+}
+
+func (s helperStore) Name() string {
+	return CredentialStoreHelper
+}
+
+func (s helperStore) Get(name string) (string, error) {
+	out, err := s.run("get", map[string]string{"name": name})
+	if err != nil {
+		return "", err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if secret, ok := strings.CutPrefix(scanner.Text(), "secret="); ok {
+			return secret, nil
+		}
+	}
+	return "", scanner.Err()
+}
+
+func (s helperStore) Set(name, secret string) error {
+	_, err := s.run("store", map[string]string{"name": name, "secret": secret})
+	return err
+}
+
+func (s helperStore) Erase(name string) error {
+	_, err := s.run("erase", map[string]string{"name": name})
+	return err
+}
+
+func (s helperStore) run(action string, attributes map[string]string) ([]byte, error) {
+	var cmd *exec.Cmd
+	switch {
+	case strings.HasPrefix(s.helper, "!"):
+		cmd = exec.Command("sh", "-c", s.helper[1:]+` "$@"`, s.helper[1:], action)
+	case filepath.IsAbs(s.helper):
+		cmd = exec.Command(s.helper, action)
+	default:
+		cmd = exec.Command("speakeasy-credential-"+s.helper, action)
+	}
+
+	var stdin strings.Builder
+	for _, key := range []string{"name", "secret"} {
+		if value, ok := attributes[key]; ok {
+			if strings.ContainsAny(value, "\n\x00") {
+				return nil, fmt.Errorf("credential %s can't contain newlines", key)
+			}
+			fmt.Fprintf(&stdin, "%s=%s\n", key, value)
+		}
+	}
+	stdin.WriteString("\n")
+	cmd.Stdin = strings.NewReader(stdin.String())
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("credential helper %s %s: %w: %s", s.helper, action, err, msg)
+		}
+		return nil, fmt.Errorf("credential helper %s %s: %w", s.helper, action, err)
+	}
+	return out, nil
+}
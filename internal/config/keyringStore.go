@@ -0,0 +1,115 @@
+package config
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+const keyringService = "speakeasy"
+
+// keyringStore keeps credentials in the OS keyring: the freedesktop Secret
+// Service through secret-tool on Linux, and the login Keychain through
+// security on macOS.
+type keyringStore struct{}
+
+func keyringAvailable() bool {
+	switch runtime.GOOS {
+	case "linux", "freebsd", "openbsd":
+		if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+			return false
+		}
+		_, err := exec.LookPath("secret-tool")
+		return err == nil
+	case "darwin":
+		_, err := exec.LookPath("security")
+		return err == nil
+	default:
+		return false
+	}
+}
+
+func (keyringStore) Name() string {
+	return CredentialStoreKeyring
+}
+
+func (keyringStore) Get(name string) (string, error) {
+	if runtime.GOOS == "darwin" {
+		out, err := runKeyringCommand(nil, "security", "find-generic-password", "-s", keyringService, "-a", name, "-w")
+		if isKeychainNotFound(err) {
+			return "", nil
+		}
+		return strings.TrimSuffix(out, "\n"), err
+	}
+
+	out, err := runKeyringCommand(nil, "secret-tool", "lookup", "service", keyringService, "account", name)
+	var exitErr *exec.ExitError
+	// secret-tool exits with 1 and says nothing when there is no such secret.
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && len(exitErr.Stderr) == 0 {
+		return "", nil
+	}
+	return out, err
+}
+
+func (keyringStore) Set(name, secret string) error {
+	if runtime.GOOS == "darwin" {
+		// The secret is passed on stdin in hex, rather than as an argument, so it
+		// doesn't show in the process list.
+		command := fmt.Sprintf("add-generic-password -U -s %s -a %s -X %s\n", quoteKeychainArg(keyringService), quoteKeychainArg(name), hex.EncodeToString([]byte(secret)))
+		_, err := runKeyringCommand(strings.NewReader(command), "security", "-i")
+		return err
+	}
+
+	_, err := runKeyringCommand(strings.NewReader(secret), "secret-tool", "store", "--label", "Speakeasy CLI "+name, "service", keyringService, "account", name)
+	return err
+}
+
+func (keyringStore) Erase(name string) error {
+	if runtime.GOOS == "darwin" {
+		_, err := runKeyringCommand(nil, "security", "delete-generic-password", "-s", keyringService, "-a", name)
+		if isKeychainNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	_, err := runKeyringCommand(nil, "secret-tool", "clear", "service", keyringService, "account", name)
+	return err
+}
+
+func runKeyringCommand(stdin *strings.Reader, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			exitErr.Stderr = stderr.Bytes()
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s: %w: %s", name, err, msg)
+		}
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return string(out), nil
+}
+
+// isKeychainNotFound reports whether security failed as there is no such item.
+func isKeychainNotFound(err error) bool {
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr) && exitErr.ExitCode() == 44
+}
+
+func quoteKeychainArg(arg string) string {
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"mime"
@@ -22,6 +23,7 @@ import (
 	"github.com/speakeasy-api/speakeasy/internal/cache"
 	"github.com/speakeasy-api/speakeasy/internal/env"
 	"github.com/speakeasy-api/speakeasy/internal/log"
+	"github.com/speakeasy-api/speakeasy/internal/offline"
 
 	"github.com/speakeasy-api/speakeasy-core/auth"
 	"github.com/speakeasy-api/speakeasy-core/loader"
@@ -90,61 +92,22 @@ func DownloadRegistryOpenAPIBundle(ctx context.Context, document workflow.Speake
 			Duration:          bundleCacheTime,
 		})
 	}
-	bundleCache, err := fileCache.Get()
-	if err != nil {
-		serverURL := auth.GetServerURL()
-		insecurePublish := false
-		if strings.HasPrefix(serverURL, "http://") {
-			insecurePublish = true
-		}
-		reg := strings.TrimPrefix(serverURL, "http://")
-		reg = strings.TrimPrefix(reg, "https://")
 
-		apiKey := config.GetWorkspaceAPIKey(document.OrganizationSlug, document.WorkspaceSlug)
-		if apiKey == "" {
-			apiKey = config.GetSpeakeasyAPIKey()
-		}
-
-		workspaceID, err := auth.GetWorkspaceIDFromContext(ctx)
+	var bundleCache *BundleResultCache
+	var err error
+	if offline.Enabled() {
+		bundleCache, err = loadMirroredRegistryBundle(document)
 		if err != nil {
 			return nil, err
 		}
-
-		access := ocicommon.NewRepositoryAccess(apiKey, document.NamespaceName, ocicommon.RepositoryAccessOptions{
-			Insecure: insecurePublish,
-		})
-		if (document.WorkspaceSlug != auth.GetWorkspaceSlugFromContext(ctx) || document.OrganizationSlug != auth.GetOrgSlugFromContext(ctx)) && workspaceID == "self" {
-			access = ocicommon.NewRepositoryAccessAdmin(apiKey, document.NamespaceID, document.NamespaceName, false, ocicommon.RepositoryAccessOptions{
-				Insecure: insecurePublish,
-			})
-		}
-
-		bundleLoader := loader.NewLoader(loader.OCILoaderOptions{
-			Registry: reg,
-			Access:   access,
-		})
-
-		bundleResult, err := bundleLoader.LoadOpenAPIBundle(ctx, document.Reference)
+		// The mirror is the source of the bundle, so it isn't cached as well.
+		fileCache = nil
+	} else if bundleCache, err = fileCache.Get(); err != nil {
+		bundleCache, err = fetchRegistryBundle(ctx, document)
 		if err != nil {
 			return nil, err
 		}
 
-		defer bundleResult.Body.Close()
-
-		buf, err := io.ReadAll(bundleResult.Body)
-		if err != nil {
-			return nil, err
-		}
-		bodyEncoded := base64.StdEncoding.EncodeToString(buf)
-
-		bundleCache = &BundleResultCache{
-			Body:              bodyEncoded,
-			MediaType:         bundleResult.MediaType,
-			BundleAnnotations: bundleResult.BundleAnnotations,
-			BlobDigest:        bundleResult.BlobDigest,
-			ManifestDigest:    bundleResult.ManifestDigest,
-		}
-
 		err = fileCache.Store(bundleCache)
 		if err == nil {
 			log.From(ctx).Infof("Stored bundle into global cache")
@@ -194,6 +157,108 @@ func DownloadRegistryOpenAPIBundle(ctx context.Context, document workflow.Speake
 	}, nil
 }
 
+// fetchRegistryBundle downloads the bundle of a document from the registry.
+func fetchRegistryBundle(ctx context.Context, document workflow.SpeakeasyRegistryDocument) (*BundleResultCache, error) {
+	serverURL := auth.GetServerURL()
+	insecurePublish := false
+	if strings.HasPrefix(serverURL, "http://") {
+		insecurePublish = true
+	}
+	reg := strings.TrimPrefix(serverURL, "http://")
+	reg = strings.TrimPrefix(reg, "https://")
+
+	apiKey := config.GetWorkspaceAPIKey(document.OrganizationSlug, document.WorkspaceSlug)
+	if apiKey == "" {
+		apiKey = config.GetSpeakeasyAPIKey()
+	}
+
+	workspaceID, err := auth.GetWorkspaceIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	access := ocicommon.NewRepositoryAccess(apiKey, document.NamespaceName, ocicommon.RepositoryAccessOptions{
+		Insecure: insecurePublish,
+	})
+	if (document.WorkspaceSlug != auth.GetWorkspaceSlugFromContext(ctx) || document.OrganizationSlug != auth.GetOrgSlugFromContext(ctx)) && workspaceID == "self" {
+		access = ocicommon.NewRepositoryAccessAdmin(apiKey, document.NamespaceID, document.NamespaceName, false, ocicommon.RepositoryAccessOptions{
+			Insecure: insecurePublish,
+		})
+	}
+
+	bundleLoader := loader.NewLoader(loader.OCILoaderOptions{
+		Registry: reg,
+		Access:   access,
+	})
+
+	bundleResult, err := bundleLoader.LoadOpenAPIBundle(ctx, document.Reference)
+	if err != nil {
+		return nil, err
+	}
+
+	defer bundleResult.Body.Close()
+
+	buf, err := io.ReadAll(bundleResult.Body)
+	if err != nil {
+		return nil, err
+	}
+	bodyEncoded := base64.StdEncoding.EncodeToString(buf)
+
+	return &BundleResultCache{
+		Body:              bodyEncoded,
+		MediaType:         bundleResult.MediaType,
+		BundleAnnotations: bundleResult.BundleAnnotations,
+		BlobDigest:        bundleResult.BlobDigest,
+		ManifestDigest:    bundleResult.ManifestDigest,
+	}, nil
+}
+
+// MirrorRegistryBundle downloads the bundle of a document from the registry
+// into the local mirror at mirrorDir, for use in offline mode. It returns the
+// path of the mirrored bundle.
+func MirrorRegistryBundle(ctx context.Context, document workflow.SpeakeasyRegistryDocument, mirrorDir string) (string, error) {
+	bundle, err := fetchRegistryBundle(ctx, document)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		return "", err
+	}
+
+	path := offline.RegistryBundlePath(mirrorDir, document.OrganizationSlug, document.WorkspaceSlug, document.NamespaceName, document.Reference)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write mirrored bundle: %w", err)
+	}
+
+	return path, nil
+}
+
+func loadMirroredRegistryBundle(document workflow.SpeakeasyRegistryDocument) (*BundleResultCache, error) {
+	mirrorDir, err := offline.MirrorDir()
+	if err != nil {
+		return nil, err
+	}
+
+	path := offline.RegistryBundlePath(mirrorDir, document.OrganizationSlug, document.WorkspaceSlug, document.NamespaceName, document.Reference)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("registry bundle %s/%s/%s@%s isn't in the offline mirror at %s, run speakeasy mirror sync with network access to add it", document.OrganizationSlug, document.WorkspaceSlug, document.NamespaceName, document.Reference, mirrorDir)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read mirrored bundle: %w", err)
+	}
+
+	var bundle BundleResultCache
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse mirrored bundle %s: %w", path, err)
+	}
+	return &bundle, nil
+}
+
 func copyZipToOutDir(zipReader *zip.Reader, outDir string) error {
 	for _, file := range zipReader.File {
 		cleanName := filepath.Clean(file.Name)
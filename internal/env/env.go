@@ -37,6 +37,31 @@ func IsCI() bool {
 	return os.Getenv("CI") == "true" || IsGithubAction() || utils.IsRunningInCI()
 }
 
+// Returns true if the SPEAKEASY_OFFLINE environment variable is set, which
+// the --offline flag also sets. Offline, the CLI makes no network requests.
+func IsOffline() bool {
+	offline := os.Getenv("SPEAKEASY_OFFLINE")
+	return offline != "" && offline != "false" && offline != "0"
+}
+
+// Returns the SPEAKEASY_MIRROR_DIR environment variable value, the local
+// mirror used in offline mode.
+func MirrorDir() string {
+	return os.Getenv("SPEAKEASY_MIRROR_DIR")
+}
+
+// Returns the SPEAKEASY_UPDATE_MIRROR_URL environment variable value, a mirror
+// of the CLI's GitHub releases to install updates and pinned versions from.
+func UpdateMirrorURL() string {
+	return os.Getenv("SPEAKEASY_UPDATE_MIRROR_URL")
+}
+
+// Returns the SPEAKEASY_UPDATE_PUBLIC_KEY environment variable value, a minisign
+// or cosign public key that releases must be signed with to be installed.
+func UpdatePublicKey() string {
+	return os.Getenv("SPEAKEASY_UPDATE_PUBLIC_KEY")
+}
+
 // Returns the SPEAKEASY_RUN_LOCATION environment variable value. For example,
 // this is set by Speakeasy maintained GitHub Actions to "action".
 func SpeakeasyRunLocation() string {
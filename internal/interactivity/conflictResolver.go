@@ -0,0 +1,338 @@
+package interactivity
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	charm_internal "github.com/speakeasy-api/speakeasy/internal/charm"
+	"github.com/speakeasy-api/speakeasy/internal/charm/styles"
+	"github.com/speakeasy-api/speakeasy/internal/patches"
+)
+
+const (
+	conflictMarkerOurs   = "<<<<<<< ours (custom code)"
+	conflictMarkerBase   = "||||||| base (previous generation)"
+	conflictMarkerSplit  = "======="
+	conflictMarkerTheirs = ">>>>>>> theirs (new generation)"
+	conflictContextLines = 3
+)
+
+// ConflictResolver is a three-way merge UI that walks the conflicting hunks
+// of each file, showing base, ours and theirs side by side.
+type ConflictResolver struct {
+	files   []*patches.ConflictFile
+	file    int
+	hunk    int
+	width   int
+	height  int
+	message string
+	done    bool
+}
+
+type hunkEditedMsg struct {
+	hunk *patches.ConflictHunk
+	path string
+	err  error
+}
+
+// RunConflictResolver runs the resolver and returns the files whose
+// conflicts were all resolved. Every file must have at least one hunk.
+func RunConflictResolver(files []*patches.ConflictFile) ([]*patches.ConflictFile, error) {
+	m := &ConflictResolver{files: files}
+	m.firstUnresolved()
+
+	if _, err := charm_internal.RunModel(m, tea.WithAltScreen()); err != nil {
+		return nil, err
+	}
+
+	var resolved []*patches.ConflictFile
+	for _, f := range files {
+		if f.Resolved() {
+			resolved = append(resolved, f)
+		}
+	}
+	return resolved, nil
+}
+
+func (m *ConflictResolver) Init() tea.Cmd {
+	return nil
+}
+
+func (m *ConflictResolver) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(hunkEditedMsg); ok {
+		defer os.Remove(msg.path)
+		if msg.err != nil {
+			m.message = fmt.Sprintf("editor failed: %v", msg.err)
+			return m, nil
+		}
+
+		content, err := os.ReadFile(msg.path)
+		if err != nil {
+			m.message = fmt.Sprintf("failed to read edited hunk: %v", err)
+			return m, nil
+		}
+		if containsConflictMarkers(string(content)) {
+			m.message = "edited hunk still contains conflict markers, edit again or pick a side"
+			return m, nil
+		}
+
+		msg.hunk.ResolveEdited(string(content))
+		m.message = ""
+		return m, m.advance()
+	}
+
+	return m, nil
+}
+
+func (m *ConflictResolver) HandleKeypress(key string) tea.Cmd {
+	if m.done || len(m.files) == 0 {
+		return nil
+	}
+
+	hunk := m.currentHunk()
+	switch key {
+	case "o":
+		hunk.Resolve(patches.ResolutionOurs)
+		return m.advance()
+	case "t":
+		hunk.Resolve(patches.ResolutionTheirs)
+		return m.advance()
+	case "b":
+		hunk.Resolve(patches.ResolutionBoth)
+		return m.advance()
+	case "e":
+		return m.editHunk(hunk)
+	case "u":
+		hunk.Resolve(patches.ResolutionNone)
+	case "right", "n":
+		m.step(1)
+	case "left", "p":
+		m.step(-1)
+	case "tab":
+		m.file = (m.file + 1) % len(m.files)
+		m.hunk = 0
+	case "shift+tab":
+		m.file = (m.file - 1 + len(m.files)) % len(m.files)
+		m.hunk = 0
+	case "q":
+		m.done = true
+		return tea.Quit
+	}
+
+	m.message = ""
+	return nil
+}
+
+func (m *ConflictResolver) currentHunk() *patches.ConflictHunk {
+	return m.files[m.file].Hunks[m.hunk]
+}
+
+// step moves between hunks, crossing file boundaries.
+func (m *ConflictResolver) step(delta int) {
+	m.hunk += delta
+	for m.hunk < 0 || m.hunk >= len(m.files[m.file].Hunks) {
+		if m.hunk < 0 {
+			m.file = (m.file - 1 + len(m.files)) % len(m.files)
+			m.hunk += len(m.files[m.file].Hunks)
+		} else {
+			m.hunk -= len(m.files[m.file].Hunks)
+			m.file = (m.file + 1) % len(m.files)
+		}
+	}
+}
+
+// advance moves to the next unresolved hunk, finishing once none remain.
+func (m *ConflictResolver) advance() tea.Cmd {
+	if !m.firstUnresolved() {
+		m.done = true
+		return tea.Quit
+	}
+	return nil
+}
+
+func (m *ConflictResolver) firstUnresolved() bool {
+	for i := range m.files {
+		f := (m.file + i) % len(m.files)
+		for h, hunk := range m.files[f].Hunks {
+			if hunk.Resolution == patches.ResolutionNone {
+				m.file, m.hunk = f, h
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// editHunk opens the hunk in $VISUAL or $EDITOR with diff3 style markers.
+func (m *ConflictResolver) editHunk(hunk *patches.ConflictHunk) tea.Cmd {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	tmp, err := os.CreateTemp("", "speakeasy-hunk-*")
+	if err != nil {
+		m.message = fmt.Sprintf("failed to create temporary file: %v", err)
+		return nil
+	}
+	defer tmp.Close()
+
+	var b strings.Builder
+	b.WriteString(conflictMarkerOurs + "\n")
+	writeHunkLines(&b, hunk.Ours)
+	b.WriteString(conflictMarkerBase + "\n")
+	writeHunkLines(&b, hunk.Base)
+	b.WriteString(conflictMarkerSplit + "\n")
+	writeHunkLines(&b, hunk.Theirs)
+	b.WriteString(conflictMarkerTheirs + "\n")
+	if _, err := tmp.WriteString(b.String()); err != nil {
+		m.message = fmt.Sprintf("failed to write temporary file: %v", err)
+		return nil
+	}
+
+	args := strings.Fields(editor)
+	cmd := exec.Command(args[0], append(args[1:], tmp.Name())...) //nolint:gosec // the user's own editor
+	path := tmp.Name()
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return hunkEditedMsg{hunk: hunk, path: path, err: err}
+	})
+}
+
+func writeHunkLines(b *strings.Builder, lines []string) {
+	for _, line := range lines {
+		b.WriteString(line)
+		if !strings.HasSuffix(line, "\n") {
+			b.WriteString("\n")
+		}
+	}
+}
+
+func containsConflictMarkers(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		for _, marker := range []string{"<<<<<<< ", "||||||| ", ">>>>>>> "} {
+			if strings.HasPrefix(line, marker) {
+				return true
+			}
+		}
+		if line == conflictMarkerSplit {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *ConflictResolver) SetWidth(width int) {
+	m.width = width
+}
+
+func (m *ConflictResolver) SetHeight(height int) {
+	m.height = height
+}
+
+func (m *ConflictResolver) OnUserExit() {}
+
+func (m *ConflictResolver) View() string {
+	if m.done || len(m.files) == 0 {
+		return ""
+	}
+
+	f := m.files[m.file]
+	hunk := m.currentHunk()
+
+	resolvedHunks, totalHunks := 0, 0
+	for _, file := range m.files {
+		for _, h := range file.Hunks {
+			totalHunks++
+			if h.Resolution != patches.ResolutionNone {
+				resolvedHunks++
+			}
+		}
+	}
+
+	var s strings.Builder
+	s.WriteString(styles.HeavilyEmphasized.Render(fmt.Sprintf("Resolving %s", f.Path)))
+	s.WriteString(styles.Dimmed.Render(fmt.Sprintf("  file %d/%d · hunk %d/%d · %d/%d resolved", m.file+1, len(m.files), m.hunk+1, len(f.Hunks), resolvedHunks, totalHunks)))
+	s.WriteString("\n\n")
+
+	before, after := f.Context(hunk, conflictContextLines)
+
+	width := m.width
+	if width <= 0 {
+		width = 120
+	}
+	// Three bordered columns, each with a one cell border and padding on both sides.
+	columnWidth := max(20, width/3-4)
+	maxLines := 0
+	if m.height > 0 {
+		maxLines = max(5, m.height-14)
+	}
+
+	columns := []string{
+		renderHunkColumn("Base (previous generation)", before, hunk.Base, after, columnWidth, maxLines, styles.Colors.Grey),
+		renderHunkColumn("Ours (custom code)", before, hunk.Ours, after, columnWidth, maxLines, styles.Colors.Blue),
+		renderHunkColumn("Theirs (new generation)", before, hunk.Theirs, after, columnWidth, maxLines, styles.Colors.Green),
+	}
+	s.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, columns...))
+	s.WriteString("\n\n")
+
+	if hunk.Resolution == patches.ResolutionNone {
+		s.WriteString(styles.Warning.Render("Unresolved"))
+	} else {
+		s.WriteString(styles.Success.Render("Resolved: " + hunk.Resolution.String()))
+	}
+	if m.message != "" {
+		s.WriteString("  " + styles.Error.Render(m.message))
+	}
+	s.WriteString("\n\n")
+
+	s.WriteString(styles.RenderKeymapLegend(
+		[]string{"o", "t", "b", "e", "u", "←/→", "tab", "q", "esc"},
+		[]string{"ours", "theirs", "both", "edit", "undo", "hunk", "file", "save & quit", "abort"},
+	))
+
+	return styles.Margins.Render(s.String())
+}
+
+func renderHunkColumn(title string, before, lines, after []string, width, maxLines int, color lipgloss.AdaptiveColor) string {
+	var rows []string
+	for _, line := range before {
+		rows = append(rows, styles.Dimmed.Render(formatHunkLine(line, width)))
+	}
+	if len(lines) == 0 {
+		rows = append(rows, styles.DimmedItalic.Render("(empty)"))
+	}
+	for _, line := range lines {
+		rows = append(rows, lipgloss.NewStyle().Foreground(color).Render(formatHunkLine(line, width)))
+	}
+	for _, line := range after {
+		rows = append(rows, styles.Dimmed.Render(formatHunkLine(line, width)))
+	}
+
+	if maxLines > 0 && len(rows) > maxLines {
+		hidden := len(rows) - maxLines + 1
+		rows = append(rows[:maxLines-1], styles.DimmedItalic.Render(fmt.Sprintf("… %d more lines", hidden)))
+	}
+
+	header := lipgloss.NewStyle().Foreground(color).Bold(true).Render(title)
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(color).
+		Padding(0, 1).
+		Width(width).
+		Render(header + "\n" + strings.Join(rows, "\n"))
+}
+
+func formatHunkLine(line string, width int) string {
+	line = strings.ReplaceAll(strings.TrimRight(line, "\n"), "\t", "    ")
+	if runes := []rune(line); len(runes) > width {
+		return string(runes[:width-1]) + "…"
+	}
+	return line
+}
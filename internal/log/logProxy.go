@@ -32,6 +32,10 @@ type logProxyEntry struct {
 }
 
 func SendToLogProxy(ctx context.Context, logLevel logProxyLevel, logMessage string, tags map[string]interface{}) error {
+	if env.IsOffline() {
+		return nil
+	}
+
 	key := config.GetSpeakeasyAPIKey()
 	if key == "" {
 		return fmt.Errorf("SPEAKEASY_API_KEY not found")
@@ -28,6 +28,7 @@ import (
 	"github.com/speakeasy-api/speakeasy/internal/interactivity"
 	"github.com/speakeasy-api/speakeasy/internal/log"
 	"github.com/speakeasy-api/speakeasy/internal/model/flag"
+	"github.com/speakeasy-api/speakeasy/internal/offline"
 	"github.com/speakeasy-api/speakeasy/internal/updates"
 	"github.com/speakeasy-api/speakeasy/internal/utils"
 	"github.com/spf13/cobra"
@@ -100,6 +101,15 @@ type ExecutableCommand[F interface{}] struct {
 	// context.
 	RequiresAuth bool
 
+	// When set, decides whether the command requires authentication from its
+	// flags instead of RequiresAuth, for commands that only need it in some
+	// modes.
+	RequiresAuthWith func(flags *F) bool
+
+	// When enabled, the command needs network access for more than
+	// authentication, and fails with an explanation in offline mode.
+	RequiresNetwork bool
+
 	// When enabled, the command uses a workflow file. If the "pinned" CLI flag
 	// is not present or set to false and the execution environment is not
 	// local, run using the CLI version specified in the workflow file.
@@ -135,7 +145,23 @@ func (c ExecutableCommand[F]) Init() (*cobra.Command, error) {
 			}
 		}
 
-		if c.RequiresAuth {
+		if c.RequiresNetwork {
+			if err := offline.RequireNetwork(cmd.CommandPath()); err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+		}
+
+		requiresAuth := c.RequiresAuth
+		if c.RequiresAuthWith != nil {
+			requiresAuth = c.RequiresAuthWith(flags)
+		}
+
+		if offline.Enabled() {
+			// Authenticating calls the Speakeasy API. Features that need it
+			// explain they're unavailable in offline mode when they're used.
+			log.From(cmd.Context()).Debug("Skipping authentication in offline mode")
+		} else if requiresAuth {
 			authCtx, err := auth.Authenticate(cmd.Context(), false)
 			if err != nil {
 				cmd.SilenceUsage = true
@@ -196,7 +222,7 @@ func (c ExecutableCommand[F]) Init() (*cobra.Command, error) {
 			}
 		}
 
-		return events.Telemetry(cmd.Context(), shared.InteractionTypeCliExec, func(ctx context.Context, event *shared.CliEvent) error {
+		return offline.Telemetry(cmd.Context(), shared.InteractionTypeCliExec, func(ctx context.Context, event *shared.CliEvent) error {
 			return execute(ctx)
 		})
 	}
@@ -0,0 +1,80 @@
+// Package offline supports running the CLI without network access, with
+// pinned CLI versions and registry bundles resolved from a local mirror
+// populated by speakeasy mirror sync.
+package offline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/speakeasy-api/speakeasy-client-sdk-go/v3/pkg/models/shared"
+	"github.com/speakeasy-api/speakeasy-core/events"
+	"github.com/speakeasy-api/speakeasy/internal/env"
+)
+
+// ErrOffline is returned by features that need network access in offline mode.
+var ErrOffline = errors.New("not available in offline mode")
+
+// Enabled reports whether the CLI is running in offline mode, set by
+// --offline or SPEAKEASY_OFFLINE.
+func Enabled() bool {
+	return env.IsOffline()
+}
+
+// RequireNetwork returns an error explaining that feature needs network
+// access if the CLI is running in offline mode.
+func RequireNetwork(feature string) error {
+	if !Enabled() {
+		return nil
+	}
+	return fmt.Errorf("%s needs network access and is %w, unset --offline and SPEAKEASY_OFFLINE to use it", feature, ErrOffline)
+}
+
+// Telemetry runs fn as events.Telemetry does, without sending the event in
+// offline mode.
+func Telemetry(ctx context.Context, interactionType shared.InteractionType, fn func(ctx context.Context, event *shared.CliEvent) error) error {
+	if Enabled() {
+		return fn(ctx, &shared.CliEvent{})
+	}
+	return events.Telemetry(ctx, interactionType, fn)
+}
+
+// MirrorDir is the root of the local mirror: SPEAKEASY_MIRROR_DIR, or
+// ~/.speakeasy/mirror by default.
+func MirrorDir() (string, error) {
+	if dir := env.MirrorDir(); dir != "" {
+		return dir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".speakeasy", "mirror"), nil
+}
+
+// CLIReleasesDir is where CLI releases are mirrored, in the layout of an
+// update mirror: releases.json lists the releases as returned by the GitHub
+// API, and <tag>/<asset> holds their assets. The directory can also be served
+// over HTTP as SPEAKEASY_UPDATE_MIRROR_URL.
+func CLIReleasesDir(mirrorDir string) string {
+	return filepath.Join(mirrorDir, "cli")
+}
+
+// RegistryBundlePath is where the registry bundle of a document is mirrored.
+func RegistryBundlePath(mirrorDir, organizationSlug, workspaceSlug, namespaceName, reference string) string {
+	return filepath.Join(mirrorDir, "registry", organizationSlug, workspaceSlug, namespaceName, sanitizeReference(reference)+".json")
+}
+
+// sanitizeReference makes a tag or digest reference safe to use as a file
+// name on every OS.
+func sanitizeReference(reference string) string {
+	if reference == "" {
+		reference = "latest"
+	}
+	return strings.NewReplacer(":", "_", "/", "_", "\\", "_").Replace(reference)
+}
@@ -0,0 +1,49 @@
+package offline
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireNetwork(t *testing.T) {
+	for value, offline := range map[string]bool{"": false, "false": false, "0": false, "true": true, "1": true} {
+		t.Run(value, func(t *testing.T) {
+			t.Setenv("SPEAKEASY_OFFLINE", value)
+
+			err := RequireNetwork("speakeasy pull")
+			if !offline {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrOffline)
+			assert.ErrorContains(t, err, "speakeasy pull needs network access")
+		})
+	}
+}
+
+func TestMirrorDir(t *testing.T) {
+	t.Setenv("SPEAKEASY_MIRROR_DIR", "/mnt/mirror")
+	dir, err := MirrorDir()
+	require.NoError(t, err)
+	assert.Equal(t, "/mnt/mirror", dir)
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("SPEAKEASY_MIRROR_DIR", "")
+	dir, err = MirrorDir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".speakeasy", "mirror"), dir)
+}
+
+func TestRegistryBundlePath(t *testing.T) {
+	assert.Equal(t,
+		filepath.Join("mirror", "registry", "org", "ws", "api", "sha256_abc.json"),
+		RegistryBundlePath("mirror", "org", "ws", "api", "sha256:abc"))
+	assert.Equal(t,
+		filepath.Join("mirror", "registry", "org", "ws", "api", "latest.json"),
+		RegistryBundlePath("mirror", "org", "ws", "api", ""))
+}
@@ -0,0 +1,256 @@
+package patches
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	config "github.com/speakeasy-api/sdk-gen-config"
+	"github.com/speakeasy-api/speakeasy/internal/git"
+)
+
+// DoctorIssue is a problem with a gen.lock tracked file entry.
+type DoctorIssue string
+
+const (
+	// IssueMissingPristine means the entry's pristine object is not in the
+	// local object database, e.g. after a shallow clone or an aggressive gc.
+	IssueMissingPristine DoctorIssue = "missing-pristine"
+	// IssueUnreachablePristine means the pristine object exists locally but
+	// no ref keeps it alive, so the next gc may delete it.
+	IssueUnreachablePristine DoctorIssue = "unreachable-pristine"
+	// IssueStaleEntry means the tracked file no longer exists anywhere on disk.
+	IssueStaleEntry DoctorIssue = "stale-entry"
+)
+
+// DoctorFinding is one problem found by Diagnose.
+type DoctorFinding struct {
+	Path           string
+	PristineObject string
+	Issue          DoctorIssue
+	// Fixed is set by the repair functions once the problem is resolved.
+	Fixed bool
+	// Detail describes how the problem was fixed, or why it could not be.
+	Detail string
+}
+
+// Diagnose checks every tracked file entry in the lockfile for missing or
+// unreachable pristine objects, and for files that no longer exist. Entries
+// marked deleted are only reported as stale when includeDeleted is set,
+// since they record that the user removed a generated file on purpose.
+func Diagnose(outDir string, lockFile *config.LockFile, gitRepo GitRepository, includeDeleted bool) ([]DoctorFinding, error) {
+	if lockFile == nil || lockFile.TrackedFiles == nil {
+		return nil, nil
+	}
+
+	scanResult, err := NewScanner(outDir).Scan()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", outDir, err)
+	}
+
+	reachable, err := reachableObjects(outDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for path := range lockFile.TrackedFiles.Keys() {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+
+	var findings []DoctorFinding
+	for _, path := range paths {
+		tracked, ok := lockFile.TrackedFiles.Get(path)
+		if !ok {
+			continue
+		}
+
+		_, statErr := os.Stat(filepath.Join(outDir, path))
+		_, movedOnDisk := scanResult.UUIDToPath[tracked.ID]
+		if statErr != nil && (tracked.ID == "" || !movedOnDisk) && (!tracked.Deleted || includeDeleted) {
+			findings = append(findings, DoctorFinding{Path: path, PristineObject: tracked.PristineGitObject, Issue: IssueStaleEntry})
+			continue
+		}
+
+		switch {
+		case tracked.PristineGitObject == "":
+		case !gitRepo.HasObject(tracked.PristineGitObject):
+			findings = append(findings, DoctorFinding{Path: path, PristineObject: tracked.PristineGitObject, Issue: IssueMissingPristine})
+		case !reachable[tracked.PristineGitObject]:
+			findings = append(findings, DoctorFinding{Path: path, PristineObject: tracked.PristineGitObject, Issue: IssueUnreachablePristine})
+		}
+	}
+
+	return findings, nil
+}
+
+// reachableObjects returns every object reachable from a ref.
+func reachableObjects(dir string) (map[string]bool, error) {
+	out, err := git.RunGitCommand(dir, "rev-list", "--objects", "--all")
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make(map[string]bool)
+	for _, line := range strings.Split(out, "\n") {
+		if hash, _, _ := strings.Cut(line, " "); hash != "" {
+			objects[hash] = true
+		}
+	}
+	return objects, nil
+}
+
+// RecoverFromSnapshots fetches the generation snapshots published on the
+// remote (refs/speakeasy/gen/*) that are not present locally, stopping once
+// every missing pristine object has been recovered.
+func RecoverFromSnapshots(outDir string, gitRepo GitRepository, findings []DoctorFinding) error {
+	if !hasUnfixed(findings, IssueMissingPristine) {
+		return nil
+	}
+
+	out, err := git.RunGitCommand(outDir, "ls-remote", "origin", "refs/speakeasy/gen/*")
+	if err != nil {
+		return fmt.Errorf("failed to list generation snapshots on origin: %w", err)
+	}
+
+	adapter := NewGitAdapter(gitRepo, "")
+	var fetchErrs []string
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		_, ref, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		if _, err := gitRepo.GetRef(ref); err == nil {
+			continue
+		}
+
+		if err := adapter.FetchSnapshot(strings.TrimPrefix(ref, "refs/speakeasy/gen/")); err != nil {
+			fetchErrs = append(fetchErrs, err.Error())
+			continue
+		}
+
+		markRecovered(findings, gitRepo, "fetched from "+ref)
+		if !hasUnfixed(findings, IssueMissingPristine) {
+			return nil
+		}
+	}
+
+	if len(fetchErrs) > 0 {
+		return fmt.Errorf("failed to fetch %d snapshot(s): %s", len(fetchErrs), strings.Join(fetchErrs, "; "))
+	}
+	return nil
+}
+
+func markRecovered(findings []DoctorFinding, gitRepo GitRepository, detail string) {
+	for i := range findings {
+		f := &findings[i]
+		if f.Issue == IssueMissingPristine && !f.Fixed && gitRepo.HasObject(f.PristineObject) {
+			f.Fixed = true
+			f.Detail = detail
+		}
+	}
+}
+
+// RecoverFromGeneration restores missing pristine objects from a fresh
+// generation in generatedDir, made with the generator version recorded in
+// the lockfile. A file is only restored when its content hashes to exactly
+// the recorded pristine object.
+func RecoverFromGeneration(gitRepo GitRepository, findings []DoctorFinding, generatedDir string) error {
+	for i := range findings {
+		f := &findings[i]
+		if f.Issue != IssueMissingPristine || f.Fixed {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(generatedDir, f.Path))
+		if err != nil {
+			f.Detail = "not produced by regeneration"
+			continue
+		}
+		if gitBlobHash(content) != f.PristineObject {
+			f.Detail = "regenerated content does not match the recorded pristine object"
+			continue
+		}
+
+		if _, err := gitRepo.WriteBlob(content); err != nil {
+			return fmt.Errorf("failed to store pristine version of %s: %w", f.Path, err)
+		}
+		f.Fixed = true
+		f.Detail = "rebuilt by regeneration"
+	}
+	return nil
+}
+
+// PinPristineObjects commits the pristine objects of the findings that exist
+// locally but are unreachable, including those just rebuilt, to a new local
+// generation snapshot ref so gc keeps them. It returns the ref name, or an
+// empty string if there was nothing to pin.
+func PinPristineObjects(outDir string, gitRepo GitRepository, findings []DoctorFinding) (string, error) {
+	reachable, err := reachableObjects(outDir)
+	if err != nil {
+		return "", err
+	}
+
+	hashes := make(map[string]string)
+	for _, f := range findings {
+		if f.Issue == IssueStaleEntry || f.PristineObject == "" || reachable[f.PristineObject] {
+			continue
+		}
+		if gitRepo.HasObject(f.PristineObject) {
+			hashes[f.Path] = f.PristineObject
+		}
+	}
+	if len(hashes) == 0 {
+		return "", nil
+	}
+
+	adapter := NewGitAdapter(gitRepo, "")
+	tree, err := adapter.CreateSnapshotTree(hashes)
+	if err != nil {
+		return "", fmt.Errorf("failed to create snapshot tree: %w", err)
+	}
+	commit, err := adapter.CommitSnapshot(tree, "", "speakeasy patches doctor: pin pristine objects")
+	if err != nil {
+		return "", fmt.Errorf("failed to commit snapshot: %w", err)
+	}
+
+	ref := "refs/speakeasy/gen/" + uuid.NewString()
+	if err := gitRepo.UpdateRef(ref, commit, ""); err != nil {
+		return "", fmt.Errorf("failed to create ref %s: %w", ref, err)
+	}
+
+	for i := range findings {
+		if findings[i].Issue == IssueUnreachablePristine {
+			findings[i].Fixed = true
+			findings[i].Detail = "pinned by " + ref
+		}
+	}
+	return ref, nil
+}
+
+// PruneStaleEntries removes the lockfile entries of tracked files that no
+// longer exist. The caller saves the lockfile.
+func PruneStaleEntries(lockFile *config.LockFile, findings []DoctorFinding) {
+	for i := range findings {
+		f := &findings[i]
+		if f.Issue != IssueStaleEntry || f.Fixed {
+			continue
+		}
+		lockFile.TrackedFiles.Delete(f.Path)
+		f.Fixed = true
+		f.Detail = "removed from gen.lock"
+	}
+}
+
+func hasUnfixed(findings []DoctorFinding, issue DoctorIssue) bool {
+	for _, f := range findings {
+		if f.Issue == issue && !f.Fixed {
+			return true
+		}
+	}
+	return false
+}
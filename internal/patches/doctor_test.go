@@ -0,0 +1,97 @@
+package patches
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/speakeasy-api/sdk-gen-config/lockfile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func initDoctorTestRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for path, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, path), []byte(content), 0o644))
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "initial"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	return dir
+}
+
+func TestDiagnose(t *testing.T) {
+	t.Parallel()
+
+	dir := initDoctorTestRepo(t, map[string]string{
+		"ok.go":      "package ok\n",
+		"missing.go": "package missing\n// custom\n",
+		"loose.go":   "package loose\n",
+	})
+
+	okHash := gitBlobHash([]byte("package ok\n"))
+	missingHash := gitBlobHash([]byte("package missing\n"))
+	looseHash := gitBlobHash([]byte("package loose\n// generated\n"))
+
+	lf := lockfile.New()
+	lf.TrackedFiles.Set("ok.go", lockfile.TrackedFile{PristineGitObject: okHash})
+	lf.TrackedFiles.Set("missing.go", lockfile.TrackedFile{PristineGitObject: missingHash})
+	lf.TrackedFiles.Set("loose.go", lockfile.TrackedFile{PristineGitObject: looseHash})
+	lf.TrackedFiles.Set("gone.go", lockfile.TrackedFile{PristineGitObject: okHash})
+	lf.TrackedFiles.Set("removed.go", lockfile.TrackedFile{PristineGitObject: okHash, Deleted: true})
+
+	repo := &mockGitRepo{blobs: map[string][]byte{
+		okHash:    []byte("package ok\n"),
+		looseHash: []byte("package loose\n// generated\n"),
+	}}
+
+	findings, err := Diagnose(dir, lf, repo, false)
+	require.NoError(t, err)
+	assert.Equal(t, []DoctorFinding{
+		{Path: "gone.go", PristineObject: okHash, Issue: IssueStaleEntry},
+		{Path: "loose.go", PristineObject: looseHash, Issue: IssueUnreachablePristine},
+		{Path: "missing.go", PristineObject: missingHash, Issue: IssueMissingPristine},
+	}, findings)
+
+	withDeleted, err := Diagnose(dir, lf, repo, true)
+	require.NoError(t, err)
+	assert.Len(t, withDeleted, 4)
+
+	PruneStaleEntries(lf, findings)
+	_, ok := lf.TrackedFiles.Get("gone.go")
+	assert.False(t, ok)
+	_, ok = lf.TrackedFiles.Get("removed.go")
+	assert.True(t, ok)
+	assert.True(t, findings[0].Fixed)
+}
+
+func TestRecoverFromGeneration(t *testing.T) {
+	t.Parallel()
+
+	generated := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(generated, "a.go"), []byte("package a\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(generated, "b.go"), []byte("package b // changed\n"), 0o644))
+
+	findings := []DoctorFinding{
+		{Path: "a.go", PristineObject: gitBlobHash([]byte("package a\n")), Issue: IssueMissingPristine},
+		{Path: "b.go", PristineObject: gitBlobHash([]byte("package b\n")), Issue: IssueMissingPristine},
+		{Path: "c.go", PristineObject: gitBlobHash([]byte("package c\n")), Issue: IssueMissingPristine},
+	}
+
+	require.NoError(t, RecoverFromGeneration(&mockGitRepo{}, findings, generated))
+	assert.True(t, findings[0].Fixed)
+	assert.False(t, findings[1].Fixed)
+	assert.Equal(t, "regenerated content does not match the recorded pristine object", findings[1].Detail)
+	assert.False(t, findings[2].Fixed)
+	assert.Equal(t, "not produced by regeneration", findings[2].Detail)
+}
@@ -0,0 +1,224 @@
+package patches
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Resolution is the choice made for a conflicting hunk.
+type Resolution int
+
+const (
+	ResolutionNone Resolution = iota
+	// ResolutionOurs keeps the custom code.
+	ResolutionOurs
+	// ResolutionTheirs takes the newly generated code.
+	ResolutionTheirs
+	// ResolutionBoth keeps the custom code followed by the generated code.
+	ResolutionBoth
+	// ResolutionEdited uses manually edited lines.
+	ResolutionEdited
+)
+
+func (r Resolution) String() string {
+	switch r {
+	case ResolutionOurs:
+		return "ours"
+	case ResolutionTheirs:
+		return "theirs"
+	case ResolutionBoth:
+		return "both"
+	case ResolutionEdited:
+		return "edited"
+	default:
+		return "unresolved"
+	}
+}
+
+// ConflictHunk is a region changed differently by the custom code (ours) and
+// the new generation (theirs) relative to the previous generation (base).
+// Lines include their trailing newline.
+type ConflictHunk struct {
+	Base   []string
+	Ours   []string
+	Theirs []string
+
+	Resolution Resolution
+	// Edited holds the lines used for ResolutionEdited.
+	Edited []string
+}
+
+// Lines returns the lines the hunk resolves to.
+func (h *ConflictHunk) Lines() []string {
+	switch h.Resolution {
+	case ResolutionOurs:
+		return h.Ours
+	case ResolutionTheirs:
+		return h.Theirs
+	case ResolutionBoth:
+		return slices.Concat(h.Ours, h.Theirs)
+	case ResolutionEdited:
+		return h.Edited
+	default:
+		return nil
+	}
+}
+
+// Resolve sets the hunk's resolution.
+func (h *ConflictHunk) Resolve(resolution Resolution) {
+	h.Resolution = resolution
+	if resolution != ResolutionEdited {
+		h.Edited = nil
+	}
+}
+
+// ResolveEdited resolves the hunk to the given content.
+func (h *ConflictHunk) ResolveEdited(content string) {
+	h.Resolution = ResolutionEdited
+	h.Edited = splitPatchLines(content)
+}
+
+// mergeSegment is either lines that merged cleanly or a conflicting hunk.
+type mergeSegment struct {
+	lines []string
+	hunk  *ConflictHunk
+}
+
+// ConflictFile is a file with conflicts between custom code and a new
+// generation, split into cleanly merged regions and conflicting hunks.
+type ConflictFile struct {
+	Path   string
+	Base   []byte
+	Ours   []byte
+	Theirs []byte
+	Hunks  []*ConflictHunk
+
+	segments []mergeSegment
+}
+
+// NewConflictFile performs a line based three-way merge of the file. Regions
+// changed on one side only, or identically on both, merge cleanly; the rest
+// become hunks to resolve. A nil base is treated as an empty file.
+func NewConflictFile(path string, base, ours, theirs []byte) *ConflictFile {
+	f := &ConflictFile{Path: path, Base: base, Ours: ours, Theirs: theirs}
+
+	baseLines := splitPatchLines(normalizeLineEndings(string(base)))
+	oursLines := splitPatchLines(normalizeLineEndings(string(ours)))
+	theirsLines := splitPatchLines(normalizeLineEndings(string(theirs)))
+
+	oursMatches := matchedLines(baseLines, oursLines)
+	theirsMatches := matchedLines(baseLines, theirsLines)
+
+	// Lines of base unchanged on both sides are sync points; everything
+	// between consecutive sync points is merged as one chunk.
+	nextBase, nextOurs, nextTheirs := 0, 0, 0
+	for i := 0; i <= len(baseLines); i++ {
+		o, t := len(oursLines), len(theirsLines)
+		if i < len(baseLines) {
+			var okOurs, okTheirs bool
+			o, okOurs = oursMatches[i]
+			t, okTheirs = theirsMatches[i]
+			if !okOurs || !okTheirs || o < nextOurs || t < nextTheirs {
+				continue
+			}
+		}
+
+		f.addChunk(baseLines[nextBase:i], oursLines[nextOurs:o], theirsLines[nextTheirs:t])
+
+		if i < len(baseLines) {
+			f.addLines(baseLines[i : i+1])
+			nextBase, nextOurs, nextTheirs = i+1, o+1, t+1
+		}
+	}
+
+	return f
+}
+
+func (f *ConflictFile) addChunk(base, ours, theirs []string) {
+	switch {
+	case slices.Equal(base, ours):
+		f.addLines(theirs)
+	case slices.Equal(base, theirs), slices.Equal(ours, theirs):
+		f.addLines(ours)
+	default:
+		hunk := &ConflictHunk{Base: base, Ours: ours, Theirs: theirs}
+		f.Hunks = append(f.Hunks, hunk)
+		f.segments = append(f.segments, mergeSegment{hunk: hunk})
+	}
+}
+
+func (f *ConflictFile) addLines(lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	if n := len(f.segments); n > 0 && f.segments[n-1].hunk == nil {
+		f.segments[n-1].lines = append(f.segments[n-1].lines, lines...)
+		return
+	}
+	f.segments = append(f.segments, mergeSegment{lines: slices.Clone(lines)})
+}
+
+// matchedLines maps indexes of a to the indexes of the identical lines in b.
+func matchedLines(a, b []string) map[int]int {
+	matches := make(map[int]int)
+	for _, block := range difflib.NewMatcher(a, b).GetMatchingBlocks() {
+		for k := 0; k < block.Size; k++ {
+			matches[block.A+k] = block.B + k
+		}
+	}
+	return matches
+}
+
+// Context returns up to n cleanly merged lines before and after a hunk.
+func (f *ConflictFile) Context(hunk *ConflictHunk, n int) (before, after []string) {
+	for i, segment := range f.segments {
+		if segment.hunk != hunk {
+			continue
+		}
+		if i > 0 && f.segments[i-1].hunk == nil {
+			lines := f.segments[i-1].lines
+			before = lines[max(0, len(lines)-n):]
+		}
+		if i+1 < len(f.segments) && f.segments[i+1].hunk == nil {
+			lines := f.segments[i+1].lines
+			after = lines[:min(n, len(lines))]
+		}
+		break
+	}
+	return before, after
+}
+
+// Resolved reports whether every hunk has been resolved.
+func (f *ConflictFile) Resolved() bool {
+	for _, hunk := range f.Hunks {
+		if hunk.Resolution == ResolutionNone {
+			return false
+		}
+	}
+	return true
+}
+
+// Content returns the merged file. It is an error if any hunk is unresolved.
+func (f *ConflictFile) Content() ([]byte, error) {
+	var b strings.Builder
+	for i, segment := range f.segments {
+		lines := segment.lines
+		if segment.hunk != nil {
+			if segment.hunk.Resolution == ResolutionNone {
+				return nil, fmt.Errorf("%s has unresolved conflicts", f.Path)
+			}
+			lines = segment.hunk.Lines()
+		}
+		for j, line := range lines {
+			b.WriteString(line)
+			// Only the file's final line may lack a newline.
+			if !strings.HasSuffix(line, "\n") && (i < len(f.segments)-1 || j < len(lines)-1) {
+				b.WriteString("\n")
+			}
+		}
+	}
+	return []byte(b.String()), nil
+}
@@ -0,0 +1,87 @@
+package patches
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConflictFile_CleanMerge(t *testing.T) {
+	t.Parallel()
+
+	base := "a\nb\nc\nd\ne\n"
+	ours := "a\nB custom\nc\nd\ne\n"
+	theirs := "a\nb\nc\nd\nE generated\n"
+
+	f := NewConflictFile("f.go", []byte(base), []byte(ours), []byte(theirs))
+	assert.Empty(t, f.Hunks)
+	assert.True(t, f.Resolved())
+
+	content, err := f.Content()
+	require.NoError(t, err)
+	assert.Equal(t, "a\nB custom\nc\nd\nE generated\n", string(content))
+}
+
+func TestNewConflictFile_Conflict(t *testing.T) {
+	t.Parallel()
+
+	base := "header\nfunc A() {\n\treturn 1\n}\nfooter\n"
+	ours := "header\nfunc A() {\n\treturn custom()\n}\nfooter\n"
+	theirs := "header\nfunc A() {\n\treturn 2\n}\nfooter\n"
+
+	f := NewConflictFile("f.go", []byte(base), []byte(ours), []byte(theirs))
+	require.Len(t, f.Hunks, 1)
+
+	hunk := f.Hunks[0]
+	assert.Equal(t, []string{"\treturn 1\n"}, hunk.Base)
+	assert.Equal(t, []string{"\treturn custom()\n"}, hunk.Ours)
+	assert.Equal(t, []string{"\treturn 2\n"}, hunk.Theirs)
+
+	before, after := f.Context(hunk, 1)
+	assert.Equal(t, []string{"func A() {\n"}, before)
+	assert.Equal(t, []string{"}\n"}, after)
+
+	_, err := f.Content()
+	assert.Error(t, err)
+
+	tests := []struct {
+		resolve  func()
+		expected string
+	}{
+		{func() { hunk.Resolve(ResolutionOurs) }, ours},
+		{func() { hunk.Resolve(ResolutionTheirs) }, theirs},
+		{func() { hunk.Resolve(ResolutionBoth) }, "header\nfunc A() {\n\treturn custom()\n\treturn 2\n}\nfooter\n"},
+		{func() { hunk.ResolveEdited("\treturn custom(2)\n") }, "header\nfunc A() {\n\treturn custom(2)\n}\nfooter\n"},
+	}
+	for _, tc := range tests {
+		tc.resolve()
+		content, err := f.Content()
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, string(content))
+	}
+}
+
+func TestNewConflictFile_NoBase(t *testing.T) {
+	t.Parallel()
+
+	f := NewConflictFile("f.go", nil, []byte("custom\n"), []byte("generated\n"))
+	require.Len(t, f.Hunks, 1)
+
+	f.Hunks[0].Resolve(ResolutionTheirs)
+	content, err := f.Content()
+	require.NoError(t, err)
+	assert.Equal(t, "generated\n", string(content))
+}
+
+func TestConflictFile_MissingTrailingNewline(t *testing.T) {
+	t.Parallel()
+
+	f := NewConflictFile("f.txt", []byte("a\nb"), []byte("a\nours"), []byte("a\ntheirs"))
+	require.Len(t, f.Hunks, 1)
+
+	f.Hunks[0].ResolveEdited("merged")
+	content, err := f.Content()
+	require.NoError(t, err)
+	assert.Equal(t, "a\nmerged", string(content))
+}
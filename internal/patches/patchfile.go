@@ -0,0 +1,238 @@
+package patches
+
+import (
+	"bufio"
+	"crypto/sha1" //nolint:gosec // git object ids are SHA-1
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+const (
+	patchAuthor = "Speakeasy <noreply@speakeasy.com>"
+
+	trailerPath           = "Speakeasy-Path"
+	trailerGeneratedID    = "Speakeasy-Generated-Id"
+	trailerPristineObject = "Speakeasy-Pristine-Object"
+)
+
+// PatchMetadata describes the custom code in one file, as recorded in an
+// exported patch. It is stored both as trailers in the patch's commit message
+// and in the series manifest.
+type PatchMetadata struct {
+	// File is the name of the patch file within the series directory.
+	File string `json:"file"`
+	// Path is the file's path relative to the generation root.
+	Path string `json:"path"`
+	// GeneratedID is the file's @generated-id, used to locate the file if
+	// the generator has moved it.
+	GeneratedID    string `json:"generatedId,omitempty"`
+	PristineObject string `json:"pristineObject"`
+	Added          int    `json:"added"`
+	Removed        int    `json:"removed"`
+}
+
+// FormatPatch renders the change from pristine to current as a single
+// `git format-patch` style email, which can be applied with `git am` or
+// `git apply`.
+func FormatPatch(meta PatchMetadata, index, total int, pristine, current []byte, date time.Time) string {
+	diff, stats := unifiedDiff(meta.Path, string(pristine), string(current))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From %s Mon Sep 17 00:00:00 2001\n", strings.Repeat("0", 40))
+	fmt.Fprintf(&b, "From: %s\n", patchAuthor)
+	fmt.Fprintf(&b, "Date: %s\n", date.Format(time.RFC1123Z))
+	fmt.Fprintf(&b, "Subject: [PATCH %d/%d] Custom code in %s\n\n", index, total, meta.Path)
+
+	fmt.Fprintf(&b, "%s: %s\n", trailerPath, meta.Path)
+	if meta.GeneratedID != "" {
+		fmt.Fprintf(&b, "%s: %s\n", trailerGeneratedID, meta.GeneratedID)
+	}
+	fmt.Fprintf(&b, "%s: %s\n", trailerPristineObject, meta.PristineObject)
+
+	b.WriteString("---\n")
+	b.WriteString(formatDiffStat(meta.Path, stats))
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "diff --git a/%s b/%s\n", meta.Path, meta.Path)
+	fmt.Fprintf(&b, "index %s..%s 100644\n", meta.PristineObject, gitBlobHash(current))
+	b.WriteString(diff)
+	b.WriteString("-- \nspeakeasy\n")
+
+	return b.String()
+}
+
+// ParsePatch reads the metadata trailers from an exported patch.
+func ParsePatch(content string) (PatchMetadata, error) {
+	var meta PatchMetadata
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "---" {
+			break
+		}
+
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			continue
+		}
+		switch key {
+		case trailerPath:
+			meta.Path = value
+		case trailerGeneratedID:
+			meta.GeneratedID = value
+		case trailerPristineObject:
+			meta.PristineObject = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return meta, err
+	}
+
+	if meta.Path == "" {
+		return meta, fmt.Errorf("patch has no %s trailer", trailerPath)
+	}
+
+	stats := countDiffStats(patchDiff(content))
+	meta.Added, meta.Removed = stats.Added, stats.Removed
+
+	return meta, nil
+}
+
+// patchDiff returns the diff of a patch, without its message and signature.
+func patchDiff(content string) string {
+	start := strings.Index(content, "\ndiff --git ")
+	if start < 0 {
+		return ""
+	}
+	diff := content[start+1:]
+	if end := strings.LastIndex(diff, "\n-- \n"); end >= 0 {
+		diff = diff[:end+1]
+	}
+	return diff
+}
+
+// RetargetPatch rewrites the file paths in a patch's diff from one path to
+// another, e.g. when the generator has moved the customized file.
+func RetargetPatch(content, from, to string) string {
+	if from == to {
+		return content
+	}
+
+	replacer := strings.NewReplacer(
+		fmt.Sprintf("diff --git a/%s b/%s\n", from, from), fmt.Sprintf("diff --git a/%s b/%s\n", to, to),
+		fmt.Sprintf("--- a/%s\n", from), fmt.Sprintf("--- a/%s\n", to),
+		fmt.Sprintf("+++ b/%s\n", from), fmt.Sprintf("+++ b/%s\n", to),
+	)
+	return replacer.Replace(content)
+}
+
+// unifiedDiff renders a git compatible unified diff body (from the ---/+++
+// headers onwards), marking missing trailing newlines as git does.
+func unifiedDiff(path, a, b string) (string, DiffStats) {
+	aLines := splitPatchLines(normalizeLineEndings(a))
+	bLines := splitPatchLines(normalizeLineEndings(b))
+
+	var out strings.Builder
+	var stats DiffStats
+	fmt.Fprintf(&out, "--- a/%s\n+++ b/%s\n", path, path)
+
+	matcher := difflib.NewMatcher(aLines, bLines)
+	for _, group := range matcher.GetGroupedOpCodes(3) {
+		first, last := group[0], group[len(group)-1]
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(first.I1, last.I2), hunkRange(first.J1, last.J2))
+
+		for _, op := range group {
+			if op.Tag == 'e' {
+				for _, line := range aLines[op.I1:op.I2] {
+					writePatchLine(&out, " ", line)
+				}
+				continue
+			}
+			if op.Tag == 'r' || op.Tag == 'd' {
+				for _, line := range aLines[op.I1:op.I2] {
+					writePatchLine(&out, "-", line)
+					stats.Removed++
+				}
+			}
+			if op.Tag == 'r' || op.Tag == 'i' {
+				for _, line := range bLines[op.J1:op.J2] {
+					writePatchLine(&out, "+", line)
+					stats.Added++
+				}
+			}
+		}
+	}
+
+	return out.String(), stats
+}
+
+func splitPatchLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func writePatchLine(b *strings.Builder, prefix, line string) {
+	b.WriteString(prefix)
+	b.WriteString(line)
+	if !strings.HasSuffix(line, "\n") {
+		b.WriteString("\n\\ No newline at end of file\n")
+	}
+}
+
+// hunkRange formats a half-open line range in unified diff notation.
+func hunkRange(start, end int) string {
+	length := end - start
+	switch length {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, length)
+	}
+}
+
+func formatDiffStat(path string, stats DiffStats) string {
+	const maxWidth = 60
+
+	added, removed := stats.Added, stats.Removed
+	if total := added + removed; total > maxWidth {
+		added = added * maxWidth / total
+		removed = removed * maxWidth / total
+	}
+
+	summary := " 1 file changed"
+	if stats.Added > 0 {
+		summary += fmt.Sprintf(", %d %s(+)", stats.Added, pluralize(stats.Added, "insertion"))
+	}
+	if stats.Removed > 0 {
+		summary += fmt.Sprintf(", %d %s(-)", stats.Removed, pluralize(stats.Removed, "deletion"))
+	}
+
+	return fmt.Sprintf(" %s | %d %s%s\n%s\n", path, stats.Added+stats.Removed, strings.Repeat("+", added), strings.Repeat("-", removed), summary)
+}
+
+func pluralize(count int, word string) string {
+	if count == 1 {
+		return word
+	}
+	return word + "s"
+}
+
+// gitBlobHash returns the git object id of content stored as a blob.
+func gitBlobHash(content []byte) string {
+	h := sha1.New() //nolint:gosec // git object ids are SHA-1
+	fmt.Fprintf(h, "blob %d\x00", len(content))
+	h.Write(content)
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
@@ -0,0 +1,86 @@
+package patches
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/speakeasy-api/speakeasy/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatPatch_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	pristine := "package sdk\n\nfunc A() {}\n\nfunc B() {}\n"
+	current := "package sdk\n\nfunc A() {}\n\n// B is customized.\nfunc B() { println(\"b\") }\n"
+
+	meta := PatchMetadata{Path: "pkg/sdk.go", GeneratedID: "a1b2c3d4e5f6", PristineObject: gitBlobHash([]byte(pristine))}
+	patch := FormatPatch(meta, 1, 2, []byte(pristine), []byte(current), time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	assert.Contains(t, patch, "Subject: [PATCH 1/2] Custom code in pkg/sdk.go\n")
+	assert.Contains(t, patch, "diff --git a/pkg/sdk.go b/pkg/sdk.go\n")
+	assert.Contains(t, patch, " pkg/sdk.go | 3 ++-\n 1 file changed, 2 insertions(+), 1 deletion(-)\n")
+
+	parsed, err := ParsePatch(patch)
+	require.NoError(t, err)
+	assert.Equal(t, PatchMetadata{Path: "pkg/sdk.go", GeneratedID: "a1b2c3d4e5f6", PristineObject: meta.PristineObject, Added: 2, Removed: 1}, parsed)
+
+	// The patch applies with git.
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "pkg"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pkg", "sdk.go"), []byte(pristine), 0o644))
+	_, err = git.RunGitCommandWithStdin(dir, strings.NewReader(patch), "apply")
+	require.NoError(t, err)
+
+	applied, err := os.ReadFile(filepath.Join(dir, "pkg", "sdk.go"))
+	require.NoError(t, err)
+	assert.Equal(t, current, string(applied))
+}
+
+func TestFormatPatch_NoTrailingNewline(t *testing.T) {
+	t.Parallel()
+
+	pristine := "a\nb"
+	current := "a\nc\n"
+
+	patch := FormatPatch(PatchMetadata{Path: "f.txt", PristineObject: gitBlobHash([]byte(pristine))}, 1, 1, []byte(pristine), []byte(current), time.Now())
+	assert.Contains(t, patch, "-b\n\\ No newline at end of file\n+c\n")
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "f.txt"), []byte(pristine), 0o644))
+	_, err := git.RunGitCommandWithStdin(dir, strings.NewReader(patch), "apply")
+	require.NoError(t, err)
+
+	applied, err := os.ReadFile(filepath.Join(dir, "f.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, current, string(applied))
+}
+
+func TestRetargetPatch(t *testing.T) {
+	t.Parallel()
+
+	patch := FormatPatch(PatchMetadata{Path: "old/sdk.go"}, 1, 1, []byte("a\n"), []byte("b\n"), time.Now())
+	retargeted := RetargetPatch(patch, "old/sdk.go", "new/sdk.go")
+
+	assert.Contains(t, retargeted, "diff --git a/new/sdk.go b/new/sdk.go\n")
+	assert.Contains(t, retargeted, "--- a/new/sdk.go\n+++ b/new/sdk.go\n")
+	assert.NotContains(t, retargeted, "--- a/old/sdk.go")
+}
+
+func TestParsePatch_MissingMetadata(t *testing.T) {
+	t.Parallel()
+
+	_, err := ParsePatch("Subject: [PATCH] something\n---\ndiff --git a/x b/x\n")
+	assert.Error(t, err)
+}
+
+func TestGitBlobHash(t *testing.T) {
+	t.Parallel()
+
+	// Matches `git hash-object` of "hello\n".
+	assert.Equal(t, "ce013625030ba8dba906f756967f9e9ca394464a", gitBlobHash([]byte("hello\n")))
+}
@@ -221,7 +221,9 @@ func DetectFileChanges(outDir string, lockFile *config.LockFile) (bool, []string
 	return isDirty, modifiedPaths, nil
 }
 
-// PrepareForGeneration detects custom code changes and optionally prompts the user.
+// PrepareForGeneration detects custom code changes and optionally prompts the user,
+// then stashes any protected regions to be restored with RestoreProtectedRegions
+// once generation finishes.
 // This should be called before SDK generation when git is available.
 //
 // Parameters:
@@ -291,5 +293,20 @@ func PrepareForGeneration(outDir string, autoYes bool, promptFunc PromptFunc, wa
 		}
 	}
 
+	// Lift protected regions out last, so the checks above see the files as
+	// the user left them.
+	if cfg.LockFile.TrackedFiles == nil {
+		return nil
+	}
+	var paths []string
+	for path := range cfg.LockFile.TrackedFiles.Keys() {
+		if tracked, ok := cfg.LockFile.TrackedFiles.Get(path); ok && !tracked.Deleted {
+			paths = append(paths, path)
+		}
+	}
+	if _, err := StashProtectedRegions(outDir, paths, warnFunc); err != nil {
+		return err
+	}
+
 	return nil
 }
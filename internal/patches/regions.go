@@ -0,0 +1,296 @@
+package patches
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ProtectedRegionsStashFile holds the protected regions extracted before a
+// generation until they are re-injected afterwards. It lives in the
+// .speakeasy directory and only exists while a generation is in progress,
+// or after one failed to restore every region.
+const ProtectedRegionsStashFile = "protected-regions.json"
+
+var (
+	regionBeginMarker = regexp.MustCompile(`speakeasy:custom-begin\s+([A-Za-z0-9_.:/-]+)`)
+	regionEndMarker   = regexp.MustCompile(`speakeasy:custom-end\b`)
+)
+
+// ProtectedRegion is a block of custom code delimited by marker comments,
+// e.g.
+//
+//	// speakeasy:custom-begin retry-hook
+//	...
+//	// speakeasy:custom-end
+//
+// Protected regions are lifted out of a file before generation and put back
+// by id afterwards, so their content is preserved exactly rather than going
+// through the three-way merge.
+type ProtectedRegion struct {
+	Path string `json:"path"`
+	ID   string `json:"id"`
+	// BeginLine and EndLine are the marker lines, kept verbatim so the
+	// region can be recreated if the generated file lost its markers.
+	BeginLine string `json:"beginLine"`
+	EndLine   string `json:"endLine"`
+	// Anchor is the closest non-blank line above the region, used to place
+	// it when the markers are missing from the generated file.
+	Anchor  string `json:"anchor,omitempty"`
+	Content string `json:"content"`
+}
+
+type protectedRegionsStash struct {
+	Regions []ProtectedRegion `json:"regions"`
+}
+
+// extractProtectedRegions returns the protected regions of a file and its
+// content with the body of each region removed, leaving the marker lines.
+func extractProtectedRegions(path, content string) ([]ProtectedRegion, string, error) {
+	var regions []ProtectedRegion
+	var stripped strings.Builder
+	var current *ProtectedRegion
+	var body strings.Builder
+	anchor := ""
+	seen := make(map[string]bool)
+
+	for i, line := range splitPatchLines(content) {
+		if m := regionBeginMarker.FindStringSubmatch(line); m != nil {
+			if current != nil {
+				return nil, "", fmt.Errorf("%s:%d: protected region %q starts inside region %q", path, i+1, m[1], current.ID)
+			}
+			if seen[m[1]] {
+				return nil, "", fmt.Errorf("%s:%d: duplicate protected region %q", path, i+1, m[1])
+			}
+			seen[m[1]] = true
+			current = &ProtectedRegion{Path: path, ID: m[1], BeginLine: line, Anchor: anchor}
+			body.Reset()
+			stripped.WriteString(line)
+			continue
+		}
+
+		if regionEndMarker.MatchString(line) {
+			if current == nil {
+				return nil, "", fmt.Errorf("%s:%d: protected region end without a matching begin", path, i+1)
+			}
+			current.EndLine = line
+			current.Content = body.String()
+			regions = append(regions, *current)
+			current = nil
+			stripped.WriteString(line)
+			continue
+		}
+
+		if current != nil {
+			body.WriteString(line)
+			continue
+		}
+		stripped.WriteString(line)
+		if strings.TrimSpace(line) != "" {
+			anchor = strings.TrimSpace(line)
+		}
+	}
+
+	if current != nil {
+		return nil, "", fmt.Errorf("%s: protected region %q is never closed", path, current.ID)
+	}
+
+	return regions, stripped.String(), nil
+}
+
+// injectProtectedRegions puts each region's content back between its markers.
+// Regions whose markers are missing are recreated below their anchor line;
+// those that cannot be placed are returned.
+func injectProtectedRegions(content string, regions []ProtectedRegion) (string, []ProtectedRegion) {
+	lines := splitPatchLines(content)
+	var missing []ProtectedRegion
+
+	for _, region := range regions {
+		body := splitPatchLines(region.Content)
+
+		begin, end := -1, -1
+		for i, line := range lines {
+			if begin < 0 {
+				if m := regionBeginMarker.FindStringSubmatch(line); m != nil && m[1] == region.ID {
+					begin = i
+				}
+			} else if regionEndMarker.MatchString(line) {
+				end = i
+				break
+			}
+		}
+
+		if begin >= 0 && end >= 0 {
+			lines = append(lines[:begin+1], append(body, lines[end:]...)...)
+			continue
+		}
+
+		at := -1
+		if region.Anchor == "" {
+			at = 0
+		} else {
+			for i, line := range lines {
+				if strings.TrimSpace(line) == region.Anchor {
+					at = i + 1
+					break
+				}
+			}
+		}
+		if at < 0 {
+			missing = append(missing, region)
+			continue
+		}
+
+		if at > 0 && !strings.HasSuffix(lines[at-1], "\n") {
+			lines[at-1] += "\n"
+		}
+		block := append([]string{region.BeginLine}, body...)
+		block = append(block, region.EndLine)
+		lines = append(lines[:at], append(block, lines[at:]...)...)
+	}
+
+	return strings.Join(lines, ""), missing
+}
+
+func protectedRegionsStashPath(outDir string) string {
+	return filepath.Join(outDir, ".speakeasy", ProtectedRegionsStashFile)
+}
+
+// StashProtectedRegions lifts the body of every protected region out of the
+// given files (relative to outDir), recording them in the stash file before
+// any file is rewritten. Files with malformed markers are left untouched and
+// reported through warnFunc. Any regions left in the stash by an earlier,
+// interrupted generation are restored first.
+func StashProtectedRegions(outDir string, paths []string, warnFunc func(format string, args ...any)) (int, error) {
+	if _, err := os.Stat(protectedRegionsStashPath(outDir)); err == nil {
+		unrestored, err := RestoreProtectedRegions(outDir)
+		if err != nil {
+			return 0, fmt.Errorf("failed to restore protected regions from a previous generation: %w", err)
+		}
+		if len(unrestored) > 0 {
+			return 0, fmt.Errorf("%d protected region(s) from a previous generation could not be restored, see %s", len(unrestored), protectedRegionsStashPath(outDir))
+		}
+	}
+
+	var stash protectedRegionsStash
+	strippedFiles := make(map[string]string)
+
+	for _, path := range paths {
+		content, err := os.ReadFile(filepath.Join(outDir, path))
+		if err != nil || isBinary(content) || !regionBeginMarker.Match(content) {
+			continue
+		}
+
+		regions, stripped, err := extractProtectedRegions(path, string(content))
+		if err != nil {
+			warnFunc("Skipping protected regions: %v", err)
+			continue
+		}
+		if len(regions) == 0 {
+			continue
+		}
+
+		stash.Regions = append(stash.Regions, regions...)
+		strippedFiles[path] = stripped
+	}
+
+	if len(stash.Regions) == 0 {
+		return 0, nil
+	}
+
+	if err := writeProtectedRegionsStash(outDir, &stash); err != nil {
+		return 0, err
+	}
+
+	for path, stripped := range strippedFiles {
+		if err := writeFilePreservingMode(filepath.Join(outDir, path), []byte(stripped)); err != nil {
+			return 0, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+
+	return len(stash.Regions), nil
+}
+
+// RestoreProtectedRegions re-injects the stashed protected regions into the
+// generated files by id and removes the stash. Regions that could not be
+// placed are returned and kept in the stash so they are not lost.
+func RestoreProtectedRegions(outDir string) ([]ProtectedRegion, error) {
+	data, err := os.ReadFile(protectedRegionsStashPath(outDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read protected regions: %w", err)
+	}
+
+	var stash protectedRegionsStash
+	if err := json.Unmarshal(data, &stash); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ProtectedRegionsStashFile, err)
+	}
+
+	byPath := make(map[string][]ProtectedRegion)
+	var order []string
+	for _, region := range stash.Regions {
+		if _, ok := byPath[region.Path]; !ok {
+			order = append(order, region.Path)
+		}
+		byPath[region.Path] = append(byPath[region.Path], region)
+	}
+
+	var unrestored []ProtectedRegion
+	for _, path := range order {
+		fullPath := filepath.Join(outDir, path)
+		content, err := os.ReadFile(fullPath)
+		if err != nil {
+			unrestored = append(unrestored, byPath[path]...)
+			continue
+		}
+
+		injected, missing := injectProtectedRegions(string(content), byPath[path])
+		unrestored = append(unrestored, missing...)
+		if injected == string(content) {
+			continue
+		}
+		if err := writeFilePreservingMode(fullPath, []byte(injected)); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+
+	if len(unrestored) > 0 {
+		if err := writeProtectedRegionsStash(outDir, &protectedRegionsStash{Regions: unrestored}); err != nil {
+			return nil, err
+		}
+		return unrestored, nil
+	}
+
+	if err := os.Remove(protectedRegionsStashPath(outDir)); err != nil {
+		return nil, fmt.Errorf("failed to remove %s: %w", ProtectedRegionsStashFile, err)
+	}
+	return nil, nil
+}
+
+func writeProtectedRegionsStash(outDir string, stash *protectedRegionsStash) error {
+	data, err := json.MarshalIndent(stash, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := protectedRegionsStashPath(outDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ProtectedRegionsStashFile, err)
+	}
+	return nil
+}
+
+func writeFilePreservingMode(path string, content []byte) error {
+	perm := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+	return os.WriteFile(path, content, perm)
+}
@@ -0,0 +1,132 @@
+package patches
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const regionsFile = `package sdk
+
+func (s *SDK) Do() error {
+	req := s.build()
+	// speakeasy:custom-begin before-send
+	req.Header.Set("X-Custom", "1")
+	// speakeasy:custom-end
+	return s.send(req)
+}
+`
+
+func TestExtractProtectedRegions(t *testing.T) {
+	t.Parallel()
+
+	regions, stripped, err := extractProtectedRegions("sdk.go", regionsFile)
+	require.NoError(t, err)
+	require.Len(t, regions, 1)
+
+	region := regions[0]
+	assert.Equal(t, "before-send", region.ID)
+	assert.Equal(t, "req := s.build()", region.Anchor)
+	assert.Equal(t, "\treq.Header.Set(\"X-Custom\", \"1\")\n", region.Content)
+	assert.NotContains(t, stripped, "X-Custom")
+	assert.Contains(t, stripped, "// speakeasy:custom-begin before-send\n\t// speakeasy:custom-end\n")
+}
+
+func TestExtractProtectedRegions_Malformed(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"unclosed":  "// speakeasy:custom-begin a\nx\n",
+		"nested":    "// speakeasy:custom-begin a\n// speakeasy:custom-begin b\n// speakeasy:custom-end\n",
+		"orphan":    "x\n// speakeasy:custom-end\n",
+		"duplicate": "// speakeasy:custom-begin a\n// speakeasy:custom-end\n// speakeasy:custom-begin a\n// speakeasy:custom-end\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := extractProtectedRegions("f.go", content)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestInjectProtectedRegions(t *testing.T) {
+	t.Parallel()
+
+	regions, stripped, err := extractProtectedRegions("sdk.go", regionsFile)
+	require.NoError(t, err)
+
+	t.Run("markers kept", func(t *testing.T) {
+		t.Parallel()
+		injected, missing := injectProtectedRegions(stripped, regions)
+		assert.Empty(t, missing)
+		assert.Equal(t, regionsFile, injected)
+	})
+
+	t.Run("markers lost", func(t *testing.T) {
+		t.Parallel()
+		regenerated := "package sdk\n\nfunc (s *SDK) Do() error {\n\treq := s.build()\n\treturn s.sendV2(req)\n}\n"
+		injected, missing := injectProtectedRegions(regenerated, regions)
+		assert.Empty(t, missing)
+		assert.Equal(t, "package sdk\n\nfunc (s *SDK) Do() error {\n\treq := s.build()\n"+
+			"\t// speakeasy:custom-begin before-send\n\treq.Header.Set(\"X-Custom\", \"1\")\n\t// speakeasy:custom-end\n"+
+			"\treturn s.sendV2(req)\n}\n", injected)
+	})
+
+	t.Run("anchor gone", func(t *testing.T) {
+		t.Parallel()
+		injected, missing := injectProtectedRegions("package sdk\n", regions)
+		assert.Equal(t, "package sdk\n", injected)
+		assert.Equal(t, regions, missing)
+	})
+}
+
+func TestStashAndRestoreProtectedRegions(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sdk.go"), []byte(regionsFile), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "models.go"), []byte("package sdk\n"), 0o644))
+
+	warn := func(format string, args ...any) { t.Errorf(format, args...) }
+	count, err := StashProtectedRegions(dir, []string{"sdk.go", "models.go", "missing.go"}, warn)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.FileExists(t, protectedRegionsStashPath(dir))
+
+	content, err := os.ReadFile(filepath.Join(dir, "sdk.go"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "X-Custom")
+
+	// Simulate a generation that rewrites the file without the markers.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sdk.go"), []byte("package sdk\n\nfunc (s *SDK) Do() error {\n\treq := s.build()\n\treturn s.send(req)\n}\n"), 0o644))
+
+	unrestored, err := RestoreProtectedRegions(dir)
+	require.NoError(t, err)
+	assert.Empty(t, unrestored)
+	assert.NoFileExists(t, protectedRegionsStashPath(dir))
+
+	content, err = os.ReadFile(filepath.Join(dir, "sdk.go"))
+	require.NoError(t, err)
+	assert.Equal(t, regionsFile, string(content))
+}
+
+func TestRestoreProtectedRegions_KeepsUnrestored(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sdk.go"), []byte(regionsFile), 0o644))
+
+	_, err := StashProtectedRegions(dir, []string{"sdk.go"}, func(string, ...any) {})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sdk.go"), []byte("package other\n"), 0o644))
+
+	unrestored, err := RestoreProtectedRegions(dir)
+	require.NoError(t, err)
+	require.Len(t, unrestored, 1)
+	assert.Equal(t, "before-send", unrestored[0].ID)
+	assert.FileExists(t, protectedRegionsStashPath(dir))
+}
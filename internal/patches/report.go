@@ -0,0 +1,320 @@
+package patches
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	config "github.com/speakeasy-api/sdk-gen-config"
+	"github.com/speakeasy-api/speakeasy/internal/git"
+	"github.com/speakeasy-api/speakeasy/internal/markdown"
+)
+
+// ConflictStatus predicts how a customized file will fare in the next
+// generation.
+type ConflictStatus string
+
+const (
+	// ConflictUnknown means no upcoming generation was available to compare.
+	ConflictUnknown ConflictStatus = "unknown"
+	// ConflictNone means the custom code merges cleanly with the new generation.
+	ConflictNone ConflictStatus = "clean"
+	// ConflictExpected means the custom code and the new generation change the
+	// same lines.
+	ConflictExpected ConflictStatus = "conflict"
+	// ConflictRemoved means the new generation no longer produces the file.
+	ConflictRemoved ConflictStatus = "removed"
+)
+
+// FileReport describes the custom code in one generated file.
+type FileReport struct {
+	Path    string `json:"path"`
+	Added   int    `json:"added"`
+	Removed int    `json:"removed"`
+	// FirstCustomized and LastCustomized are the oldest and newest commit
+	// times of the custom lines, from git blame. They are nil when the file
+	// has no added lines or is not committed.
+	FirstCustomized *time.Time     `json:"firstCustomized,omitempty"`
+	LastCustomized  *time.Time     `json:"lastCustomized,omitempty"`
+	Conflict        ConflictStatus `json:"conflict"`
+	ConflictHunks   int            `json:"conflictHunks,omitempty"`
+	Risk            int            `json:"risk"`
+}
+
+// AgeDays returns how many days ago the file was first customized.
+func (f FileReport) AgeDays(now time.Time) int {
+	if f.FirstCustomized == nil {
+		return 0
+	}
+	return int(now.Sub(*f.FirstCustomized).Hours() / 24)
+}
+
+// Report is the health of the custom code in a generated SDK.
+type Report struct {
+	GeneratedAt time.Time    `json:"generatedAt"`
+	Files       []FileReport `json:"files"`
+	// Risk is the highest risk of any file, from 0 to 100.
+	Risk      int  `json:"risk"`
+	Predicted bool `json:"conflictsPredicted"`
+}
+
+// RiskLevel buckets a risk score into low, medium or high.
+func RiskLevel(risk int) string {
+	switch {
+	case risk >= 60:
+		return "high"
+	case risk >= 30:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+// BuildReport reports on every tracked file whose content differs from its
+// pristine version. When nextDir holds the output of an upcoming generation,
+// each file is also three-way merged against it to predict conflicts.
+func BuildReport(outDir string, lockFile *config.LockFile, gitRepo GitRepository, nextDir string) (*Report, []string, error) {
+	if lockFile == nil || lockFile.TrackedFiles == nil {
+		return nil, nil, fmt.Errorf("no tracked files in gen.lock")
+	}
+
+	report := &Report{GeneratedAt: time.Now().UTC(), Predicted: nextDir != ""}
+	var warnings []string
+
+	var paths []string
+	for path := range lockFile.TrackedFiles.Keys() {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+
+	for _, path := range paths {
+		tracked, ok := lockFile.TrackedFiles.Get(path)
+		if !ok || tracked.PristineGitObject == "" {
+			continue
+		}
+
+		fd := ComputeFileDiff(outDir, path, tracked.PristineGitObject, gitRepo)
+		if fd.Stats.Added+fd.Stats.Removed == 0 {
+			continue
+		}
+
+		file := FileReport{
+			Path:     path,
+			Added:    fd.Stats.Added,
+			Removed:  fd.Stats.Removed,
+			Conflict: ConflictUnknown,
+		}
+
+		first, last, err := blameLines(outDir, path, addedLineNumbers(fd.DiffText))
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: could not determine customization age: %v", path, err))
+		} else {
+			file.FirstCustomized, file.LastCustomized = first, last
+		}
+
+		if nextDir != "" {
+			file.Conflict, file.ConflictHunks, err = predictConflict(outDir, path, tracked.PristineGitObject, gitRepo, nextDir)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s: could not predict conflicts: %v", path, err))
+			}
+		}
+
+		file.Risk = fileRisk(file, report.GeneratedAt)
+		report.Risk = max(report.Risk, file.Risk)
+		report.Files = append(report.Files, file)
+	}
+
+	return report, warnings, nil
+}
+
+func predictConflict(outDir, path, pristineHash string, gitRepo GitRepository, nextDir string) (ConflictStatus, int, error) {
+	next, err := os.ReadFile(filepath.Join(nextDir, path))
+	if os.IsNotExist(err) {
+		return ConflictRemoved, 0, nil
+	} else if err != nil {
+		return ConflictUnknown, 0, err
+	}
+
+	pristine, err := gitRepo.GetBlob(pristineHash)
+	if err != nil {
+		return ConflictUnknown, 0, err
+	}
+	current, err := os.ReadFile(filepath.Join(outDir, path))
+	if err != nil {
+		return ConflictUnknown, 0, err
+	}
+
+	merged := NewConflictFile(path, pristine, current, next)
+	if len(merged.Hunks) > 0 {
+		return ConflictExpected, len(merged.Hunks), nil
+	}
+	return ConflictNone, 0, nil
+}
+
+// fileRisk scores a customization from 0 to 100. Predicted conflicts weigh
+// most, followed by the size of the change and how long it has been carried
+// across generations.
+func fileRisk(f FileReport, now time.Time) int {
+	risk := 0
+	switch f.Conflict {
+	case ConflictExpected, ConflictRemoved:
+		risk += 50
+	case ConflictUnknown:
+		risk += 15
+	}
+	risk += min(30, (f.Added+f.Removed)/5)
+	risk += min(20, f.AgeDays(now)/30)
+	return min(100, risk)
+}
+
+var hunkHeader = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// addedLineNumbers returns the 1-based line numbers in the new file of the
+// lines added by a unified diff.
+func addedLineNumbers(diffText string) []int {
+	var lines []int
+	line := 0
+	for _, l := range strings.Split(diffText, "\n") {
+		if m := hunkHeader.FindStringSubmatch(l); m != nil {
+			line, _ = strconv.Atoi(m[1])
+			continue
+		}
+		if line == 0 {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(l, "+"):
+			lines = append(lines, line)
+			line++
+		case strings.HasPrefix(l, " "):
+			line++
+		}
+	}
+	return lines
+}
+
+// blameLines returns the oldest and newest commit times of the given lines.
+func blameLines(dir, path string, lines []int) (first, last *time.Time, err error) {
+	if len(lines) == 0 {
+		return nil, nil, nil
+	}
+
+	out, err := git.RunGitCommand(dir, "blame", "--line-porcelain", "--", path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	times := parseBlameTimes(out)
+	for _, line := range lines {
+		if line < 1 || line > len(times) {
+			continue
+		}
+		t := times[line-1]
+		if first == nil || t.Before(*first) {
+			first = &t
+		}
+		if last == nil || t.After(*last) {
+			last = &t
+		}
+	}
+	return first, last, nil
+}
+
+// parseBlameTimes returns the author time of each line of
+// `git blame --line-porcelain` output, in file order.
+func parseBlameTimes(out string) []time.Time {
+	var times []time.Time
+	var current time.Time
+	for _, l := range strings.Split(out, "\n") {
+		switch {
+		case strings.HasPrefix(l, "author-time "):
+			if secs, err := strconv.ParseInt(strings.TrimPrefix(l, "author-time "), 10, 64); err == nil {
+				current = time.Unix(secs, 0).UTC()
+			}
+		case strings.HasPrefix(l, "\t"):
+			times = append(times, current)
+		}
+	}
+	return times
+}
+
+func (r *Report) rows() [][]string {
+	rows := [][]string{{"File", "Added", "Removed", "Age", "Next generation", "Risk"}}
+	for _, f := range r.Files {
+		age := "-"
+		if f.FirstCustomized != nil {
+			age = formatAge(f.AgeDays(r.GeneratedAt))
+		}
+		conflict := string(f.Conflict)
+		if f.Conflict == ConflictExpected {
+			conflict = fmt.Sprintf("conflict (%d %s)", f.ConflictHunks, pluralize(f.ConflictHunks, "hunk"))
+		}
+		rows = append(rows, []string{
+			f.Path,
+			fmt.Sprintf("+%d", f.Added),
+			fmt.Sprintf("-%d", f.Removed),
+			age,
+			conflict,
+			fmt.Sprintf("%d (%s)", f.Risk, RiskLevel(f.Risk)),
+		})
+	}
+	return rows
+}
+
+func formatAge(days int) string {
+	switch {
+	case days < 1:
+		return "today"
+	case days < 60:
+		return fmt.Sprintf("%d %s", days, pluralize(days, "day"))
+	default:
+		return fmt.Sprintf("%d months", days/30)
+	}
+}
+
+// Table renders the report as an aligned plain text table.
+func (r *Report) Table() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	for _, row := range r.rows() {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+	fmt.Fprintf(&b, "\n%s, overall risk %d (%s)\n", r.summary(), r.Risk, RiskLevel(r.Risk))
+	return b.String()
+}
+
+// Markdown renders the report for a pull request comment.
+func (r *Report) Markdown() string {
+	var b strings.Builder
+	b.WriteString("## Custom code report\n\n")
+	fmt.Fprintf(&b, "%s. Overall risk: **%d (%s)**.\n\n", r.summary(), r.Risk, RiskLevel(r.Risk))
+	if len(r.Files) > 0 {
+		b.WriteString(markdown.CreateMarkdownTable(r.rows()))
+		b.WriteString("\n")
+	}
+	if !r.Predicted {
+		b.WriteString("\n_Conflicts were not predicted: no upcoming generation was provided._\n")
+	}
+	return b.String()
+}
+
+func (r *Report) summary() string {
+	conflicts := 0
+	for _, f := range r.Files {
+		if f.Conflict == ConflictExpected || f.Conflict == ConflictRemoved {
+			conflicts++
+		}
+	}
+	s := fmt.Sprintf("%d customized %s", len(r.Files), pluralize(len(r.Files), "file"))
+	if r.Predicted {
+		s += fmt.Sprintf(", %d expected to conflict", conflicts)
+	}
+	return s
+}
@@ -0,0 +1,98 @@
+package patches
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddedLineNumbers(t *testing.T) {
+	t.Parallel()
+
+	diff := `--- generated
++++ current
+@@ -1,4 +1,5 @@
+ a
+-b
++B
++B2
+ c
+ d
+@@ -10,2 +11,3 @@
+ x
++y
+ z
+`
+	assert.Equal(t, []int{2, 3, 12}, addedLineNumbers(diff))
+}
+
+func TestParseBlameTimes(t *testing.T) {
+	t.Parallel()
+
+	out := "abc123 1 1 2\nauthor Jane\nauthor-time 1700000000\nfilename f.go\n\tline one\n" +
+		"abc123 2 2\nauthor Jane\nauthor-time 1700000000\nfilename f.go\n\tline two\n" +
+		"def456 3 3 1\nauthor Sam\nauthor-time 1710000000\nfilename f.go\n\tline three\n"
+
+	times := parseBlameTimes(out)
+	assert.Equal(t, []time.Time{
+		time.Unix(1700000000, 0).UTC(),
+		time.Unix(1700000000, 0).UTC(),
+		time.Unix(1710000000, 0).UTC(),
+	}, times)
+}
+
+func TestFileRisk(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := now.AddDate(-2, 0, 0)
+
+	tests := []struct {
+		name     string
+		file     FileReport
+		expected int
+		level    string
+	}{
+		{"small clean", FileReport{Added: 3, Removed: 1, Conflict: ConflictNone}, 0, "low"},
+		{"unknown", FileReport{Added: 50, Removed: 0, Conflict: ConflictUnknown}, 25, "low"},
+		{"conflict", FileReport{Added: 100, Removed: 20, Conflict: ConflictExpected}, 74, "high"},
+		{"old removed", FileReport{Added: 10, Conflict: ConflictRemoved, FirstCustomized: &old}, 72, "high"},
+		{"old clean", FileReport{Added: 200, Conflict: ConflictNone, FirstCustomized: &old}, 50, "medium"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			risk := fileRisk(tc.file, now)
+			assert.Equal(t, tc.expected, risk)
+			assert.Equal(t, tc.level, RiskLevel(risk))
+		})
+	}
+}
+
+func TestReportMarkdown(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	first := now.AddDate(0, 0, -10)
+	report := &Report{
+		GeneratedAt: now,
+		Predicted:   true,
+		Risk:        56,
+		Files: []FileReport{
+			{Path: "src/sdk.ts", Added: 12, Removed: 2, FirstCustomized: &first, LastCustomized: &first, Conflict: ConflictExpected, ConflictHunks: 2, Risk: 56},
+			{Path: "src/models.ts", Added: 1, Conflict: ConflictNone},
+		},
+	}
+
+	md := report.Markdown()
+	assert.Contains(t, md, "2 customized files, 1 expected to conflict. Overall risk: **56 (medium)**.")
+	assert.Contains(t, md, "src/sdk.ts")
+	assert.Contains(t, md, "conflict (2 hunks)")
+	assert.Contains(t, md, "10 days")
+	assert.NotContains(t, md, "were not predicted")
+
+	table := report.Table()
+	assert.Contains(t, table, "src/models.ts")
+	assert.Contains(t, table, "overall risk 56 (medium)")
+}
@@ -0,0 +1,87 @@
+package patches
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	config "github.com/speakeasy-api/sdk-gen-config"
+	"github.com/speakeasy-api/speakeasy/internal/git"
+)
+
+// LoadConflicts returns the files under outDir left conflicted in the git
+// index by a generation, built from their base (previous pristine), ours
+// (custom code) and theirs (new pristine) index stages. Paths are relative
+// to outDir.
+func LoadConflicts(outDir string) ([]*ConflictFile, error) {
+	out, err := git.RunGitCommand(outDir, "diff", "--name-only", "--relative", "--diff-filter=U")
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*ConflictFile
+	for _, path := range strings.Split(strings.TrimSpace(out), "\n") {
+		if path == "" {
+			continue
+		}
+
+		ours, err := indexStage(outDir, 2, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read custom version of %s: %w", path, err)
+		}
+		theirs, err := indexStage(outDir, 3, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read generated version of %s: %w", path, err)
+		}
+		// Files added on both sides have no base.
+		base, _ := indexStage(outDir, 1, path)
+
+		files = append(files, NewConflictFile(path, base, ours, theirs))
+	}
+
+	return files, nil
+}
+
+func indexStage(dir string, stage int, path string) ([]byte, error) {
+	content, err := git.RunGitCommand(dir, "show", fmt.Sprintf(":%d:./%s", stage, path))
+	if err != nil {
+		return nil, err
+	}
+	return []byte(content), nil
+}
+
+// ApplyResolution writes a fully resolved file, marks it resolved in the git
+// index and records the newly generated version (theirs) as the file's
+// pristine object in the lockfile, so the resolved custom code is diffed
+// against it from now on. The caller saves the lockfile.
+func ApplyResolution(outDir string, lockFile *config.LockFile, gitRepo GitRepository, file *ConflictFile) error {
+	content, err := file.Content()
+	if err != nil {
+		return err
+	}
+
+	if err := writeFilePreservingMode(filepath.Join(outDir, file.Path), content); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file.Path, err)
+	}
+
+	if _, err := git.RunGitCommand(outDir, "add", "--", file.Path); err != nil {
+		return err
+	}
+
+	if lockFile == nil || lockFile.TrackedFiles == nil {
+		return nil
+	}
+	tracked, ok := lockFile.TrackedFiles.Get(filepath.ToSlash(file.Path))
+	if !ok {
+		return nil
+	}
+
+	pristine, err := gitRepo.WriteBlob(file.Theirs)
+	if err != nil {
+		return fmt.Errorf("failed to store pristine version of %s: %w", file.Path, err)
+	}
+	tracked.PristineGitObject = pristine
+	lockFile.TrackedFiles.Set(filepath.ToSlash(file.Path), tracked)
+
+	return nil
+}
@@ -0,0 +1,251 @@
+package patches
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	config "github.com/speakeasy-api/sdk-gen-config"
+	"github.com/speakeasy-api/speakeasy/internal/git"
+)
+
+// SeriesManifestFile is the name of the manifest written alongside an
+// exported patch series.
+const SeriesManifestFile = "series.json"
+
+// SeriesManifest describes an exported patch series.
+type SeriesManifest struct {
+	SpeakeasyVersion  string          `json:"speakeasyVersion,omitempty"`
+	GenerationVersion string          `json:"generationVersion,omitempty"`
+	ReleaseVersion    string          `json:"releaseVersion,omitempty"`
+	ExportedAt        time.Time       `json:"exportedAt"`
+	Patches           []PatchMetadata `json:"patches"`
+}
+
+var unsafePatchNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// ExportSeries writes one patch per customized tracked file to destDir,
+// numbered in path order like `git format-patch`, along with a manifest.
+// Files that are binary or missing a pristine object are skipped and
+// returned as warnings.
+func ExportSeries(outDir string, lockFile *config.LockFile, gitRepo GitRepository, destDir string) (*SeriesManifest, []string, error) {
+	if lockFile == nil || lockFile.TrackedFiles == nil {
+		return nil, nil, fmt.Errorf("no tracked files in gen.lock")
+	}
+
+	scanResult, err := NewScanner(outDir).Scan()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to scan %s: %w", outDir, err)
+	}
+
+	type customization struct {
+		meta              PatchMetadata
+		pristine, current []byte
+	}
+
+	var customizations []customization
+	var warnings []string
+
+	var paths []string
+	for path := range lockFile.TrackedFiles.Keys() {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+
+	for _, path := range paths {
+		tracked, ok := lockFile.TrackedFiles.Get(path)
+		if !ok || tracked.PristineGitObject == "" {
+			continue
+		}
+
+		// Follow files the user has moved since generation.
+		currentPath := path
+		if movedTo, ok := scanResult.UUIDToPath[tracked.ID]; ok && tracked.ID != "" {
+			currentPath = movedTo
+		}
+
+		current, err := os.ReadFile(filepath.Join(outDir, currentPath))
+		if err != nil {
+			continue
+		}
+
+		pristine, err := gitRepo.GetBlob(tracked.PristineGitObject)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: pristine object %s not found", path, tracked.PristineGitObject))
+			continue
+		}
+
+		if normalizeLineEndings(string(pristine)) == normalizeLineEndings(string(current)) {
+			continue
+		}
+		if isBinary(pristine) || isBinary(current) {
+			warnings = append(warnings, fmt.Sprintf("%s: binary files cannot be exported", path))
+			continue
+		}
+
+		customizations = append(customizations, customization{
+			meta: PatchMetadata{
+				Path:           currentPath,
+				GeneratedID:    tracked.ID,
+				PristineObject: tracked.PristineGitObject,
+			},
+			pristine: pristine,
+			current:  current,
+		})
+	}
+
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create %s: %w", destDir, err)
+	}
+
+	manifest := &SeriesManifest{
+		SpeakeasyVersion:  lockFile.Management.SpeakeasyVersion,
+		GenerationVersion: lockFile.Management.GenerationVersion,
+		ReleaseVersion:    lockFile.Management.ReleaseVersion,
+		ExportedAt:        time.Now().UTC(),
+	}
+
+	for i, c := range customizations {
+		meta := c.meta
+		meta.File = fmt.Sprintf("%04d-%s.patch", i+1, strings.Trim(unsafePatchNameChars.ReplaceAllString(meta.Path, "-"), "-"))
+
+		content := FormatPatch(meta, i+1, len(customizations), c.pristine, c.current, manifest.ExportedAt)
+		stats := countDiffStats(patchDiff(content))
+		meta.Added, meta.Removed = stats.Added, stats.Removed
+
+		if err := os.WriteFile(filepath.Join(destDir, meta.File), []byte(content), 0o644); err != nil {
+			return nil, nil, fmt.Errorf("failed to write patch %s: %w", meta.File, err)
+		}
+		manifest.Patches = append(manifest.Patches, meta)
+	}
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := os.WriteFile(filepath.Join(destDir, SeriesManifestFile), manifestJSON, 0o644); err != nil {
+		return nil, nil, fmt.Errorf("failed to write %s: %w", SeriesManifestFile, err)
+	}
+
+	return manifest, warnings, nil
+}
+
+// ImportedPatch is the outcome of applying one patch of a series.
+type ImportedPatch struct {
+	Patch PatchMetadata
+	// Path is where the patch was applied, which differs from Patch.Path if
+	// the file was found elsewhere by its @generated-id.
+	Path string
+	Err  error
+}
+
+// ImportSeries applies a patch series exported by ExportSeries to outDir,
+// which is typically a freshly generated SDK. Patches are applied with
+// `git apply`, falling back to a three-way merge against the pristine object
+// when threeWay is set and outDir is inside a git repository. A failing
+// patch does not stop the remaining patches from being applied.
+func ImportSeries(outDir, seriesDir string, threeWay bool) ([]ImportedPatch, error) {
+	files, err := seriesPatchFiles(seriesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	scanResult, err := NewScanner(outDir).Scan()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", outDir, err)
+	}
+
+	// Inside a repository, git apply reads patch paths relative to its root
+	// and silently skips those outside the working directory, so they are
+	// prefixed with outDir's path in the repository.
+	inRepo := false
+	prefix := ""
+	if out, err := git.RunGitCommand(outDir, "rev-parse", "--show-prefix"); err == nil {
+		inRepo = true
+		prefix = strings.TrimSpace(out)
+	}
+
+	results := make([]ImportedPatch, 0, len(files))
+	for _, file := range files {
+		content, err := os.ReadFile(filepath.Join(seriesDir, file))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read patch %s: %w", file, err)
+		}
+
+		meta, err := ParsePatch(string(content))
+		if err != nil {
+			results = append(results, ImportedPatch{Patch: PatchMetadata{File: file}, Err: err})
+			continue
+		}
+		meta.File = file
+
+		target := meta.Path
+		if movedTo, ok := scanResult.UUIDToPath[meta.GeneratedID]; ok && meta.GeneratedID != "" {
+			target = movedTo
+		}
+
+		args := []string{"apply", "--whitespace=nowarn"}
+		if prefix != "" {
+			args = append(args, "--directory="+prefix)
+		}
+		if threeWay && inRepo {
+			args = append(args, "--3way")
+		}
+
+		before, _ := os.ReadFile(filepath.Join(outDir, target))
+
+		patch := RetargetPatch(string(content), meta.Path, target)
+		_, err = git.RunGitCommandWithStdin(outDir, strings.NewReader(patch), args...)
+		if err == nil {
+			// An exported patch always changes its file, so one that applied
+			// cleanly without changing it was skipped by git.
+			if after, readErr := os.ReadFile(filepath.Join(outDir, target)); readErr == nil && string(after) == string(before) {
+				err = fmt.Errorf("git apply made no changes to %s", target)
+			}
+		}
+		results = append(results, ImportedPatch{Patch: meta, Path: target, Err: err})
+	}
+
+	return results, nil
+}
+
+// seriesPatchFiles returns the patches of a series in application order,
+// using the manifest if present.
+func seriesPatchFiles(seriesDir string) ([]string, error) {
+	manifestJSON, err := os.ReadFile(filepath.Join(seriesDir, SeriesManifestFile))
+	if err == nil {
+		var manifest SeriesManifest
+		if err := json.Unmarshal(manifestJSON, &manifest); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", SeriesManifestFile, err)
+		}
+
+		files := make([]string, 0, len(manifest.Patches))
+		for _, patch := range manifest.Patches {
+			files = append(files, patch.File)
+		}
+		return files, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", SeriesManifestFile, err)
+	}
+
+	matches, err := filepath.Glob(filepath.Join(seriesDir, "*.patch"))
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no patches found in %s", seriesDir)
+	}
+
+	files := make([]string, 0, len(matches))
+	for _, match := range matches {
+		files = append(files, filepath.Base(match))
+	}
+	slices.Sort(files)
+	return files, nil
+}
@@ -0,0 +1,72 @@
+package patches
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/speakeasy-api/sdk-gen-config/lockfile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImportSeries(t *testing.T) {
+	t.Parallel()
+
+	const pristine = "package sdk\n\nfunc A() {}\n"
+	const custom = "package sdk\n\n// A is customized.\nfunc A() {}\n"
+
+	for name, threeWay := range map[string]bool{"apply": false, "3way": true} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// The SDK is generated into a subdirectory of the repository.
+			dir := initDoctorTestRepo(t, map[string]string{"README.md": "# repo\n"})
+			outDir := filepath.Join(dir, "sdks", "go")
+			require.NoError(t, os.MkdirAll(outDir, 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(outDir, "sdk.go"), []byte(custom), 0o644))
+
+			pristineHash := gitBlobHash([]byte(pristine))
+			lf := lockfile.New()
+			lf.TrackedFiles.Set("sdk.go", lockfile.TrackedFile{PristineGitObject: pristineHash})
+			lf.TrackedFiles.Set("unchanged.go", lockfile.TrackedFile{PristineGitObject: pristineHash})
+			repo := &mockGitRepo{blobs: map[string][]byte{pristineHash: []byte(pristine)}}
+
+			seriesDir := t.TempDir()
+			manifest, warnings, err := ExportSeries(outDir, lf, repo, seriesDir)
+			require.NoError(t, err)
+			assert.Empty(t, warnings)
+			require.Len(t, manifest.Patches, 1)
+			assert.Equal(t, "sdk.go", manifest.Patches[0].Path)
+			assert.FileExists(t, filepath.Join(seriesDir, SeriesManifestFile))
+
+			// Regenerate the file, dropping the customization.
+			require.NoError(t, os.WriteFile(filepath.Join(outDir, "sdk.go"), []byte(pristine), 0o644))
+			for _, args := range [][]string{
+				{"add", "."},
+				{"-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "generate"},
+			} {
+				cmd := exec.Command("git", args...)
+				cmd.Dir = dir
+				out, err := cmd.CombinedOutput()
+				require.NoError(t, err, string(out))
+			}
+
+			results, err := ImportSeries(outDir, seriesDir, threeWay)
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+			assert.NoError(t, results[0].Err)
+			assert.Equal(t, "sdk.go", results[0].Path)
+
+			content, err := os.ReadFile(filepath.Join(outDir, "sdk.go"))
+			require.NoError(t, err)
+			assert.Equal(t, custom, string(content))
+
+			// Applying the series again fails, as the customization is already there.
+			results, err = ImportSeries(outDir, seriesDir, false)
+			require.NoError(t, err)
+			assert.Error(t, results[0].Err)
+		})
+	}
+}
@@ -0,0 +1,275 @@
+// Package rollback reverts a bad SDK release: it locates the generation
+// commit that introduced a version, reverts it on a new branch and restores
+// the lockfiles to their previous state.
+package rollback
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/speakeasy-api/speakeasy/internal/ci/fanout"
+	"github.com/speakeasy-api/speakeasy/internal/git"
+	"gopkg.in/yaml.v3"
+)
+
+// GenerationCommit is the commit that bumped a target to a release version.
+type GenerationCommit struct {
+	Hash string
+	// PreviousVersion is the release version recorded in gen.lock before
+	// Hash, or empty if gen.lock did not exist.
+	PreviousVersion string
+}
+
+// FindGenerationCommit walks the history of genLockPath (relative to
+// repoDir) and returns the commit that first recorded version as the
+// release version.
+func FindGenerationCommit(repoDir, genLockPath, version string) (*GenerationCommit, error) {
+	out, err := git.RunGitCommand(repoDir, "log", "--format=%H", "--", genLockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var found *GenerationCommit
+	for _, hash := range strings.Fields(out) {
+		commitVersion := releaseVersionAt(repoDir, hash, genLockPath)
+		if commitVersion == version {
+			found = &GenerationCommit{Hash: hash}
+			continue
+		}
+		if found != nil {
+			found.PreviousVersion = commitVersion
+			return found, nil
+		}
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("no commit found that released version %s in %s", version, genLockPath)
+	}
+	return found, nil
+}
+
+// releaseVersionAt returns the release version in gen.lock at the given
+// revision, or empty if the file does not exist or cannot be parsed.
+func releaseVersionAt(repoDir, rev, genLockPath string) string {
+	content, err := git.RunGitCommand(repoDir, "show", rev+":"+filepath.ToSlash(genLockPath))
+	if err != nil {
+		return ""
+	}
+
+	var lock struct {
+		Management struct {
+			ReleaseVersion string `yaml:"releaseVersion"`
+		} `yaml:"management"`
+	}
+	if err := yaml.Unmarshal([]byte(content), &lock); err != nil {
+		return ""
+	}
+	return lock.Management.ReleaseVersion
+}
+
+// Options configure Revert. Paths are relative to RepoDir.
+type Options struct {
+	RepoDir          string
+	Branch           string
+	Commit           string
+	GenLockPath      string
+	WorkflowLockPath string
+	Message          string
+	// TargetDir is the output directory of the target being rolled back, and
+	// OtherTargetDirs those of the workflow's other targets. Commits changing
+	// files outside TargetDir, or inside another target's directory, are
+	// refused so other SDKs aren't rolled back with it.
+	TargetDir       string
+	OtherTargetDirs []string
+}
+
+// Result describes the rollback commit created by Revert.
+type Result struct {
+	Branch string
+	Commit string
+	// Overridden lists lockfile values changed both by the reverted commit
+	// and later commits, which were set back to their pre-release value.
+	Overridden []string
+}
+
+// Revert creates Branch from HEAD and commits a revert of Commit on it.
+// Conflicts in the lockfiles are resolved by a structural merge that undoes
+// only the reverted commit's changes; RELEASES.md keeps its history. Any
+// other conflict aborts the rollback, returning to the original branch.
+func Revert(opts Options) (*Result, error) {
+	status, err := git.RunGitCommand(opts.RepoDir, "status", "--porcelain", "--untracked-files=no")
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(status) != "" {
+		return nil, fmt.Errorf("working tree has uncommitted changes, commit or stash them before rolling back")
+	}
+
+	if err := checkTargetOnly(opts); err != nil {
+		return nil, err
+	}
+
+	original, err := currentRef(opts.RepoDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := git.RunGitCommand(opts.RepoDir, "checkout", "-b", opts.Branch); err != nil {
+		return nil, err
+	}
+
+	// The working tree was clean, so the rollback branch can be discarded.
+	abort := func(err error) (*Result, error) {
+		_, _ = git.RunGitCommand(opts.RepoDir, "revert", "--abort")
+		_, _ = git.RunGitCommand(opts.RepoDir, "reset", "--hard")
+		_, _ = git.RunGitCommand(opts.RepoDir, "checkout", original)
+		_, _ = git.RunGitCommand(opts.RepoDir, "branch", "-D", opts.Branch)
+		return nil, err
+	}
+
+	result := &Result{Branch: opts.Branch}
+
+	if _, revertErr := git.RunGitCommand(opts.RepoDir, "revert", "--no-commit", opts.Commit); revertErr != nil {
+		overridden, err := resolveConflicts(opts)
+		if err != nil {
+			return abort(fmt.Errorf("failed to revert %s: %w", opts.Commit, err))
+		}
+		result.Overridden = overridden
+	}
+
+	if _, err := git.RunGitCommand(opts.RepoDir, "-c", "core.editor=true", "commit", "--allow-empty", "-m", opts.Message); err != nil {
+		return abort(err)
+	}
+
+	result.Commit, err = git.RunGitCommand(opts.RepoDir, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	result.Commit = strings.TrimSpace(result.Commit)
+
+	return result, nil
+}
+
+// checkTargetOnly returns an error if Commit changes files of other targets,
+// such as a squashed commit generating several SDKs.
+func checkTargetOnly(opts Options) error {
+	out, err := git.RunGitCommand(opts.RepoDir, "diff-tree", "--no-commit-id", "--name-only", "-r", opts.Commit)
+	if err != nil {
+		return err
+	}
+
+	targetDir := filepath.ToSlash(filepath.Clean(opts.TargetDir))
+	workflowLockPath := filepath.ToSlash(filepath.Clean(opts.WorkflowLockPath))
+
+	var others []string
+	for _, file := range strings.Split(strings.TrimSpace(out), "\n") {
+		if file == "" || file == workflowLockPath {
+			continue
+		}
+
+		// A file belongs to the target with the deepest output directory
+		// containing it, as targets can be generated into subdirectories of
+		// another's.
+		owner, ownerDepth := "", -1
+		if isWithin(file, targetDir) {
+			owner, ownerDepth = targetDir, depth(targetDir)
+		}
+		for _, dir := range opts.OtherTargetDirs {
+			dir = filepath.ToSlash(filepath.Clean(dir))
+			if isWithin(file, dir) && depth(dir) > ownerDepth {
+				owner, ownerDepth = dir, depth(dir)
+			}
+		}
+		if owner != targetDir {
+			others = append(others, file)
+		}
+	}
+
+	if len(others) > 0 {
+		return fmt.Errorf("commit %s also changes files outside the target, such as %s: revert it manually to roll back more than one target", opts.Commit, strings.Join(others[:min(len(others), 3)], ", "))
+	}
+	return nil
+}
+
+// isWithin reports whether path is inside dir, both slash-separated and
+// relative to the repository root.
+func isWithin(path, dir string) bool {
+	return dir == "." || path == dir || strings.HasPrefix(path, dir+"/")
+}
+
+func depth(dir string) int {
+	if dir == "." {
+		return 0
+	}
+	return strings.Count(dir, "/") + 1
+}
+
+// currentRef returns the checked out branch, or the commit if HEAD is detached.
+func currentRef(repoDir string) (string, error) {
+	if branch, err := git.RunGitCommand(repoDir, "symbolic-ref", "--short", "-q", "HEAD"); err == nil && strings.TrimSpace(branch) != "" {
+		return strings.TrimSpace(branch), nil
+	}
+	hash, err := git.RunGitCommand(repoDir, "rev-parse", "HEAD")
+	return strings.TrimSpace(hash), err
+}
+
+func resolveConflicts(opts Options) ([]string, error) {
+	out, err := git.RunGitCommand(opts.RepoDir, "diff", "--name-only", "--diff-filter=U")
+	if err != nil {
+		return nil, err
+	}
+	conflicted := strings.Fields(out)
+	if len(conflicted) == 0 {
+		return nil, fmt.Errorf("revert failed without conflicts")
+	}
+
+	lockfiles := map[string]bool{
+		filepath.ToSlash(filepath.Clean(opts.GenLockPath)):      true,
+		filepath.ToSlash(filepath.Clean(opts.WorkflowLockPath)): true,
+	}
+
+	var overridden []string
+	var unresolved []string
+	for _, file := range conflicted {
+		var content []byte
+		switch {
+		case lockfiles[file]:
+			// In a revert, the base is the reverted commit and "theirs" is its parent.
+			result, err := fanout.MergeLockfile(indexStage(opts.RepoDir, 1, file), indexStage(opts.RepoDir, 2, file), indexStage(opts.RepoDir, 3, file))
+			if err != nil {
+				return nil, fmt.Errorf("failed to merge %s: %w", file, err)
+			}
+			content = result.Content
+			for _, location := range result.Overridden {
+				overridden = append(overridden, file+":"+location)
+			}
+		case filepath.Base(file) == "RELEASES.md":
+			content = indexStage(opts.RepoDir, 2, file)
+		default:
+			unresolved = append(unresolved, file)
+			continue
+		}
+
+		if err := os.WriteFile(filepath.Join(opts.RepoDir, file), content, 0o644); err != nil {
+			return nil, err
+		}
+		if _, err := git.RunGitCommand(opts.RepoDir, "add", "--", file); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(unresolved) > 0 {
+		return nil, fmt.Errorf("conflicts in %s must be resolved manually", strings.Join(unresolved, ", "))
+	}
+	return overridden, nil
+}
+
+func indexStage(repoDir string, stage int, file string) []byte {
+	content, err := git.RunGitCommand(repoDir, "show", fmt.Sprintf(":%d:%s", stage, file))
+	if err != nil {
+		return nil
+	}
+	return []byte(content)
+}
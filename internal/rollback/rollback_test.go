@@ -0,0 +1,196 @@
+package rollback
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/speakeasy-api/speakeasy/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func initRepo(t *testing.T) string {
+	t.Helper()
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@test.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@test.com")
+
+	dir := t.TempDir()
+	_, err := git.RunGitCommand(dir, "init", "-b", "main")
+	require.NoError(t, err)
+	return dir
+}
+
+func commitFiles(t *testing.T, dir, message string, files map[string]string) string {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	_, err := git.RunGitCommand(dir, "add", "-A")
+	require.NoError(t, err)
+	_, err = git.RunGitCommand(dir, "commit", "-m", message)
+	require.NoError(t, err)
+
+	hash, err := git.RunGitCommand(dir, "rev-parse", "HEAD")
+	require.NoError(t, err)
+	return hash[:len(hash)-1]
+}
+
+func genLock(version, digest string) string {
+	return "management:\n  releaseVersion: " + version + "\n  docChecksum: " + digest + "\n"
+}
+
+func TestFindGenerationCommit(t *testing.T) {
+	dir := initRepo(t)
+	genLockPath := filepath.Join(".speakeasy", "gen.lock")
+
+	commitFiles(t, dir, "v1.0.0", map[string]string{genLockPath: genLock("1.0.0", "a")})
+	release := commitFiles(t, dir, "v1.1.0", map[string]string{genLockPath: genLock("1.1.0", "b")})
+	commitFiles(t, dir, "checksum only", map[string]string{genLockPath: genLock("1.1.0", "c")})
+	commitFiles(t, dir, "v1.2.0", map[string]string{genLockPath: genLock("1.2.0", "d")})
+
+	commit, err := FindGenerationCommit(dir, genLockPath, "1.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, release, commit.Hash)
+	assert.Equal(t, "1.0.0", commit.PreviousVersion)
+
+	first, err := FindGenerationCommit(dir, genLockPath, "1.0.0")
+	require.NoError(t, err)
+	assert.Empty(t, first.PreviousVersion)
+
+	_, err = FindGenerationCommit(dir, genLockPath, "9.9.9")
+	assert.Error(t, err)
+}
+
+func TestRevert(t *testing.T) {
+	dir := initRepo(t)
+	genLockPath := filepath.Join(".speakeasy", "gen.lock")
+	workflowLockPath := filepath.Join(".speakeasy", "workflow.lock")
+
+	commitFiles(t, dir, "v1.0.0", map[string]string{
+		genLockPath:      genLock("1.0.0", "a"),
+		workflowLockPath: "targets:\n  go:\n    sourceRevisionDigest: sha256:a\n",
+		"sdk.go":         "package sdk // v1\n",
+	})
+	release := commitFiles(t, dir, "v1.1.0", map[string]string{
+		genLockPath:      genLock("1.1.0", "b"),
+		workflowLockPath: "targets:\n  go:\n    sourceRevisionDigest: sha256:b\n",
+		"sdk.go":         "package sdk // v2\n",
+	})
+	// A later commit touching the lockfile forces a structural merge.
+	commitFiles(t, dir, "add python", map[string]string{
+		workflowLockPath: "targets:\n  go:\n    sourceRevisionDigest: sha256:b\n  python:\n    sourceRevisionDigest: sha256:b\n",
+	})
+
+	result, err := Revert(Options{
+		RepoDir:          dir,
+		Branch:           "rollback",
+		Commit:           release,
+		GenLockPath:      genLockPath,
+		WorkflowLockPath: workflowLockPath,
+		Message:          "rollback v1.1.0",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "rollback", result.Branch)
+	assert.Empty(t, result.Overridden)
+
+	sdk, err := os.ReadFile(filepath.Join(dir, "sdk.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package sdk // v1\n", string(sdk))
+
+	lock, err := os.ReadFile(filepath.Join(dir, genLockPath))
+	require.NoError(t, err)
+	assert.Equal(t, genLock("1.0.0", "a"), string(lock))
+
+	workflowLock, err := os.ReadFile(filepath.Join(dir, workflowLockPath))
+	require.NoError(t, err)
+	assert.Equal(t, "targets:\n  go:\n    sourceRevisionDigest: sha256:a\n  python:\n    sourceRevisionDigest: sha256:b\n", string(workflowLock))
+}
+
+func TestRevert_DirtyWorktree(t *testing.T) {
+	dir := initRepo(t)
+	commitFiles(t, dir, "init", map[string]string{"sdk.go": "package sdk\n"})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sdk.go"), []byte("package changed\n"), 0o644))
+
+	_, err := Revert(Options{RepoDir: dir, Branch: "rollback", Commit: "HEAD", Message: "rollback"})
+	assert.Error(t, err)
+}
+
+func TestRevert_ConflictRestoresBranch(t *testing.T) {
+	dir := initRepo(t)
+	commitFiles(t, dir, "v1.0.0", map[string]string{"sdk.go": "package sdk // v1\n"})
+	release := commitFiles(t, dir, "v1.1.0", map[string]string{"sdk.go": "package sdk // v2\n"})
+	commitFiles(t, dir, "custom code", map[string]string{"sdk.go": "package sdk // v2 custom\n"})
+
+	_, err := Revert(Options{RepoDir: dir, Branch: "rollback", Commit: release, Message: "rollback"})
+	assert.ErrorContains(t, err, "sdk.go must be resolved manually")
+
+	branch, err := git.RunGitCommand(dir, "symbolic-ref", "--short", "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, "main", strings.TrimSpace(branch))
+
+	branches, err := git.RunGitCommand(dir, "branch", "--list", "rollback")
+	require.NoError(t, err)
+	assert.Empty(t, strings.TrimSpace(branches))
+
+	sdk, err := os.ReadFile(filepath.Join(dir, "sdk.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package sdk // v2 custom\n", string(sdk))
+}
+
+func TestRevert_RefusesOtherTargets(t *testing.T) {
+	dir := initRepo(t)
+	workflowLockPath := filepath.Join(".speakeasy", "workflow.lock")
+	commitFiles(t, dir, "init", map[string]string{
+		workflowLockPath:   "targets: {}\n",
+		"go/sdk.go":        "package sdk // v1\n",
+		"python/sdk.py":    "# v1\n",
+		"python/go/sdk.go": "package nested // v1\n",
+	})
+
+	opts := Options{
+		RepoDir:          dir,
+		Branch:           "rollback",
+		WorkflowLockPath: workflowLockPath,
+		Message:          "rollback",
+		TargetDir:        "python",
+		OtherTargetDirs:  []string{"go", "python/go"},
+	}
+
+	opts.Commit = commitFiles(t, dir, "squashed", map[string]string{
+		workflowLockPath: "targets: {go: {}, python: {}}\n",
+		"go/sdk.go":      "package sdk // v2\n",
+		"python/sdk.py":  "# v2\n",
+	})
+	_, err := Revert(opts)
+	assert.ErrorContains(t, err, "go/sdk.go")
+
+	opts.Commit = commitFiles(t, dir, "nested", map[string]string{
+		"python/sdk.py":    "# v3\n",
+		"python/go/sdk.go": "package nested // v2\n",
+	})
+	_, err = Revert(opts)
+	assert.ErrorContains(t, err, "python/go/sdk.go")
+
+	opts.Commit = commitFiles(t, dir, "python only", map[string]string{
+		workflowLockPath: "targets: {go: {}, python: {v: 4}}\n",
+		"python/sdk.py":  "# v4\n",
+	})
+	_, err = Revert(opts)
+	require.NoError(t, err)
+
+	sdk, err := os.ReadFile(filepath.Join(dir, "python", "sdk.py"))
+	require.NoError(t, err)
+	assert.Equal(t, "# v3\n", string(sdk))
+}
+
+func TestYankInstructions(t *testing.T) {
+	assert.Equal(t, []string{`npm deprecate @acme/sdk@1.1.0 "1.1.0 has been rolled back"`}, YankInstructions("typescript", "@acme/sdk", "1.1.0", "v1.1.0", ""))
+	assert.Equal(t, []string{"gem yank acme -v 1.1.0"}, YankInstructions("ruby", "acme", "1.1.0", "v1.1.0", "broken"))
+	assert.Contains(t, YankInstructions("php", "acme/sdk", "1.1.0", "sdks/php/v1.1.0", "")[0], "sdks/php/v1.1.0")
+}
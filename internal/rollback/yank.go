@@ -0,0 +1,40 @@
+package rollback
+
+import (
+	"fmt"
+	"strings"
+)
+
+// YankInstructions returns the commands (or, for registries without a CLI,
+// the manual steps) that deprecate or yank a published package version. Tag
+// is the git tag of the release.
+func YankInstructions(lang, packageName, version, tag, reason string) []string {
+	if reason == "" {
+		reason = fmt.Sprintf("%s has been rolled back", version)
+	}
+
+	switch lang {
+	case "typescript", "mcp-typescript":
+		return []string{fmt.Sprintf("npm deprecate %s@%s %q", packageName, version, reason)}
+	case "python":
+		return []string{fmt.Sprintf("Yank the release at https://pypi.org/manage/project/%s/release/%s/ (reason: %s)", packageName, version, reason)}
+	case "go":
+		return []string{
+			fmt.Sprintf("go mod edit -retract='v%s // %s'", version, reason),
+			"Release a new version containing the retract directive so that the go command stops selecting it",
+		}
+	case "java":
+		groupID, artifactID, _ := strings.Cut(packageName, ":")
+		return []string{fmt.Sprintf("Maven Central does not allow removing %s:%s:%s; publish a fixed version and note the rollback in the release notes", groupID, artifactID, version)}
+	case "csharp":
+		return []string{fmt.Sprintf("dotnet nuget delete %s %s --source https://api.nuget.org/v3/index.json --non-interactive", packageName, version)}
+	case "ruby":
+		return []string{fmt.Sprintf("gem yank %s -v %s", packageName, version)}
+	case "php":
+		return []string{fmt.Sprintf("git push --delete origin %s", tag), "Packagist removes the version on its next update of " + packageName}
+	case "terraform":
+		return []string{fmt.Sprintf("gh release delete %s --cleanup-tag", tag), "The Terraform Registry hides versions whose release and tag are removed"}
+	default:
+		return []string{fmt.Sprintf("No yank instructions are available for %s; remove %s@%s from its registry manually", lang, packageName, version)}
+	}
+}
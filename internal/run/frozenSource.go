@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/speakeasy-api/sdk-gen-config/workflow"
+	"github.com/speakeasy-api/speakeasy/internal/offline"
 	"github.com/speakeasy-api/speakeasy/internal/workflowTracking"
 	"github.com/speakeasy-api/speakeasy/registry"
 )
@@ -36,7 +37,7 @@ func (f FrozenSource) Do(ctx context.Context, _ string) (string, error) {
 	if !ok {
 		return "", fmt.Errorf("workflow lockfile lacks a reference to source %s: can't use this on first run", f.sourceID)
 	}
-	if !registry.IsRegistryEnabled(ctx) {
+	if !offline.Enabled() && !registry.IsRegistryEnabled(ctx) {
 		return "", fmt.Errorf("registry is not enabled for this workspace")
 	}
 	if lockSource.SourceBlobDigest == "" || lockSource.SourceRevisionDigest == "" || lockSource.SourceNamespace == "" {
@@ -8,6 +8,7 @@ import (
 	"os"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
@@ -220,12 +221,45 @@ func (w *Workflow) RunInner(ctx context.Context) error {
 		w.Cleanup()
 	}
 
-	if err := workflow.SaveLockfile(w.ProjectDir, w.lockfile); err != nil {
+	if err := w.saveLockfile(); err != nil {
 		return err
 	}
 	return nil
 }
 
+// lockfileMu serializes workflow.lock updates by workflows running
+// concurrently in the same process, as in the studio.
+var lockfileMu sync.Mutex
+
+// saveLockfile writes workflow.lock, keeping the entries of sources and
+// targets this run didn't touch as they are on disk so that concurrent runs
+// don't revert each other's entries.
+func (w *Workflow) saveLockfile() error {
+	lockfileMu.Lock()
+	defer lockfileMu.Unlock()
+
+	if current, err := workflow.LoadLockfile(w.ProjectDir); err == nil && current != nil {
+		if w.lockfile.Sources == nil {
+			w.lockfile.Sources = make(map[string]workflow.SourceLock)
+		}
+		if w.lockfile.Targets == nil {
+			w.lockfile.Targets = make(map[string]workflow.TargetLock)
+		}
+		for sourceID, sourceLock := range current.Sources {
+			if _, ok := w.SourceResults[sourceID]; !ok {
+				w.lockfile.Sources[sourceID] = sourceLock
+			}
+		}
+		for targetID, targetLock := range current.Targets {
+			if _, ok := w.TargetResults[targetID]; !ok {
+				w.lockfile.Targets[targetID] = targetLock
+			}
+		}
+	}
+
+	return workflow.SaveLockfile(w.ProjectDir, w.lockfile)
+}
+
 func (w *Workflow) Cleanup() {
 	_ = os.RemoveAll(workflow.GetTempDir())
 }
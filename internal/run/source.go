@@ -188,6 +188,8 @@ func (w *Workflow) runSourceInner(ctx context.Context, parentStep *workflowTrack
 	if err != nil {
 		return "", nil, err
 	}
+	// The document known to the lint baseline, before any reformatting
+	documentPath := outputLocation
 
 	frozenSource := false
 
@@ -217,6 +219,7 @@ func (w *Workflow) runSourceInner(ctx context.Context, parentStep *workflowTrack
 			// In registry bundles specifically we cannot know the exact file output location before pulling the bundle down
 			if source.Inputs[0].IsSpeakeasyRegistry() {
 				outputLocation = currentDocument
+				documentPath = currentDocument
 			}
 			// If we aren't going to touch the document because it's a single input document with no overlay, then check if we should reformat it
 			// Primarily this is to improve readability of single-line documents in the Studio and Linting output
@@ -277,7 +280,7 @@ func (w *Workflow) runSourceInner(ctx context.Context, parentStep *workflowTrack
 	var lintingErr error
 	if !w.SkipLinting {
 		_ = w.OnSourceResult(sourceRes, SourceStepLint)
-		sourceRes.LintResult, err = w.validateDocument(ctx, rootStep, sourceID, currentDocument, rulesetToUse, w.ProjectDir, targetLanguage)
+		sourceRes.LintResult, err = w.validateDocument(ctx, rootStep, sourceID, currentDocument, documentPath, rulesetToUse, w.ProjectDir, targetLanguage)
 		if err != nil {
 			lintingErr = &LintingError{Err: err, Document: currentDocument}
 		}
@@ -338,7 +341,10 @@ func (w *Workflow) runSourceInner(ctx context.Context, parentStep *workflowTrack
 	return currentDocument, sourceRes, nil
 }
 
-func (w *Workflow) validateDocument(ctx context.Context, parentStep *workflowTracking.WorkflowStep, source, schemaPath, defaultRuleset, projectDir string, target string) (*validation.ValidationResult, error) {
+// validateDocument lints the document at schemaPath. Its known findings are
+// those recorded in the project's lint baseline for documentPath, the
+// document as the user lints it, where schemaPath may be a temporary copy.
+func (w *Workflow) validateDocument(ctx context.Context, parentStep *workflowTracking.WorkflowStep, source, schemaPath, documentPath, defaultRuleset, projectDir string, target string) (*validation.ValidationResult, error) {
 	step := parentStep.NewSubstep("Validating Document")
 
 	alreadyValidated := func() bool {
@@ -357,7 +363,14 @@ func (w *Workflow) validateDocument(ctx context.Context, parentStep *workflowTra
 		MaxWarns:  1000,
 	}
 
-	res, err := validation.ValidateOpenAPI(ctx, source, schemaPath, "", "", limits, defaultRuleset, projectDir, w.FromQuickstart, w.SkipGenerateLintReport, target)
+	// Known findings recorded with lint openapi --update-baseline don't fail the run
+	baseline, err := validation.LoadProjectBaseline(projectDir)
+	if err != nil {
+		step.FailWorkflow()
+		return nil, err
+	}
+
+	res, err := validation.ValidateOpenAPI(ctx, source, schemaPath, "", "", limits, defaultRuleset, projectDir, w.FromQuickstart, w.SkipGenerateLintReport, target, baseline.Document(validation.DocumentKey(projectDir, documentPath)))
 
 	func() {
 		w.sourceMu.Lock()
@@ -104,7 +104,7 @@ func (w *Workflow) runTarget(ctx context.Context, target string) (*SourceResult,
 			}
 		}
 	} else {
-		res, err := w.validateDocument(ctx, rootStep, t.Source, sourcePath, "speakeasy-generation", w.ProjectDir, targetLanguage)
+		res, err := w.validateDocument(ctx, rootStep, t.Source, sourcePath, sourcePath, "speakeasy-generation", w.ProjectDir, targetLanguage)
 		if err != nil {
 			return sourceRes, nil, err
 		}
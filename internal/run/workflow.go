@@ -13,6 +13,7 @@ import (
 	"github.com/speakeasy-api/sdk-gen-config/workflow"
 	"github.com/speakeasy-api/speakeasy-core/events"
 	"github.com/speakeasy-api/speakeasy/internal/log"
+	"github.com/speakeasy-api/speakeasy/internal/offline"
 	"github.com/speakeasy-api/speakeasy/internal/sdkgen"
 	"github.com/speakeasy-api/speakeasy/internal/utils"
 	"github.com/speakeasy-api/speakeasy/internal/workflowTracking"
@@ -403,6 +404,12 @@ func (w *Workflow) Clone(ctx context.Context, opts ...Opt) (*Workflow, error) {
 }
 
 func Migrate(ctx context.Context, wf *workflow.Workflow) {
+	// Offline, whether the registry is enabled isn't known, so leave the
+	// workflow as it is.
+	if offline.Enabled() {
+		return
+	}
+
 	if registry.IsRegistryEnabled(ctx) {
 		*wf = wf.Migrate()
 	} else {
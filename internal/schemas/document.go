@@ -8,6 +8,7 @@ import (
 	"github.com/speakeasy-api/sdk-gen-config/workflow"
 	coreopenapi "github.com/speakeasy-api/speakeasy-core/openapi"
 	"github.com/speakeasy-api/speakeasy/internal/download"
+	"github.com/speakeasy-api/speakeasy/internal/offline"
 	"github.com/speakeasy-api/speakeasy/internal/utils"
 	"github.com/speakeasy-api/speakeasy/internal/workflowTracking"
 	"github.com/speakeasy-api/speakeasy/registry"
@@ -25,7 +26,7 @@ func LoadDocument(ctx context.Context, schemaLocation string) ([]byte, *openapi.
 func ResolveDocument(ctx context.Context, d workflow.Document, outputLocation *string, step *workflowTracking.WorkflowStep) (string, error) {
 	if d.IsSpeakeasyRegistry() {
 		step.NewSubstep("Downloading registry bundle")
-		if !registry.IsRegistryEnabled(ctx) {
+		if !offline.Enabled() && !registry.IsRegistryEnabled(ctx) {
 			return "", fmt.Errorf("schema registry is not enabled for this workspace")
 		}
 
@@ -21,6 +21,7 @@ import (
 	"github.com/speakeasy-api/speakeasy/internal/env"
 	"github.com/speakeasy-api/speakeasy/internal/fs"
 	"github.com/speakeasy-api/speakeasy/internal/git"
+	"github.com/speakeasy-api/speakeasy/internal/offline"
 	"github.com/speakeasy-api/speakeasy/internal/patches"
 	"github.com/speakeasy-api/speakeasy/internal/workflowTracking"
 
@@ -216,6 +217,10 @@ func Generate(ctx context.Context, opts GenerateOptions) (*GenerationAccess, err
 		),
 	)
 
+	// Set once protected regions may have been stashed, so they are restored
+	// whether or not generation succeeds.
+	restoreRegions := false
+
 	// Try to open a git repository for the Round-Trip Engineering (3-way merge) feature.
 	// If a git repository exists, inject Git and FileSystem adapters for the persistentEdits feature.
 	repo, repoErr := git.NewLocalRepository(opts.OutDir)
@@ -247,6 +252,7 @@ func Generate(ctx context.Context, opts GenerateOptions) (*GenerationAccess, err
 		if err := patches.PrepareForGeneration(opts.OutDir, opts.AutoYes, promptFunc, logger.Warnf); err != nil {
 			logger.Warnf("Error preparing for generation: %v", err)
 		}
+		restoreRegions = true
 	}
 
 	g, err := generate.New(generatorOpts...)
@@ -258,7 +264,7 @@ func Generate(ctx context.Context, opts GenerateOptions) (*GenerationAccess, err
 		}, err
 	}
 
-	err = events.Telemetry(ctx, shared.InteractionTypeTargetGenerate, func(ctx context.Context, event *shared.CliEvent) error {
+	err = offline.Telemetry(ctx, shared.InteractionTypeTargetGenerate, func(ctx context.Context, event *shared.CliEvent) error {
 		event.GenerateTargetName = &opts.TargetName
 
 		var errs []error
@@ -293,6 +299,9 @@ func Generate(ctx context.Context, opts GenerateOptions) (*GenerationAccess, err
 
 		return nil
 	})
+	if restoreRegions {
+		restoreProtectedRegions(logger, opts.OutDir)
+	}
 	if err != nil {
 		return &GenerationAccess{
 			AccessAllowed: generationAccess,
@@ -410,3 +419,16 @@ func renderConflictsError(logger log.Logger, conflictErr *merge.ConflictsError)
 		logger.Printf("::error file=%s::Merge conflict detected - manual resolution required", file)
 	}
 }
+
+// restoreProtectedRegions re-injects the protected regions stashed before
+// generation, warning about any that no longer have a place in the output.
+func restoreProtectedRegions(logger log.Logger, outDir string) {
+	unrestored, err := patches.RestoreProtectedRegions(outDir)
+	if err != nil {
+		logger.Warnf("Failed to restore protected regions: %v", err)
+		return
+	}
+	for _, region := range unrestored {
+		logger.Warnf("Could not restore protected region %q in %s; its content is kept in .speakeasy/%s", region.ID, region.Path, patches.ProtectedRegionsStashFile)
+	}
+}
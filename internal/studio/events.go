@@ -0,0 +1,91 @@
+package studio
+
+import (
+	"net/http"
+	"sync"
+)
+
+// runEvent is a server-sent event of a run, along with the source and target
+// of the pipeline that ran.
+type runEvent struct {
+	sourceID string
+	targetID string
+	data     []byte
+}
+
+// eventFilter selects the runs a subscriber is interested in. Empty fields
+// match everything.
+type eventFilter struct {
+	sourceID string
+	targetID string
+}
+
+func (f eventFilter) matches(event runEvent) bool {
+	if f.sourceID != "" && f.sourceID != event.sourceID {
+		return false
+	}
+	// Runs not restricted to a single target may generate the selected one
+	return f.targetID == "" || event.targetID == "" || f.targetID == event.targetID
+}
+
+// runEvents fans the server-sent events of every run out to the clients
+// subscribed on /events, so clients see runs started by each other.
+type runEvents struct {
+	mu          sync.Mutex
+	subscribers map[chan []byte]eventFilter
+}
+
+func (e *runEvents) subscribe(filter eventFilter) chan []byte {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.subscribers == nil {
+		e.subscribers = make(map[chan []byte]eventFilter)
+	}
+	ch := make(chan []byte, 16)
+	e.subscribers[ch] = filter
+	return ch
+}
+
+func (e *runEvents) unsubscribe(ch chan []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	delete(e.subscribers, ch)
+}
+
+// publish sends an event to every matching subscriber, dropping it for
+// subscribers too slow to keep up rather than blocking the run.
+func (e *runEvents) publish(event runEvent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for ch, filter := range e.subscribers {
+		if !filter.matches(event) {
+			continue
+		}
+		select {
+		case ch <- event.data:
+		default:
+		}
+	}
+}
+
+// broadcastWriter streams a run to the requesting client while publishing
+// each event to the other subscribers.
+type broadcastWriter struct {
+	http.ResponseWriter
+	http.Flusher
+	events   *runEvents
+	sourceID string
+	targetID string
+}
+
+func (w *broadcastWriter) Write(p []byte) (int, error) {
+	w.events.publish(runEvent{
+		sourceID: w.sourceID,
+		targetID: w.targetID,
+		data:     append([]byte(nil), p...),
+	})
+	return w.ResponseWriter.Write(p)
+}
@@ -3,6 +3,7 @@ package studio
 import (
 	"context"
 	"crypto/rand"
+	"crypto/subtle"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -12,6 +13,9 @@ import (
 	"os"
 	"os/signal"
 	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -61,22 +65,6 @@ func LaunchStudio(ctx context.Context, workflow *run.Workflow) error {
 		return fmt.Errorf("error creating studio handlers: %w", err)
 	}
 
-	mux := http.NewServeMux()
-	mux.HandleFunc("/", handler(handlers.root))
-	mux.HandleFunc("/health", handler(handlers.health))
-	mux.HandleFunc("/run", func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodPost:
-			handler(handlers.reRun)(w, r)
-		case http.MethodGet:
-			handler(handlers.getLastRunResult)(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	})
-	mux.HandleFunc("/overlays/compare", handler(handlers.compareOverlay))
-	mux.HandleFunc("/suggest/method-names", handler(handlers.suggestMethodNames))
-
 	port, err := searchForAvailablePort()
 	if err != nil {
 		return err
@@ -84,7 +72,7 @@ func LaunchStudio(ctx context.Context, workflow *run.Workflow) error {
 
 	server := &http.Server{
 		Addr:    fmt.Sprintf(":%d", port),
-		Handler: corsMiddleware(authMiddleware(secret, mux)),
+		Handler: corsMiddleware(authMiddleware(secret, newMux(handlers))),
 	}
 
 	serverURL := auth.GetWorkspaceBaseURL(ctx)
@@ -103,7 +91,7 @@ func LaunchStudio(ctx context.Context, workflow *run.Workflow) error {
 	// After 1 minute, if the health check hasn't been seen then kill the server
 	go func() {
 		time.Sleep(1 * time.Minute)
-		if !handlers.healthCheckSeen {
+		if !handlers.healthCheckSeen.Load() {
 			log.From(ctx).Warnf("Health check not seen, shutting down server")
 			err := server.Shutdown(context.Background())
 			if err != nil {
@@ -115,6 +103,102 @@ func LaunchStudio(ctx context.Context, workflow *run.Workflow) error {
 	return startServer(ctx, server, workflow)
 }
 
+// ServeOptions configures a headless studio server.
+type ServeOptions struct {
+	// Host is the interface to listen on, localhost if empty.
+	Host string
+	Port int
+	// Token authenticates clients. The saved studio secret is used if empty.
+	Token string
+}
+
+// ServeHeadless serves the studio API without opening the web UI, on a fixed
+// port and for as long as the process runs, so editor extensions and other
+// local tools can drive the workflow. Any number of clients may connect and
+// select any source or target of the workflow; runs of targets with different
+// output directories proceed concurrently and their results are broadcast on
+// /events.
+func ServeHeadless(ctx context.Context, workflow *run.Workflow, opts ServeOptions) error {
+	if workflow == nil {
+		return errors.New("unable to serve studio without a workflow")
+	}
+
+	token := opts.Token
+	if token == "" {
+		var err error
+		if token, err = getOrCreateSecret(); err != nil {
+			return fmt.Errorf("error creating studio secret key: %w", err)
+		}
+	}
+
+	handlers, err := NewStudioHandlers(ctx, workflow)
+	if err != nil {
+		return fmt.Errorf("error creating studio handlers: %w", err)
+	}
+	handlers.Headless = true
+
+	host := opts.Host
+	if host == "" {
+		host = "localhost"
+	}
+	addr := net.JoinHostPort(host, strconv.Itoa(opts.Port))
+
+	server := &http.Server{
+		Addr:    addr,
+		Handler: corsMiddleware(authMiddleware(token, newMux(handlers))),
+	}
+	handlers.Server = server
+
+	fmt.Printf("Studio API listening on http://%s\n", addr)
+	fmt.Printf("API description: http://%s/openapi.yaml\n", addr)
+	if opts.Token == "" {
+		fmt.Printf("Authenticate with the header X-Secret-Key: %s\n", token)
+	}
+
+	return startServer(ctx, server, workflow)
+}
+
+func newMux(handlers *StudioHandlers) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", handler(handlers.root))
+	mux.HandleFunc("/openapi.yaml", handler(handlers.openAPIDescription))
+	mux.HandleFunc("/health", handler(handlers.health))
+	mux.HandleFunc("/events", handler(handlers.subscribe))
+	mux.HandleFunc("/run", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			handler(handlers.reRun)(w, r)
+		case http.MethodGet:
+			handler(handlers.getLastRunResult)(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc("/sources/{sourceID}/transformations", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			handler(handlers.updateTransformations)(w, r)
+		case http.MethodGet:
+			handler(handlers.getTransformations)(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc("/targets/{targetID}/config", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			handler(handlers.updateTargetConfig)(w, r)
+		case http.MethodGet:
+			handler(handlers.getTargetConfig)(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc("/overlays/compare", handler(handlers.compareOverlay))
+	mux.HandleFunc("/suggest/method-names", handler(handlers.suggestMethodNames))
+	return mux
+}
+
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
@@ -135,7 +219,7 @@ func corsMiddleware(next http.Handler) http.Handler {
 
 func authMiddleware(secret string, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.Header.Get("X-Secret-Key") != secret && r.URL.Path != "/" {
+		if r.URL.Path != "/" && r.URL.Path != "/openapi.yaml" && !isAuthorized(r, secret) {
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
@@ -144,6 +228,14 @@ func authMiddleware(secret string, next http.Handler) http.Handler {
 	})
 }
 
+func isAuthorized(r *http.Request, secret string) bool {
+	token := r.Header.Get("X-Secret-Key")
+	if token == "" {
+		token, _ = strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1
+}
+
 func handler(h func(context.Context, http.ResponseWriter, *http.Request) error) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
@@ -245,9 +337,8 @@ func respondJSONError(ctx context.Context, w http.ResponseWriter, err error) {
 	}
 }
 
-var counter int
+var counter atomic.Int64
 
 func generateRequestID() string {
-	counter++
-	return fmt.Sprintf("%03d", counter)
+	return fmt.Sprintf("%03d", counter.Add(1))
 }
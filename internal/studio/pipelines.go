@@ -0,0 +1,199 @@
+package studio
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/speakeasy-api/sdk-gen-config/workflow"
+	"github.com/speakeasy-api/speakeasy-core/errors"
+	"github.com/speakeasy-api/speakeasy/internal/run"
+)
+
+// defaultPipeline is the key of the pipeline the studio was launched with,
+// used by requests that don't select a source or target.
+const defaultPipeline = ""
+
+// pipeline is a part of the workflow that the studio runs on its own: a
+// single target along with its source, or a source without any targets. The
+// pipeline the studio was launched with may also cover every target of its
+// source.
+type pipeline struct {
+	sourceID string
+	// targetID is empty when the pipeline isn't restricted to a single target.
+	targetID string
+
+	runQueued flag
+
+	// runMu serializes the runs of the pipeline.
+	runMu sync.Mutex
+	// sourceDone releases the lock on the source of the run in progress once
+	// it is past its source step. It is set between queueRun and its release.
+	sourceDone func()
+
+	// mu guards runner, which runs replace while other clients may be
+	// reading it.
+	mu     sync.RWMutex
+	runner *run.Workflow
+}
+
+// state returns the workflow runner of the last run of the pipeline.
+func (p *pipeline) state() *run.Workflow {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.runner
+}
+
+func (p *pipeline) setRunner(runner *run.Workflow) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.runner = runner
+}
+
+func pipelineKey(sourceID, targetID string) string {
+	if targetID != "" {
+		return "target:" + targetID
+	}
+	return "source:" + sourceID
+}
+
+// selection reads the source and target a request selects from its query
+// string, resolving the source of a selected target.
+func (h *StudioHandlers) selection(r *http.Request) (string, string, error) {
+	query := r.URL.Query()
+	sourceID, targetID := query.Get("source"), query.Get("target")
+	if targetID == "" {
+		return sourceID, "", nil
+	}
+
+	target, ok := h.workflowFile().Targets[targetID]
+	if !ok {
+		return "", "", errors.ErrNotFound.Wrap(fmt.Errorf("target %s not found", targetID))
+	}
+	if sourceID != "" && sourceID != target.Source {
+		return "", "", errors.ErrBadRequest.Wrap(fmt.Errorf("target %s is not generated from source %s", targetID, sourceID))
+	}
+
+	return target.Source, targetID, nil
+}
+
+// selectPipeline returns the pipeline selected by the source and target
+// query parameters of a request.
+func (h *StudioHandlers) selectPipeline(r *http.Request) (*pipeline, error) {
+	sourceID, targetID, err := h.selection(r)
+	if err != nil {
+		return nil, err
+	}
+	return h.pipeline(sourceID, targetID)
+}
+
+// pipeline returns the pipeline for a source or target, creating it on first
+// use. Selecting nothing, or only the source the studio was launched with,
+// returns the pipeline the studio was launched with.
+func (h *StudioHandlers) pipeline(sourceID, targetID string) (*pipeline, error) {
+	key := pipelineKey(sourceID, targetID)
+	if targetID == "" && (sourceID == "" || sourceID == h.SourceID) {
+		key = defaultPipeline
+	}
+
+	h.stateMu.Lock()
+	defer h.stateMu.Unlock()
+
+	if p, ok := h.pipelines[key]; ok {
+		return p, nil
+	}
+	if key == defaultPipeline {
+		return nil, errors.ErrBadRequest.Wrap(fmt.Errorf("the workflow has several sources: select one with the source or target query parameter"))
+	}
+	if _, ok := h.latestRunner.GetWorkflowFile().Sources[sourceID]; !ok {
+		return nil, errors.ErrNotFound.Wrap(fmt.Errorf("source %s not found", sourceID))
+	}
+
+	// A workflow runs either a source or a target, which runs its source too
+	opts := []run.Opt{run.WithSource(""), run.WithTarget(targetID)}
+	if targetID == "" {
+		opts = []run.Opt{run.WithTarget(""), run.WithSource(sourceID)}
+	}
+	runner, err := h.WorkflowRunner.Clone(h.Ctx, append(opts, run.WithSkipCleanup())...)
+	if err != nil {
+		return nil, fmt.Errorf("error cloning workflow runner: %w", err)
+	}
+
+	p := &pipeline{sourceID: sourceID, targetID: targetID, runner: runner}
+	h.pipelines[key] = p
+	return p, nil
+}
+
+// sourceLock returns the lock serializing the source steps of a source.
+// Targets of the same source share its output document, so it is held while
+// the document is written and released before the targets are generated.
+func (h *StudioHandlers) sourceLock(sourceID string) *sync.Mutex {
+	h.stateMu.Lock()
+	defer h.stateMu.Unlock()
+
+	lock, ok := h.sourceLocks[sourceID]
+	if !ok {
+		lock = &sync.Mutex{}
+		h.sourceLocks[sourceID] = lock
+	}
+	return lock
+}
+
+// outputLocks returns the locks serializing generation into the output
+// directories of the targets a pipeline runs, sorted by directory so they are
+// always acquired in the same order. Targets generating into different
+// directories proceed concurrently, whichever their source.
+func (h *StudioHandlers) outputLocks(p *pipeline) []*sync.Mutex {
+	allTargets := p.targetID == "" && p.state().Target == "all"
+
+	var dirs []string
+	for targetID, target := range h.workflowFile().Targets {
+		if targetID != p.targetID && (!allTargets || target.Source != p.sourceID) {
+			continue
+		}
+		dir := "."
+		if target.Output != nil {
+			dir = filepath.Clean(*target.Output)
+		}
+		dirs = append(dirs, dir)
+	}
+	slices.Sort(dirs)
+	dirs = slices.Compact(dirs)
+
+	h.stateMu.Lock()
+	defer h.stateMu.Unlock()
+
+	locks := make([]*sync.Mutex, 0, len(dirs))
+	for _, dir := range dirs {
+		lock, ok := h.outputDirLocks[dir]
+		if !ok {
+			lock = &sync.Mutex{}
+			h.outputDirLocks[dir] = lock
+		}
+		locks = append(locks, lock)
+	}
+	return locks
+}
+
+// overlayPath returns the studio modifications overlay of a source, if any.
+func (h *StudioHandlers) overlayPath(sourceID string) string {
+	h.stateMu.RLock()
+	defer h.stateMu.RUnlock()
+	return h.overlayPaths[sourceID]
+}
+
+func (h *StudioHandlers) setOverlayPath(sourceID, overlayPath string) {
+	h.stateMu.Lock()
+	defer h.stateMu.Unlock()
+	h.overlayPaths[sourceID] = overlayPath
+}
+
+// workflowFile returns the workflow as of the most recent run of any
+// pipeline.
+func (h *StudioHandlers) workflowFile() *workflow.Workflow {
+	h.stateMu.RLock()
+	defer h.stateMu.RUnlock()
+	return h.latestRunner.GetWorkflowFile()
+}
@@ -11,9 +11,9 @@ import (
 	"github.com/speakeasy-api/speakeasy/internal/studio/sdk/models/components"
 )
 
-func (h *StudioHandlers) enableGenerationProgressUpdates(w http.ResponseWriter, flusher http.Flusher, genSteps, fileStatus bool) {
-	workflowConfig := h.WorkflowRunner.GetWorkflowFile()
-	workflow, _ := convertWorkflowToComponentsWorkflow(*workflowConfig, h.WorkflowRunner.ProjectDir)
+func enableGenerationProgressUpdates(runner *run.Workflow, sourceID string, w http.ResponseWriter, flusher http.Flusher, genSteps, fileStatus bool) {
+	workflowConfig := runner.GetWorkflowFile()
+	workflow, _ := convertWorkflowToComponentsWorkflow(*workflowConfig, runner.ProjectDir)
 
 	onProgressUpdate := func(progressUpdate generate.ProgressUpdate) {
 		targetID := progressUpdate.TargetID
@@ -22,8 +22,8 @@ func (h *StudioHandlers) enableGenerationProgressUpdates(w http.ResponseWriter,
 			return // TODO handle error
 		}
 
-		if h.WorkflowRunner.Debug {
-			h.logGenerationProgress(progressUpdate)
+		if runner.Debug {
+			logGenerationProgress(runner, progressUpdate)
 		}
 
 		var step run.SourceStepID
@@ -52,14 +52,14 @@ func (h *StudioHandlers) enableGenerationProgressUpdates(w http.ResponseWriter,
 				Content: progressUpdate.File.Content.String(),
 			}
 
-			targetDirectory := h.WorkflowRunner.ProjectDir
+			targetDirectory := runner.ProjectDir
 			if targetConfig.Output != nil {
 				targetDirectory = *targetConfig.Output
 			}
 
 			targetResults[targetID] = components.TargetRunSummary{
 				TargetID:        targetID,
-				SourceID:        h.SourceID,
+				SourceID:        sourceID,
 				Readme:          &readme,
 				Language:        targetConfig.Target,
 				OutputDirectory: targetDirectory,
@@ -70,28 +70,28 @@ func (h *StudioHandlers) enableGenerationProgressUpdates(w http.ResponseWriter,
 
 		runResponseData := components.RunResponseData{
 			TargetResults:    targetResults,
-			WorkingDirectory: h.WorkflowRunner.ProjectDir,
+			WorkingDirectory: runner.ProjectDir,
 			Workflow:         workflow,
 			Step:             components.Step(step),
 			IsPartial:        true,
-			Took:             h.WorkflowRunner.Duration.Milliseconds(),
+			Took:             runner.Duration.Milliseconds(),
 		}
 		_ = sendRunResponseDataToStream(w, flusher, runResponseData)
 	}
 
-	h.WorkflowRunner.StreamableGeneration = &sdkgen.StreamableGeneration{
+	runner.StreamableGeneration = &sdkgen.StreamableGeneration{
 		OnProgressUpdate: onProgressUpdate,
 		GenSteps:         genSteps,
 		FileStatus:       fileStatus,
 	}
 }
 
-func (h *StudioHandlers) disableGenerationProgressUpdates() {
-	h.WorkflowRunner.StreamableGeneration = nil
+func disableGenerationProgressUpdates(runner *run.Workflow) {
+	runner.StreamableGeneration = nil
 }
 
-func (h *StudioHandlers) logGenerationProgress(progressUpdate generate.ProgressUpdate) {
-	logChan := h.WorkflowRunner.StreamableGeneration.LogListener
+func logGenerationProgress(runner *run.Workflow, progressUpdate generate.ProgressUpdate) {
+	logChan := runner.StreamableGeneration.LogListener
 	if logChan == nil {
 		return
 	}
@@ -3,15 +3,18 @@ package studio
 import (
 	"bytes"
 	"context"
+	_ "embed"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"sync"
+	"sync/atomic"
 
 	"github.com/speakeasy-api/openapi/overlay"
 	"github.com/speakeasy-api/openapi/overlay/loader"
+	sdkGenConfig "github.com/speakeasy-api/sdk-gen-config"
 	"github.com/speakeasy-api/speakeasy-core/errors"
 	"github.com/speakeasy-api/speakeasy-core/events"
 	"github.com/speakeasy-api/speakeasy/internal/env"
@@ -28,51 +31,86 @@ type flag struct {
 	v  bool
 }
 
+//go:embed oas_studio.yaml
+var openAPIDescription []byte
+
 type StudioHandlers struct {
+	// WorkflowRunner is the workflow the studio was launched with.
 	WorkflowRunner *run.Workflow
-	SourceID       string
-	OverlayPath    string
-	Ctx            context.Context //nolint:containedctx // Intentional: maintains request context for handler lifecycle
-	StudioURL      string
-	Server         *http.Server
-
-	runMutex  sync.Mutex
-	runQueued flag
-
-	healthCheckSeen bool
+	// SourceID is the source of the pipeline the studio was launched with,
+	// empty if it spans several sources.
+	SourceID  string
+	Ctx       context.Context //nolint:containedctx // Intentional: maintains request context for handler lifecycle
+	StudioURL string
+	Server    *http.Server
+	// Headless is set when serving the API without the web UI. Clients then
+	// cannot shut the server down.
+	Headless bool
+
+	// stateMu guards the fields below.
+	stateMu        sync.RWMutex
+	pipelines      map[string]*pipeline
+	sourceLocks    map[string]*sync.Mutex
+	outputDirLocks map[string]*sync.Mutex
+	overlayPaths   map[string]string
+	latestRunner   *run.Workflow
+
+	// workflowMu serializes changes to workflow.yaml by concurrent runs.
+	workflowMu sync.Mutex
+	events     runEvents
+
+	healthCheckSeen atomic.Bool
 }
 
 func NewStudioHandlers(ctx context.Context, workflowRunner *run.Workflow) (*StudioHandlers, error) {
-	ret := &StudioHandlers{WorkflowRunner: workflowRunner, Ctx: ctx}
-
-	sourceID, err := findWorkflowSourceIDBasedOnTarget(workflowRunner, workflowRunner.Target)
-	if err != nil {
-		return ret, fmt.Errorf("error finding source: %w", err)
+	ret := &StudioHandlers{
+		WorkflowRunner: workflowRunner,
+		Ctx:            ctx,
+		pipelines:      make(map[string]*pipeline),
+		sourceLocks:    make(map[string]*sync.Mutex),
+		outputDirLocks: make(map[string]*sync.Mutex),
+		overlayPaths:   make(map[string]string),
+		latestRunner:   workflowRunner,
 	}
-	if sourceID == "" {
+
+	workflowFile := workflowRunner.GetWorkflowFile()
+	if len(workflowFile.Sources) == 0 {
 		return ret, errors.New("unable to find source")
 	}
-	ret.SourceID = sourceID
-	sourceConfig := workflowRunner.GetWorkflowFile().Sources[sourceID]
 
-	for _, overlay := range sourceConfig.Overlays {
-		// If there are multiple modifications overlays - we take the last one
-		contents, _ := isStudioModificationsOverlay(overlay)
-		if contents != "" {
-			ret.OverlayPath = overlay.Document.Location.Resolve()
+	for sourceID, sourceConfig := range workflowFile.Sources {
+		for _, overlay := range sourceConfig.Overlays {
+			// If there are multiple modifications overlays - we take the last one
+			contents, _ := isStudioModificationsOverlay(overlay)
+			if contents != "" {
+				ret.overlayPaths[sourceID] = overlay.Document.Location.Resolve()
+			}
 		}
 	}
 
-	ret.runMutex = sync.Mutex{}
-	ret.runQueued = flag{
-		mu: sync.Mutex{},
-		v:  false,
+	// Workflows spanning several sources have no default pipeline; requests
+	// then select a source or target.
+	sourceID, err := findWorkflowSourceIDBasedOnTarget(workflowRunner, workflowRunner.Target)
+	if err != nil || sourceID == "" {
+		return ret, nil
+	}
+	ret.SourceID = sourceID
+
+	targetID := ""
+	if workflowRunner.Target != "all" {
+		targetID = workflowRunner.Target
 	}
+	ret.pipelines[defaultPipeline] = &pipeline{sourceID: sourceID, targetID: targetID, runner: workflowRunner}
 
 	return ret, nil
 }
 
 func (h *StudioHandlers) getLastRunResult(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	p, err := h.selectPipeline(r)
+	if err != nil {
+		return err
+	}
+
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
@@ -81,42 +119,26 @@ func (h *StudioHandlers) getLastRunResult(ctx context.Context, w http.ResponseWr
 		return errors.New("streaming unsupported")
 	}
 
-	err := sendLastRunResultToStream(ctx, w, flusher, h.WorkflowRunner, h.SourceID, h.OverlayPath, run.SourceStepStart)
+	err = sendLastRunResultToStream(ctx, w, flusher, p.state(), p.sourceID, h.overlayPath(p.sourceID), run.SourceStepStart)
 	if err != nil {
 		return fmt.Errorf("error sending last run result to stream: %w", err)
 	}
 
-	h.runMutex.Lock()
-	defer h.runMutex.Unlock()
+	// Wait for the run in progress, if any
+	p.runMu.Lock()
+	defer p.runMu.Unlock()
 
-	return sendLastRunResultToStream(ctx, w, flusher, h.WorkflowRunner, h.SourceID, h.OverlayPath, run.SourceStepComplete)
+	return sendLastRunResultToStream(ctx, w, flusher, p.state(), p.sourceID, h.overlayPath(p.sourceID), run.SourceStepComplete)
 }
 
-func (h *StudioHandlers) reRun(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-	// check if client is already disconnected
-	if ctx.Err() != nil {
-		return ctx.Err()
-	}
-
-	// check if another run is already waiting
-	h.runQueued.mu.Lock()
-	if h.runQueued.v {
-		h.runQueued.mu.Unlock()
-		return errors.New("Too many Re-run requests: please allow the current run to finish.")
+// subscribe streams the results of every subsequent run matching the source
+// and target query parameters, whichever client started it, until the client
+// disconnects.
+func (h *StudioHandlers) subscribe(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	sourceID, targetID, err := h.selection(r)
+	if err != nil {
+		return err
 	}
-	h.runQueued.v = true
-	h.runQueued.mu.Unlock()
-
-	// cancel previous run (if any) and wait for it to finish
-	_ = h.WorkflowRunner.CancelGeneration()
-	h.runMutex.Lock()
-	// previous run released the lock, meaning it has finished
-	defer h.runMutex.Unlock()
-
-	// no longer waiting
-	h.runQueued.mu.Lock()
-	h.runQueued.v = false
-	h.runQueued.mu.Unlock()
 
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
@@ -125,6 +147,33 @@ func (h *StudioHandlers) reRun(ctx context.Context, w http.ResponseWriter, r *ht
 	if !ok {
 		return errors.New("streaming unsupported")
 	}
+	flusher.Flush()
+
+	ch := h.events.subscribe(eventFilter{sourceID: sourceID, targetID: targetID})
+	defer h.events.unsubscribe(ch)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-ch:
+			_, _ = w.Write(event)
+			flusher.Flush()
+		}
+	}
+}
+
+func (h *StudioHandlers) reRun(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	p, err := h.selectPipeline(r)
+	if err != nil {
+		return err
+	}
+
+	release, err := h.queueRun(ctx, p)
+	if err != nil {
+		return err
+	}
+	defer release()
 
 	body, _ := io.ReadAll(r.Body)
 	r.Body = io.NopCloser(bytes.NewBuffer(body))
@@ -133,17 +182,83 @@ func (h *StudioHandlers) reRun(ctx context.Context, w http.ResponseWriter, r *ht
 		return errors.ErrBadRequest.Wrap(fmt.Errorf("could not decode RunRequestBody: %w", err))
 	}
 
-	updatedOverlayPath, err := updateSourceAndTarget(h.WorkflowRunner, h.SourceID, h.OverlayPath, runRequestBody)
+	h.workflowMu.Lock()
+	updatedOverlayPath, err := updateSourceAndTarget(p.state(), p.sourceID, h.overlayPath(p.sourceID), runRequestBody)
+	h.workflowMu.Unlock()
 	if err != nil {
 		return fmt.Errorf("error updating source: %w", err)
 	}
-	h.OverlayPath = updatedOverlayPath
+	h.setOverlayPath(p.sourceID, updatedOverlayPath)
 
-	defer func() {
-		h.WorkflowRunner.OnSourceResult = func(r *run.SourceResult, s run.SourceStepID) error { return nil }
-	}()
+	return h.streamRun(ctx, w, p, runRequestBody.Disconnect, runRequestBody.Stream)
+}
+
+// queueRun cancels the run in progress for the pipeline, if any, and waits
+// for it to finish, for other runs generating into the same output
+// directories, and for other runs of the same source to be past their source
+// step. The caller must call the returned function once its own run is done.
+func (h *StudioHandlers) queueRun(ctx context.Context, p *pipeline) (func(), error) {
+	// check if client is already disconnected
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	// check if another run is already waiting
+	p.runQueued.mu.Lock()
+	if p.runQueued.v {
+		p.runQueued.mu.Unlock()
+		return nil, errors.New("Too many Re-run requests: please allow the current run to finish.")
+	}
+	p.runQueued.v = true
+	p.runQueued.mu.Unlock()
+
+	// cancel previous run (if any) and wait for it to finish
+	_ = p.state().CancelGeneration()
+	p.runMu.Lock()
+	// previous run released the lock, meaning it has finished
+
+	outputLocks := h.outputLocks(p)
+	for _, lock := range outputLocks {
+		lock.Lock()
+	}
+	sourceLock := h.sourceLock(p.sourceID)
+	sourceLock.Lock()
+	sourceDone := sync.OnceFunc(sourceLock.Unlock)
+	p.sourceDone = sourceDone
+
+	// no longer waiting
+	p.runQueued.mu.Lock()
+	p.runQueued.v = false
+	p.runQueued.mu.Unlock()
+
+	return func() {
+		sourceDone()
+		for _, lock := range outputLocks {
+			lock.Unlock()
+		}
+		p.runMu.Unlock()
+	}, nil
+}
 
-	clonedWorkflow, err := h.WorkflowRunner.Clone(
+// streamRun reruns a pipeline with the current workflow.yaml, overlay and
+// gen.yaml files, streaming progress to the client and to every matching
+// /events subscriber. It must be called between queueRun and its release.
+func (h *StudioHandlers) streamRun(ctx context.Context, w http.ResponseWriter, p *pipeline, disconnect bool, streamOptions *components.RunStreamOptions) error {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return errors.New("streaming unsupported")
+	}
+	// Everything streamed to this client is also published to /events.
+	stream := &broadcastWriter{ResponseWriter: w, Flusher: flusher, events: &h.events, sourceID: p.sourceID, targetID: p.targetID}
+
+	previousRunner := p.state()
+	overlayPath := h.overlayPath(p.sourceID)
+	streamSourceResult := onSourceResult(h.Ctx, stream, stream, previousRunner, p.sourceID, overlayPath)
+	sourceDone := p.sourceDone
+	workflowRunner, err := previousRunner.Clone(
 		h.Ctx,
 		run.WithSkipCleanup(),
 		run.WithLinting(),
@@ -151,26 +266,44 @@ func (h *StudioHandlers) reRun(ctx context.Context, w http.ResponseWriter, r *ht
 		run.WithSkipSnapshot(true),
 		run.WithSkipChangeReport(true),
 		run.WithShouldCompile(true),
-		run.WithCancellableGeneration(!runRequestBody.Disconnect),
+		run.WithCancellableGeneration(!disconnect),
 		run.WithDebug(env.IsLocalDev()),
-		run.WithSourceUpdates(onSourceResult(h.Ctx, w, flusher, h.WorkflowRunner, h.SourceID, h.OverlayPath)),
+		run.WithSourceUpdates(func(sourceResult *run.SourceResult, sourceStep run.SourceStepID) error {
+			err := streamSourceResult(sourceResult, sourceStep)
+			// Other runs of the source may go ahead once its document is written.
+			if sourceResult.Source == p.sourceID && sourceStep == run.SourceStepComplete {
+				sourceDone()
+			}
+			return err
+		}),
 	)
 	if err != nil {
 		return fmt.Errorf("error cloning workflow runner: %w", err)
 	}
-	h.WorkflowRunner = clonedWorkflow
+	defer func() {
+		workflowRunner.OnSourceResult = func(r *run.SourceResult, s run.SourceStepID) error { return nil }
+	}()
+	p.setRunner(workflowRunner)
 
-	if runRequestBody.Stream != nil {
-		h.enableGenerationProgressUpdates(w, flusher, runRequestBody.Stream.GenSteps, runRequestBody.Stream.FileStatus)
-		defer h.disableGenerationProgressUpdates()
+	h.stateMu.Lock()
+	h.latestRunner = workflowRunner
+	h.stateMu.Unlock()
+
+	if streamOptions != nil {
+		enableGenerationProgressUpdates(workflowRunner, p.sourceID, stream, stream, streamOptions.GenSteps, streamOptions.FileStatus)
+		defer disableGenerationProgressUpdates(workflowRunner)
 	}
 
-	err = h.WorkflowRunner.RunWithVisualization(h.Ctx)
+	err = workflowRunner.RunWithVisualization(h.Ctx)
 	if err != nil {
 		fmt.Println("error running workflow:", err)
 	}
 
-	if runRequestBody.Disconnect {
+	if disconnect {
+		// A headless server outlives its clients.
+		if h.Headless {
+			return nil
+		}
 		if err = h.Server.Shutdown(ctx); err != nil {
 			return fmt.Errorf("error shutting down server: %w", err)
 		}
@@ -178,7 +311,120 @@ func (h *StudioHandlers) reRun(ctx context.Context, w http.ResponseWriter, r *ht
 		return nil
 	}
 
-	return sendLastRunResultToStream(ctx, w, flusher, h.WorkflowRunner, h.SourceID, h.OverlayPath, run.SourceStepComplete)
+	return sendLastRunResultToStream(ctx, stream, stream, workflowRunner, p.sourceID, overlayPath, run.SourceStepComplete)
+}
+
+func (h *StudioHandlers) getTransformations(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	sourceID := r.PathValue("sourceID")
+	source, ok := h.workflowFile().Sources[sourceID]
+	if !ok {
+		return errors.ErrNotFound.Wrap(fmt.Errorf("source %s not found", sourceID))
+	}
+
+	transformations, err := encodeTransformations(source.Transformations)
+	if err != nil {
+		return fmt.Errorf("error encoding transformations: %w", err)
+	}
+
+	return json.NewEncoder(w).Encode(components.TransformationsResponse{
+		SourceID:        sourceID,
+		Transformations: transformations,
+	})
+}
+
+// updateTransformations replaces the transformations of a source and reruns
+// the pipeline of the target selected in the query string, or the source's
+// own pipeline.
+func (h *StudioHandlers) updateTransformations(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	sourceID := r.PathValue("sourceID")
+	selectedSourceID, targetID, err := h.selection(r)
+	if err != nil {
+		return err
+	}
+	if selectedSourceID != "" && selectedSourceID != sourceID {
+		return errors.ErrBadRequest.Wrap(fmt.Errorf("target %s is not generated from source %s", targetID, sourceID))
+	}
+	p, err := h.pipeline(sourceID, targetID)
+	if err != nil {
+		return err
+	}
+
+	var requestBody components.TransformationsRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		return errors.ErrBadRequest.Wrap(fmt.Errorf("could not decode TransformationsRequestBody: %w", err))
+	}
+	transformations, err := decodeTransformations(requestBody.Transformations)
+	if err != nil {
+		return err
+	}
+
+	release, err := h.queueRun(ctx, p)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	h.workflowMu.Lock()
+	err = updateSourceTransformations(p.state(), sourceID, transformations)
+	h.workflowMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return h.streamRun(ctx, w, p, false, requestBody.Stream)
+}
+
+func (h *StudioHandlers) getTargetConfig(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	targetID := r.PathValue("targetID")
+	target, ok := h.workflowFile().Targets[targetID]
+	if !ok {
+		return errors.ErrNotFound.Wrap(fmt.Errorf("target %s not found", targetID))
+	}
+
+	cfg, err := sdkGenConfig.Load(targetOutputDir(h.WorkflowRunner, target))
+	if err != nil {
+		return fmt.Errorf("error loading config file: %w", err)
+	}
+	genYaml, err := readFileData("gen.yaml", cfg.ConfigPath)
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(w).Encode(components.TargetConfigResponse{
+		TargetID: targetID,
+		GenYaml:  genYaml,
+	})
+}
+
+// updateTargetConfig replaces the gen.yaml of a target and reruns the
+// target's pipeline.
+func (h *StudioHandlers) updateTargetConfig(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	targetID := r.PathValue("targetID")
+	target, ok := h.workflowFile().Targets[targetID]
+	if !ok {
+		return errors.ErrNotFound.Wrap(fmt.Errorf("target %s not found", targetID))
+	}
+	p, err := h.pipeline(target.Source, targetID)
+	if err != nil {
+		return err
+	}
+
+	var requestBody components.TargetConfigRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		return errors.ErrBadRequest.Wrap(fmt.Errorf("could not decode TargetConfigRequestBody: %w", err))
+	}
+
+	release, err := h.queueRun(ctx, p)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if err := writeTargetConfig(p.state(), targetID, requestBody.Config); err != nil {
+		return err
+	}
+
+	return h.streamRun(ctx, w, p, false, requestBody.Stream)
 }
 
 func (h *StudioHandlers) health(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
@@ -186,7 +432,7 @@ func (h *StudioHandlers) health(ctx context.Context, w http.ResponseWriter, r *h
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
 
-	h.healthCheckSeen = true
+	h.healthCheckSeen.Store(true)
 
 	flusher, ok := w.(http.Flusher)
 	if !ok {
@@ -210,11 +456,23 @@ func (h *StudioHandlers) health(ctx context.Context, w http.ResponseWriter, r *h
 }
 
 func (h *StudioHandlers) root(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	// A headless server has no web UI, so point at the API description instead
+	if h.StudioURL == "" {
+		http.Redirect(w, r, "/openapi.yaml", http.StatusSeeOther)
+		return nil
+	}
+
 	// In case the user navigates to the root of the studio, redirect them to the studio URL
 	http.Redirect(w, r, h.StudioURL, http.StatusSeeOther)
 	return nil
 }
 
+func (h *StudioHandlers) openAPIDescription(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	w.Header().Set("Content-Type", "application/yaml")
+	_, err := w.Write(openAPIDescription)
+	return err
+}
+
 func (h *StudioHandlers) compareOverlay(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	var requestBody components.OverlayCompareRequestBody
 
@@ -256,7 +514,12 @@ func (h *StudioHandlers) compareOverlay(ctx context.Context, w http.ResponseWrit
 }
 
 func (h *StudioHandlers) suggestMethodNames(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-	sourceResult, err := runSource(h.Ctx, h.WorkflowRunner, h.SourceID)
+	p, err := h.selectPipeline(r)
+	if err != nil {
+		return err
+	}
+	overlayPath := h.overlayPath(p.sourceID)
+	sourceResult, err := runSource(h.Ctx, p.state(), p.sourceID)
 	if err != nil {
 		return fmt.Errorf("error running source: %w", err)
 	}
@@ -271,8 +534,8 @@ func (h *StudioHandlers) suggestMethodNames(ctx context.Context, w http.Response
 		return fmt.Errorf("error suggesting method names: %w", err)
 	}
 
-	if h.OverlayPath != "" {
-		existingOverlay, err := loader.LoadOverlay(h.OverlayPath)
+	if overlayPath != "" {
+		existingOverlay, err := loader.LoadOverlay(overlayPath)
 		if err != nil {
 			log.From(ctx).Warnf("error loading existing overlay: %s", err.Error())
 		} else {
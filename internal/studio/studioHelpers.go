@@ -1,6 +1,7 @@
 package studio
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/samber/lo"
 	vErrs "github.com/speakeasy-api/openapi-generation/v2/pkg/errors"
+	"github.com/speakeasy-api/openapi-generation/v2/pkg/generate"
 	"github.com/speakeasy-api/openapi/overlay"
 	"github.com/speakeasy-api/openapi/pointer"
 	"github.com/speakeasy-api/sdk-gen-config/workflow"
@@ -22,6 +24,7 @@ import (
 	"github.com/speakeasy-api/speakeasy/internal/schemas"
 	"github.com/speakeasy-api/speakeasy/internal/studio/modifications"
 	"github.com/speakeasy-api/speakeasy/internal/studio/sdk/models/components"
+	"github.com/speakeasy-api/speakeasy/internal/validation"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 	"gopkg.in/yaml.v3"
@@ -450,7 +453,11 @@ func upsertOverlay(overlay overlay.Overlay, workflowRunner *run.Workflow, source
 		}
 	}
 
-	workflowConfig := workflowRunner.GetWorkflowFile()
+	// Other pipelines may have saved the workflow since this runner loaded it
+	workflowConfig, _, err := workflow.Load(workflowRunner.ProjectDir)
+	if err != nil {
+		return overlayPath, err
+	}
 	source := workflowConfig.Sources[sourceID]
 
 	newOverlayPath, err := modifications.UpsertOverlay(overlayPath, &source, overlay)
@@ -507,35 +514,126 @@ func updateSourceAndTarget(workflowRunner *run.Workflow, sourceID, overlayPath s
 	}
 
 	for targetID, input := range runRequestBody.Targets {
-		wfTarget, ok := workflowRunner.GetWorkflowFile().Targets[targetID]
-		if !ok {
-			return overlayPath, errors.ErrBadRequest.Wrap(fmt.Errorf("target %s not found", targetID))
-		}
-		sdkPath := workflowRunner.ProjectDir
-		if wfTarget.Output != nil {
-			sdkPath = filepath.Join(sdkPath, *wfTarget.Output)
+		if err := writeTargetConfig(workflowRunner, targetID, input.Config); err != nil {
+			return overlayPath, err
 		}
+	}
 
-		cfg, err := sdkGenConfig.Load(sdkPath)
-		if err != nil {
-			return overlayPath, errors.ErrBadRequest.Wrap(fmt.Errorf("error loading config file: %w", err))
-		}
+	return overlayPath, nil
+}
 
-		currentFileContent, err := os.ReadFile(cfg.ConfigPath)
-		if err != nil {
-			return overlayPath, errors.ErrBadRequest.Wrap(fmt.Errorf("error loading config file: %w", err))
+func targetOutputDir(workflowRunner *run.Workflow, target workflow.Target) string {
+	sdkPath := workflowRunner.ProjectDir
+	if target.Output != nil {
+		sdkPath = filepath.Join(sdkPath, *target.Output)
+	}
+	return sdkPath
+}
+
+// writeTargetConfig replaces the gen.yaml of a target, rolling back to the
+// previous contents if the new config doesn't load or fails validation.
+func writeTargetConfig(workflowRunner *run.Workflow, targetID, content string) error {
+	wfTarget, ok := workflowRunner.GetWorkflowFile().Targets[targetID]
+	if !ok {
+		return errors.ErrBadRequest.Wrap(fmt.Errorf("target %s not found", targetID))
+	}
+	sdkPath := targetOutputDir(workflowRunner, wfTarget)
+
+	cfg, err := sdkGenConfig.Load(sdkPath)
+	if err != nil {
+		return errors.ErrBadRequest.Wrap(fmt.Errorf("error loading config file: %w", err))
+	}
+
+	currentFileContent, err := os.ReadFile(cfg.ConfigPath)
+	if err != nil {
+		return errors.ErrBadRequest.Wrap(fmt.Errorf("error loading config file: %w", err))
+	}
+	rollback := func() {
+		_ = utils.WriteStringToFile(cfg.ConfigPath, string(currentFileContent))
+	}
+
+	err = utils.WriteStringToFile(cfg.ConfigPath, content)
+	if err != nil {
+		return errors.ErrBadRequest.Wrap(fmt.Errorf("error writing input to file: %w", err))
+	}
+
+	newCfg, err := sdkGenConfig.Load(sdkPath)
+	if err != nil {
+		rollback()
+		return errors.ErrBadRequest.Wrap(fmt.Errorf("invalid config file changes rolling back: %w", err))
+	}
+
+	if errs := validation.ValidateConfig(wfTarget.Target, newCfg, wfTarget.IsPublished()); len(errs) > 0 && !errors.Is(errs[0], validation.ErrNoConfigFound) {
+		rollback()
+		messages := lo.Map(errs, func(err error, _ int) string { return err.Error() })
+		return errors.ErrValidation.Wrap(fmt.Errorf("invalid gen.yaml for target %s, rolling back: %s", targetID, strings.Join(messages, "; ")))
+	}
+
+	return nil
+}
+
+// updateSourceTransformations replaces the transformations of a source in
+// workflow.yaml, leaving it untouched if the result doesn't validate.
+func updateSourceTransformations(workflowRunner *run.Workflow, sourceID string, transformations []workflow.Transformation) error {
+	// Other pipelines may have saved the workflow since this runner loaded it
+	workflowConfig, _, err := workflow.Load(workflowRunner.ProjectDir)
+	if err != nil {
+		return fmt.Errorf("error loading workflow: %w", err)
+	}
+	source, ok := workflowConfig.Sources[sourceID]
+	if !ok {
+		return errors.ErrNotFound.Wrap(fmt.Errorf("source %s not found", sourceID))
+	}
+
+	source.Transformations = transformations
+	workflowConfig.Sources[sourceID] = source
+
+	if err := workflowConfig.Validate(generate.GetSupportedTargetNames()); err != nil {
+		return errors.ErrValidation.Wrap(fmt.Errorf("invalid transformations for source %s: %w", sourceID, err))
+	}
+
+	return workflow.Save(workflowRunner.ProjectDir, workflowConfig)
+}
+
+// decodeTransformations converts transformations received as JSON into their
+// workflow.yaml form. Each must name exactly one known kind.
+func decodeTransformations(raw []map[string]any) ([]workflow.Transformation, error) {
+	transformations := make([]workflow.Transformation, 0, len(raw))
+	for i, t := range raw {
+		if len(t) != 1 {
+			return nil, errors.ErrBadRequest.Wrap(fmt.Errorf("transformation %d must have exactly one kind, got %d", i, len(t)))
 		}
 
-		err = utils.WriteStringToFile(cfg.ConfigPath, input.Config)
+		b, err := yaml.Marshal(t)
 		if err != nil {
-			return overlayPath, errors.ErrBadRequest.Wrap(fmt.Errorf("error writing input to file: %w", err))
+			return nil, errors.ErrBadRequest.Wrap(fmt.Errorf("error encoding transformation %d: %w", i, err))
 		}
 
-		if _, err := sdkGenConfig.Load(sdkPath); err != nil {
-			err = utils.WriteStringToFile(cfg.ConfigPath, string(currentFileContent))
-			return overlayPath, errors.ErrBadRequest.Wrap(fmt.Errorf("invalid config file changes rolling back: %w", err))
+		dec := yaml.NewDecoder(bytes.NewReader(b))
+		dec.KnownFields(true)
+		var transformation workflow.Transformation
+		if err := dec.Decode(&transformation); err != nil {
+			return nil, errors.ErrBadRequest.Wrap(fmt.Errorf("invalid transformation %d: %w", i, err))
 		}
+		transformations = append(transformations, transformation)
 	}
 
-	return overlayPath, nil
+	return transformations, nil
+}
+
+func encodeTransformations(transformations []workflow.Transformation) ([]map[string]any, error) {
+	b, err := yaml.Marshal(transformations)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded := []map[string]any{}
+	if err := yaml.Unmarshal(b, &encoded); err != nil {
+		return nil, err
+	}
+	if encoded == nil {
+		encoded = []map[string]any{}
+	}
+
+	return encoded, nil
 }
@@ -5,10 +5,14 @@ import (
 	"github.com/speakeasy-api/speakeasy-core/openapi"
 	"github.com/speakeasy-api/speakeasy-core/suggestions"
 	"github.com/speakeasy-api/speakeasy/internal/schemas"
+	"github.com/speakeasy-api/speakeasy/internal/suggest/errorCodes"
 )
 
+// Diagnose reports the suggestions that apply to the schema. Missing error
+// codes are checked against the error groups configured in the workspace's
+// .speakeasy/suggest.yaml, like suggest error-types does.
 func Diagnose(ctx context.Context, schemaPath string) (suggestions.Diagnosis, error) {
-	data, _, err := schemas.LoadDocument(ctx, schemaPath)
+	data, doc, err := schemas.LoadDocument(ctx, schemaPath)
 	if err != nil {
 		return nil, err
 	}
@@ -17,7 +21,24 @@ func Diagnose(ctx context.Context, schemaPath string) (suggestions.Diagnosis, er
 		return nil, err
 	}
 
-	return suggestions.Diagnose(ctx, *summary)
+	diagnosis, err := suggestions.Diagnose(ctx, *summary)
+	if err != nil {
+		return nil, err
+	}
+
+	errorCodesDiagnosis, err := errorCodes.Diagnose(doc, schemaPath)
+	if err != nil {
+		return nil, err
+	}
+	if diagnosis == nil {
+		diagnosis = suggestions.Diagnosis{}
+	}
+	delete(diagnosis, suggestions.MissingErrorCodes)
+	if diagnostics := errorCodesDiagnosis[suggestions.MissingErrorCodes]; len(diagnostics) > 0 {
+		diagnosis[suggestions.MissingErrorCodes] = diagnostics
+	}
+
+	return diagnosis, nil
 }
 
 func ShouldSuggest(d suggestions.Diagnosis) bool {
@@ -0,0 +1,103 @@
+package errorCodes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/speakeasy-api/sdk-gen-config/workspace"
+	"gopkg.in/yaml.v3"
+)
+
+const configFile = "suggest.yaml"
+
+// Config is the configuration of `speakeasy suggest`, read from
+// .speakeasy/suggest.yaml.
+type Config struct {
+	// ErrorGroups replace the default error groups when set.
+	ErrorGroups []ErrorGroupConfig `yaml:"errorGroups,omitempty"`
+	// ErrorSchema is a reference to an existing schema, e.g.
+	// "#/components/schemas/Error", shared by the responses of every error
+	// group that doesn't set its own. No schemas are added when set.
+	ErrorSchema string `yaml:"errorSchema,omitempty"`
+}
+
+// ErrorGroupConfig groups error status codes under a single response.
+type ErrorGroupConfig struct {
+	Name string `yaml:"name"`
+	// Codes are status codes or ranges, e.g. "404" or "5XX".
+	Codes       []string `yaml:"codes"`
+	Description string   `yaml:"description,omitempty"`
+	// SchemaName and ResponseName default to Name.
+	SchemaName   string `yaml:"schemaName,omitempty"`
+	ResponseName string `yaml:"responseName,omitempty"`
+	// SchemaRef is a reference to an existing schema to use for the response
+	// instead of adding one.
+	SchemaRef string `yaml:"schemaRef,omitempty"`
+}
+
+// LoadConfig finds and reads .speakeasy/suggest.yaml from dir or any of its
+// parents. A nil config is returned if there is none.
+func LoadConfig(dir string) (*Config, error) {
+	res, _ := workspace.FindWorkspace(dir, workspace.FindWorkspaceOptions{
+		FindFile:  configFile,
+		Recursive: true,
+	})
+	if res == nil || len(res.Data) == 0 {
+		return nil, nil
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(res.Data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", res.Path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", res.Path, err)
+	}
+
+	return &cfg, nil
+}
+
+func (c *Config) Validate() error {
+	if c.ErrorSchema != "" && !strings.HasPrefix(c.ErrorSchema, "#/") {
+		return fmt.Errorf("errorSchema must be a local reference like #/components/schemas/Error, got %s", c.ErrorSchema)
+	}
+
+	seenCodes := map[string]string{}
+	for i, group := range c.ErrorGroups {
+		if group.Name == "" {
+			return fmt.Errorf("error group %d is missing a name", i)
+		}
+		if len(group.Codes) == 0 {
+			return fmt.Errorf("error group %s has no codes", group.Name)
+		}
+		for _, code := range group.Codes {
+			if !isErrorCode(code) {
+				return fmt.Errorf("error group %s has invalid code %s: expected a 4XX or 5XX status code or range", group.Name, code)
+			}
+			if other, ok := seenCodes[strings.ToUpper(code)]; ok {
+				return fmt.Errorf("code %s is in both error groups %s and %s", code, other, group.Name)
+			}
+			seenCodes[strings.ToUpper(code)] = group.Name
+		}
+		if group.SchemaRef != "" && !strings.HasPrefix(group.SchemaRef, "#/") {
+			return fmt.Errorf("error group %s: schemaRef must be a local reference like #/components/schemas/Error, got %s", group.Name, group.SchemaRef)
+		}
+	}
+
+	return nil
+}
+
+func isErrorCode(code string) bool {
+	if len(code) != 3 || (code[0] != '4' && code[0] != '5') {
+		return false
+	}
+	if strings.EqualFold(code[1:], "XX") {
+		return true
+	}
+	for _, c := range code[1:] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -2,17 +2,24 @@ package errorCodes
 
 import (
 	"fmt"
+	"path/filepath"
 
 	"github.com/speakeasy-api/openapi/openapi"
 	coreopenapi "github.com/speakeasy-api/speakeasy-core/openapi"
 	"github.com/speakeasy-api/speakeasy-core/suggestions"
 )
 
-var errorGroupsDiagnose = initErrorGroups()
-
-func Diagnose(doc *openapi.OpenAPI) suggestions.Diagnosis {
+// Diagnose reports the operations of doc missing error responses of the
+// error groups configured in the .speakeasy/suggest.yaml found from the
+// schema's directory, or of the default groups.
+func Diagnose(doc *openapi.OpenAPI, schemaPath string) (suggestions.Diagnosis, error) {
 	diagnosis := suggestions.Diagnosis{}
 
+	groups, err := loadErrorGroups(filepath.Dir(schemaPath))
+	if err != nil {
+		return nil, err
+	}
+
 	for op := range coreopenapi.IterateOperations(doc) {
 		method, path, operation := op.Method, op.Path, op.Operation
 
@@ -25,7 +32,7 @@ func Diagnose(doc *openapi.OpenAPI) suggestions.Diagnosis {
 			})
 		}
 
-		missingCodes := getMissingErrorCodes(operation)
+		missingCodes := getMissingErrorCodes(operation, groups)
 		if len(missingCodes) > 0 {
 			diagnosis.Add(suggestions.MissingErrorCodes, suggestions.Diagnostic{
 				SchemaPath: schemaPath,
@@ -34,13 +41,13 @@ func Diagnose(doc *openapi.OpenAPI) suggestions.Diagnosis {
 		}
 	}
 
-	return diagnosis
+	return diagnosis, nil
 }
 
-func getMissingErrorCodes(operation *openapi.Operation) []string {
+func getMissingErrorCodes(operation *openapi.Operation, groups errorGroupSlice) []string {
 	var missingCodes []string
 
-	for _, code := range errorGroupsDiagnose.AllCodes() {
+	for _, code := range groups.AllCodes() {
 		if responseRef, _ := getResponseForCode(&operation.Responses, code); responseRef == nil {
 			missingCodes = append(missingCodes, code)
 		}
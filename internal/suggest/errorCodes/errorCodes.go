@@ -3,6 +3,7 @@ package errorCodes
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"slices"
 
 	"github.com/speakeasy-api/openapi/openapi"
@@ -14,13 +15,27 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+// BuildErrorCodesOverlay suggests error responses using the error groups
+// configured in the .speakeasy/suggest.yaml found from the schema's
+// directory, or the default groups.
 func BuildErrorCodesOverlay(ctx context.Context, schemaPath string) (*overlay.Overlay, error) {
+	cfg, err := LoadConfig(filepath.Dir(schemaPath))
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildErrorCodesOverlayWithConfig(ctx, schemaPath, cfg)
+}
+
+// BuildErrorCodesOverlayWithConfig suggests error responses using the error
+// groups of cfg, or the default groups if cfg is nil.
+func BuildErrorCodesOverlayWithConfig(ctx context.Context, schemaPath string, cfg *Config) (*overlay.Overlay, error) {
 	_, doc, err := schemas.LoadDocument(ctx, schemaPath)
 	if err != nil {
 		return nil, err
 	}
 
-	groups := initErrorGroups()
+	groups := errorGroupsFromConfig(cfg)
 	groups.DeduplicateComponentNames(doc)
 
 	builder := builder{document: doc, errorGroups: groups}
@@ -102,7 +117,9 @@ func (b *builder) Build() overlay.Overlay {
 	var missingSchemaNodes []*yaml.Node
 	var missingComponentNodes []*yaml.Node
 	for _, missingComponent := range missingComponents {
-		missingSchemaNodes = append(missingSchemaNodes, getSchemaNodes(missingComponent)...)
+		if missingComponent.schemaRef == "" {
+			missingSchemaNodes = append(missingSchemaNodes, getSchemaNodes(missingComponent)...)
+		}
 		missingComponentNodes = append(missingComponentNodes, getComponentNodes(missingComponent)...)
 	}
 
@@ -266,10 +283,15 @@ func getSchemaNodes(group errorGroup) []*yaml.Node {
 func getComponentNodes(group errorGroup) []*yaml.Node {
 	builder := yamlutil.NewBuilder(false)
 
+	schemaRef := group.schemaRef
+	if schemaRef == "" {
+		schemaRef = "#/components/schemas/" + group.schemaName
+	}
+
 	var nodes []*yaml.Node
 	nodes = append(nodes, builder.NewNodeItem("description", group.description)...)
 	nodes = append(nodes, builder.NewKeyNode("content"))
-	nodes = append(nodes, builder.NewNode("application/json", builder.NewNode("schema", builder.NewMultinode("$ref", "#/components/schemas/"+group.schemaName))))
+	nodes = append(nodes, builder.NewNode("application/json", builder.NewNode("schema", builder.NewMultinode("$ref", schemaRef))))
 
 	return []*yaml.Node{builder.NewKeyNode(group.responseName), builder.NewMappingNode(nodes...)}
 }
@@ -3,6 +3,7 @@ package errorCodes_test
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/speakeasy-api/speakeasy-core/suggestions"
@@ -56,6 +57,69 @@ func TestBuildErrorCodesOverlay(t *testing.T) {
 	}
 }
 
+func TestBuildErrorCodesOverlayWithConfig(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cfg := &errorCodes.Config{
+		ErrorGroups: []errorCodes.ErrorGroupConfig{
+			{Name: "ClientError", Codes: []string{"400", "404"}},
+			{Name: "Conflict", Codes: []string{"409"}},
+			{Name: "ServerError", Codes: []string{"500"}, Description: "Server error", SchemaRef: "#/components/schemas/ApiErrorNotFound"},
+		},
+		ErrorSchema: "#/components/schemas/ApiErrorInvalidInput",
+	}
+	require.NoError(t, cfg.Validate())
+
+	overlay, err := errorCodes.BuildErrorCodesOverlayWithConfig(ctx, "testData/simple.yaml", cfg)
+	require.NoError(t, err)
+
+	schemaBytes, err := os.ReadFile("testData/simple.yaml")
+	require.NoError(t, err)
+
+	var root yaml.Node
+	require.NoError(t, yaml.Unmarshal(schemaBytes, &root))
+	require.NoError(t, overlay.ApplyTo(&root))
+
+	expectedBytes, err := os.ReadFile("testData/simple_config_expected.yaml")
+	require.NoError(t, err)
+
+	actualBytes, err := yaml.Marshal(&root)
+	require.NoError(t, err)
+
+	require.YAMLEq(t, string(expectedBytes), string(actualBytes))
+}
+
+func TestConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		name    string
+		cfg     errorCodes.Config
+		wantErr string
+	}
+	toTest := []args{
+		{"Valid", errorCodes.Config{ErrorGroups: []errorCodes.ErrorGroupConfig{{Name: "ServerError", Codes: []string{"500", "5XX"}}}}, ""},
+		{"Missing name", errorCodes.Config{ErrorGroups: []errorCodes.ErrorGroupConfig{{Codes: []string{"400"}}}}, "missing a name"},
+		{"Not an error code", errorCodes.Config{ErrorGroups: []errorCodes.ErrorGroupConfig{{Name: "OK", Codes: []string{"200"}}}}, "invalid code 200"},
+		{"Code in two groups", errorCodes.Config{ErrorGroups: []errorCodes.ErrorGroupConfig{{Name: "A", Codes: []string{"4XX"}}, {Name: "B", Codes: []string{"4xx"}}}}, "in both error groups A and B"},
+		{"Remote schema ref", errorCodes.Config{ErrorSchema: "errors.yaml#/Error"}, "local reference"},
+	}
+
+	for _, tt := range toTest {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
 func TestDiagnose(t *testing.T) {
 	t.Parallel()
 
@@ -76,7 +140,8 @@ func TestDiagnose(t *testing.T) {
 			_, doc, err := schemas.LoadDocument(ctx, tt.schema)
 			require.NoError(t, err)
 
-			diagnosis := errorCodes.Diagnose(doc)
+			diagnosis, err := errorCodes.Diagnose(doc, tt.schema)
+			require.NoError(t, err)
 			if tt.expectedCount == 0 {
 				require.Empty(t, diagnosis)
 				return
@@ -90,3 +155,39 @@ func TestDiagnose(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigFromSchemaDir(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	schemaPath := filepath.Join(dir, "openapi.yaml")
+	schemaBytes, err := os.ReadFile("testData/simple.yaml")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(schemaPath, schemaBytes, 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".speakeasy"), 0o755))
+
+	// The config next to the schema applies, wherever the CLI is run from.
+	config := "errorGroups:\n  - name: ClientError\n    codes: [\"400\", \"404\"]\n  - name: Conflict\n    codes: [\"409\"]\n  - name: ServerError\n    codes: [\"500\"]\n    description: Server error\n    schemaRef: \"#/components/schemas/ApiErrorNotFound\"\nerrorSchema: \"#/components/schemas/ApiErrorInvalidInput\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".speakeasy", "suggest.yaml"), []byte(config), 0o644))
+
+	overlay, err := errorCodes.BuildErrorCodesOverlay(ctx, schemaPath)
+	require.NoError(t, err)
+
+	var root yaml.Node
+	require.NoError(t, yaml.Unmarshal(schemaBytes, &root))
+	require.NoError(t, overlay.ApplyTo(&root))
+
+	expectedBytes, err := os.ReadFile("testData/simple_config_expected.yaml")
+	require.NoError(t, err)
+	actualBytes, err := yaml.Marshal(&root)
+	require.NoError(t, err)
+	require.YAMLEq(t, string(expectedBytes), string(actualBytes))
+
+	// An invalid config is reported rather than ignored.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".speakeasy", "suggest.yaml"), []byte("errorGroups:\n  - codes: [\"400\"]\n"), 0o644))
+	_, doc, err := schemas.LoadDocument(ctx, schemaPath)
+	require.NoError(t, err)
+	_, err = errorCodes.Diagnose(doc, schemaPath)
+	require.ErrorContains(t, err, "missing a name")
+}
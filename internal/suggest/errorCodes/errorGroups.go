@@ -12,6 +12,9 @@ type errorGroup struct {
 	codes                    []string
 	description              string
 	schemaName, responseName string
+	// schemaRef references an existing schema for the response, in which case
+	// no schema is added for the group.
+	schemaRef string
 }
 type errorGroupSlice []errorGroup
 
@@ -45,7 +48,77 @@ func initErrorGroups() errorGroupSlice {
 			schemaName:   "RateLimited",
 			responseName: "RateLimited",
 		},
+		{
+			name:         "Conflict",
+			codes:        []string{"409"},
+			description:  "Conflict with the current state of the resource",
+			schemaName:   "Conflict",
+			responseName: "Conflict",
+		},
+		{
+			name:         "PreconditionFailed",
+			codes:        []string{"412"},
+			description:  "Precondition failed",
+			schemaName:   "PreconditionFailed",
+			responseName: "PreconditionFailed",
+		},
+		{
+			name:         "InternalServerError",
+			codes:        []string{"5XX"},
+			description:  "Server error",
+			schemaName:   "InternalServerError",
+			responseName: "InternalServerError",
+		},
+	}
+}
+
+// loadErrorGroups returns the error groups configured in the
+// .speakeasy/suggest.yaml found from dir, falling back to the defaults.
+func loadErrorGroups(dir string) (errorGroupSlice, error) {
+	cfg, err := LoadConfig(dir)
+	if err != nil {
+		return nil, err
+	}
+	return errorGroupsFromConfig(cfg), nil
+}
+
+func errorGroupsFromConfig(cfg *Config) errorGroupSlice {
+	if cfg == nil {
+		return initErrorGroups()
+	}
+
+	groups := initErrorGroups()
+	if len(cfg.ErrorGroups) > 0 {
+		groups = nil
+		for _, g := range cfg.ErrorGroups {
+			group := errorGroup{
+				name:         g.Name,
+				codes:        g.Codes,
+				description:  g.Description,
+				schemaName:   g.SchemaName,
+				responseName: g.ResponseName,
+				schemaRef:    g.SchemaRef,
+			}
+			if group.description == "" {
+				group.description = g.Name
+			}
+			if group.schemaName == "" {
+				group.schemaName = g.Name
+			}
+			if group.responseName == "" {
+				group.responseName = g.Name
+			}
+			groups = append(groups, group)
+		}
 	}
+
+	for i := range groups {
+		if groups[i].schemaRef == "" {
+			groups[i].schemaRef = cfg.ErrorSchema
+		}
+	}
+
+	return groups
 }
 
 func (e errorGroupSlice) FindCode(code string) errorGroup {
@@ -78,6 +151,8 @@ func (e errorGroupSlice) DeduplicateComponentNames(doc *openapi.OpenAPI) {
 
 	for i, group := range e {
 		e[i].responseName = findUnusedName(group.responseName, responseNames)
-		e[i].schemaName = findUnusedName(group.schemaName, schemaNames)
+		if group.schemaRef == "" {
+			e[i].schemaName = findUnusedName(group.schemaName, schemaNames)
+		}
 	}
 }
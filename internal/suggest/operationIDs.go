@@ -0,0 +1,383 @@
+package suggest
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/speakeasy-api/openapi/overlay"
+	coreopenapi "github.com/speakeasy-api/speakeasy-core/openapi"
+	"github.com/speakeasy-api/speakeasy-core/suggestions"
+	"github.com/speakeasy-api/speakeasy/internal/schemas"
+	"github.com/speakeasy-api/speakeasy/internal/studio/modifications"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	groupExtension        = "x-speakeasy-group"
+	nameOverrideExtension = "x-speakeasy-name-override"
+)
+
+// localOperation is the part of an operation the local suggester names
+// methods from.
+type localOperation struct {
+	path        string
+	method      string
+	operationID string
+	tags        []string
+	// group and name are the existing x-speakeasy-group and
+	// x-speakeasy-name-override of the operation, if any.
+	group string
+	name  string
+}
+
+type pathSegment struct {
+	name  string
+	param bool
+}
+
+// SuggestOperationIDsLocal suggests method names without calling the
+// Suggest API. Names are derived deterministically from the path, HTTP method
+// and tags of each operation: list/get/create/update/delete for collections
+// and items, the action name for action endpoints, grouped by tag or first
+// path segment. The overlay has the same shape as SuggestOperationIDs.
+func SuggestOperationIDsLocal(ctx context.Context, schemaPath string) (*overlay.Overlay, error) {
+	_, doc, err := schemas.LoadDocument(ctx, schemaPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Filter out any already-modified targets (generally through the studio)
+	alreadyModifiedTargets, _ := modifications.GetModifiedTargets(filepath.Dir(schemaPath), suggestions.ModificationTypeMethodName)
+
+	var operations []localOperation
+	for op := range coreopenapi.IterateOperations(doc) {
+		method := strings.ToLower(string(op.Method))
+		if slices.Contains(alreadyModifiedTargets, overlay.NewTargetSelector(op.Path, method)) {
+			continue
+		}
+
+		operation := localOperation{
+			path:        op.Path,
+			method:      method,
+			operationID: op.Operation.GetOperationID(),
+			tags:        op.Operation.GetTags(),
+		}
+		if extensions := op.Operation.GetExtensions(); extensions != nil {
+			if group := extensions.GetOrZero(groupExtension); group != nil {
+				operation.group = group.Value
+			}
+			if name := extensions.GetOrZero(nameOverrideExtension); name != nil {
+				operation.name = name.Value
+			}
+		}
+		operations = append(operations, operation)
+	}
+
+	o := buildMethodNamesOverlay(operations)
+	return &o, nil
+}
+
+// OperationIDWords returns the words of an operationId for an operation
+// matching the method the local suggester names it: its group followed by its
+// name, as in pets get for GET /pets/{petId}, which becomes pets.get().
+func OperationIDWords(path, method string, tags []string) []string {
+	op := localOperation{path: path, method: strings.ToLower(method), tags: tags}
+	segments := parsePath(path)
+	group := suggestGroup(op, segments)
+	return append(SplitWords(group), SplitWords(suggestName(op.method, group, segments))...)
+}
+
+func buildMethodNamesOverlay(operations []localOperation) overlay.Overlay {
+	// Name operations in a fixed order, so which of two colliding operations
+	// keeps the plain name doesn't depend on the order of the document
+	operations = slices.Clone(operations)
+	slices.SortStableFunc(operations, func(a, b localOperation) int {
+		if c := strings.Compare(a.path, b.path); c != 0 {
+			return c
+		}
+		return strings.Compare(a.method, b.method)
+	})
+
+	// Names taken within each group, including those we won't rename
+	taken := map[string]map[string]bool{}
+	take := func(group, name string) {
+		if taken[group] == nil {
+			taken[group] = map[string]bool{}
+		}
+		taken[group][name] = true
+	}
+	for _, op := range operations {
+		if op.name != "" {
+			take(op.group, op.name)
+		}
+	}
+
+	var actions []overlay.Action
+	for _, op := range operations {
+		// Names set by hand are kept
+		if op.name != "" {
+			continue
+		}
+
+		segments := parsePath(op.path)
+		group := suggestGroup(op, segments)
+		name := avoidCollision(suggestName(op.method, group, segments), op.method, segments, taken[group])
+		take(group, name)
+
+		if group == op.group && name == op.operationID {
+			continue
+		}
+
+		var update []*yaml.Node
+		if group != "" {
+			update = append(update, StringNode(groupExtension), StringNode(group))
+		}
+		update = append(update, StringNode(nameOverrideExtension), StringNode(name))
+
+		action := overlay.Action{
+			Target: overlay.NewTargetSelector(op.path, op.method),
+			Update: yaml.Node{Kind: yaml.MappingNode, Content: update},
+		}
+		suggestions.AddModificationExtension(&action, &suggestions.ModificationExtension{
+			Type:   suggestions.ModificationTypeMethodName,
+			Before: methodSignature(op.group, op.operationID),
+			After:  methodSignature(group, name),
+		})
+		actions = append(actions, action)
+	}
+
+	return overlay.Overlay{
+		Version: "1.0.0",
+		Info: overlay.Info{
+			Title:   "Method Names Overlay",
+			Version: "0.0.0",
+		},
+		Actions: actions,
+	}
+}
+
+func methodSignature(group, name string) string {
+	if group == "" {
+		group = "<no_group>"
+	}
+	if name == "" {
+		name = "<no_name>"
+	}
+	return fmt.Sprintf("sdk.%s.%s()", group, name)
+}
+
+var versionSegment = regexp.MustCompile(`^v\d+(\.\d+)*$`)
+
+// parsePath splits a path into segments, dropping the "api" and version
+// prefixes, which don't name resources.
+func parsePath(path string) []pathSegment {
+	var segments []pathSegment
+	for _, s := range strings.Split(path, "/") {
+		if s == "" {
+			continue
+		}
+		if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+			segments = append(segments, pathSegment{name: strings.Trim(s, "{}"), param: true})
+			continue
+		}
+		if len(segments) == 0 && (strings.EqualFold(s, "api") || versionSegment.MatchString(strings.ToLower(s))) {
+			continue
+		}
+		segments = append(segments, pathSegment{name: s})
+	}
+	return segments
+}
+
+// suggestGroup groups operations by their first tag, falling back to the
+// first resource in the path.
+func suggestGroup(op localOperation, segments []pathSegment) string {
+	if len(op.tags) > 0 {
+		return camelCase(SplitWords(op.tags[0]))
+	}
+	for _, s := range segments {
+		if !s.param {
+			return camelCase(SplitWords(s.name))
+		}
+	}
+	return ""
+}
+
+// suggestName names the method after the resource the path leads to, relative
+// to the resource of its group.
+func suggestName(method, group string, segments []pathSegment) string {
+	rest := segments
+	for i, s := range segments {
+		if !s.param && singular(camelCase(SplitWords(s.name))) == singular(group) {
+			rest = segments[i+1:]
+			break
+		}
+	}
+
+	// The group's own resource
+	if len(rest) == 0 {
+		if group == "" || isPlural(group) {
+			return collectionVerb(method)
+		}
+		return itemVerb(method)
+	}
+
+	last := rest[len(rest)-1]
+	noun := ""
+	for i := len(rest) - 1; i >= 0; i-- {
+		if !rest[i].param {
+			noun = rest[i].name
+			break
+		}
+	}
+	words := SplitWords(noun)
+
+	if last.param {
+		if len(words) > 0 {
+			words[len(words)-1] = singular(words[len(words)-1])
+		}
+		return camelCase(append([]string{itemVerb(method)}, words...))
+	}
+
+	if !isPlural(noun) {
+		// Posting to a singular name is an action, as in /users/{id}/activate
+		if method == "post" {
+			return camelCase(words)
+		}
+		return camelCase(append([]string{itemVerb(method)}, words...))
+	}
+
+	if method == "post" {
+		words[len(words)-1] = singular(words[len(words)-1])
+	}
+	return camelCase(append([]string{collectionVerb(method)}, words...))
+}
+
+func collectionVerb(method string) string {
+	switch method {
+	case "get":
+		return "list"
+	case "post":
+		return "create"
+	case "put", "patch":
+		return "update"
+	default:
+		return method
+	}
+}
+
+func itemVerb(method string) string {
+	switch method {
+	case "post":
+		return "create"
+	case "put", "patch":
+		return "update"
+	default:
+		return method
+	}
+}
+
+// avoidCollision disambiguates a name already taken within its group, first
+// by the path parameter that identifies the resource, then by the HTTP
+// method, then by a number.
+func avoidCollision(name, method string, segments []pathSegment, taken map[string]bool) string {
+	if !taken[name] {
+		return name
+	}
+
+	var candidates []string
+	if len(segments) > 0 && segments[len(segments)-1].param {
+		candidates = append(candidates, camelCase(append([]string{name, "by"}, SplitWords(segments[len(segments)-1].name)...)))
+	}
+	if !strings.HasPrefix(name, method) {
+		candidates = append(candidates, camelCase([]string{name, method}))
+	}
+	for _, candidate := range candidates {
+		if !taken[candidate] {
+			return candidate
+		}
+	}
+
+	for i := 2; ; i++ {
+		candidate := name + strconv.Itoa(i)
+		if !taken[candidate] {
+			return candidate
+		}
+	}
+}
+
+// SplitWords splits an identifier on separators, lower-to-upper case
+// boundaries and the end of an acronym, as in HTTPServer, lower-casing each
+// word.
+func SplitWords(s string) []string {
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, strings.ToLower(string(current)))
+			current = nil
+		}
+	}
+
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])),
+			unicode.IsUpper(r) && i > 0 && unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+
+	return words
+}
+
+func camelCase(words []string) string {
+	var b strings.Builder
+	for i, word := range words {
+		if word == "" {
+			continue
+		}
+		word = strings.ToLower(word)
+		if i > 0 {
+			word = strings.ToUpper(word[:1]) + word[1:]
+		}
+		b.WriteString(word)
+	}
+	return b.String()
+}
+
+// singular returns the singular form of a plural English noun, handling the
+// regular forms only.
+func singular(word string) string {
+	lower := strings.ToLower(word)
+	switch {
+	case strings.HasSuffix(lower, "ies") && len(word) > 3:
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(lower, "sses"), strings.HasSuffix(lower, "xes"), strings.HasSuffix(lower, "ches"), strings.HasSuffix(lower, "shes"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(lower, "s") && !strings.HasSuffix(lower, "ss") && !strings.HasSuffix(lower, "us") && !strings.HasSuffix(lower, "is") && len(word) > 1:
+		return word[:len(word)-1]
+	default:
+		return word
+	}
+}
+
+func isPlural(word string) bool {
+	return word != "" && singular(word) != word
+}
+
+// StringNode returns a YAML string scalar.
+func StringNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
@@ -0,0 +1,80 @@
+package suggest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/speakeasy-api/speakeasy-core/suggestions"
+	"github.com/speakeasy-api/speakeasy/internal/suggest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestOperationIDsLocal(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name       string
+		schemaPath string
+		expected   map[string]string
+	}
+	tests := []testCase{
+		{
+			name:       "Petstore",
+			schemaPath: "errorCodes/testData/petstore.yaml",
+			expected: map[string]string{
+				`$["paths"]["/pets"]["get"]`:         "sdk.pets.list()",
+				`$["paths"]["/pets"]["post"]`:        "sdk.pets.create()",
+				`$["paths"]["/pets/{petId}"]["get"]`: "sdk.pets.get()",
+			},
+		},
+		{
+			name:       "Conventions",
+			schemaPath: "testData/operationIDs.yaml",
+			expected: map[string]string{
+				`$["paths"]["/api/v1/pets"]["get"]`:                   "sdk.pets.list()",
+				`$["paths"]["/api/v1/pets"]["post"]`:                  "sdk.pets.create()",
+				`$["paths"]["/api/v1/pets/{petId}"]["get"]`:           "sdk.pets.get()",
+				`$["paths"]["/api/v1/pets/{petId}"]["patch"]`:         "sdk.pets.update()",
+				`$["paths"]["/api/v1/pets/{petId}"]["put"]`:           "sdk.pets.updateByPetId()",
+				`$["paths"]["/api/v1/pets/{petId}"]["delete"]`:        "sdk.pets.delete()",
+				`$["paths"]["/api/v1/pets/{petId}/owners"]["get"]`:    "sdk.pets.listOwners()",
+				`$["paths"]["/api/v1/pets/{petId}/adopt"]["post"]`:    "sdk.pets.adopt()",
+				`$["paths"]["/api/v1/categories"]["get"]`:             "sdk.categories.list()",
+				`$["paths"]["/api/v1/store/orders/{orderId}"]["get"]`: "sdk.store.getOrder()",
+			},
+		},
+		{
+			name:       "Collisions do not depend on document order",
+			schemaPath: "testData/operationIDsReordered.yaml",
+			expected: map[string]string{
+				`$["paths"]["/pets/{petId}"]["patch"]`: "sdk.pets.update()",
+				`$["paths"]["/pets/{petId}"]["put"]`:   "sdk.pets.updateByPetId()",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			o, err := suggest.SuggestOperationIDsLocal(ctx, tt.schemaPath)
+			require.NoError(t, err)
+
+			actual := map[string]string{}
+			for _, action := range o.Actions {
+				modification := suggestions.GetModificationExtension(action)
+				require.NotNil(t, modification)
+				assert.Equal(t, suggestions.ModificationTypeMethodName, modification.Type)
+				actual[action.Target] = modification.After
+			}
+			assert.Equal(t, tt.expected, actual)
+
+			// Suggestions are deterministic
+			again, err := suggest.SuggestOperationIDsLocal(ctx, tt.schemaPath)
+			require.NoError(t, err)
+			assert.Equal(t, o.Actions, again.Actions)
+		})
+	}
+}
@@ -36,7 +36,7 @@ func SuggestAndWrite(
 	ctx context.Context,
 	modificationType string,
 	schemaLocation string,
-	asOverlay, yamlOut bool,
+	asOverlay, yamlOut, local bool,
 	w io.Writer,
 ) error {
 	if asOverlay {
@@ -53,7 +53,11 @@ func SuggestAndWrite(
 	var overlay *overlay.Overlay
 	switch modificationType {
 	case suggestions.ModificationTypeMethodName:
-		overlay, err = SuggestOperationIDs(ctx, schemaBytes, schemaLocation)
+		if local {
+			overlay, err = SuggestOperationIDsLocal(ctx, schemaLocation)
+		} else {
+			overlay, err = SuggestOperationIDs(ctx, schemaBytes, schemaLocation)
+		}
 	case suggestions.ModificationTypeErrorNames:
 		overlay, err = errorCodes.BuildErrorCodesOverlay(ctx, schemaLocation)
 	}
@@ -0,0 +1,147 @@
+package updates
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/speakeasy-api/speakeasy/internal/offline"
+
+	"github.com/google/go-github/v63/github"
+	"github.com/hashicorp/go-version"
+)
+
+// MirrorReleases downloads the CLI release for each of versions, "latest" or a
+// version number, and artifactArch into the local mirror at mirrorDir, for use
+// in offline mode. Each release is verified as it would be when installed. It
+// returns the tags of the mirrored releases.
+func MirrorReleases(ctx context.Context, mirrorDir string, versions []string, artifactArch string, timeout int) ([]string, error) {
+	dir := offline.CLIReleasesDir(mirrorDir)
+
+	var mirrored []*github.RepositoryRelease
+	var tags []string
+	for _, v := range versions {
+		release, asset, err := findReleaseToMirror(ctx, v, artifactArch, time.Duration(timeout)*time.Second)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find release %s: %w", v, err)
+		}
+		if release == nil || asset == nil {
+			return nil, fmt.Errorf("no release %s of the CLI for %s", v, artifactArch)
+		}
+
+		tagDir := filepath.Join(dir, release.GetTagName())
+		if err := os.MkdirAll(tagDir, 0o755); err != nil {
+			return nil, err
+		}
+
+		downloadedPath, err := downloadCLI(tagDir, assetURL(release, asset), timeout)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download %s: %w", asset.GetName(), err)
+		}
+
+		// This also mirrors the checksums, and their signature if there is a
+		// public key to check it with.
+		if err := verifyDownload(ctx, tagDir, release, asset, downloadedPath, timeout); err != nil {
+			_ = os.Remove(downloadedPath)
+			return nil, fmt.Errorf("refusing to mirror %s: %w", asset.GetName(), err)
+		}
+
+		// Mirror every signature, so a public key configured later can check them.
+		for _, suffix := range []string{minisignSuffix, cosignSuffix} {
+			if signatureAsset := findAsset(release, checksumsAssetName+suffix); signatureAsset != nil {
+				if _, err := downloadCLI(tagDir, assetURL(release, signatureAsset), timeout); err != nil {
+					return nil, fmt.Errorf("failed to download %s: %w", signatureAsset.GetName(), err)
+				}
+			}
+		}
+
+		mirrored = append(mirrored, release)
+		tags = append(tags, release.GetTagName())
+	}
+
+	if err := writeMirroredReleases(dir, mirrored); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func findReleaseToMirror(ctx context.Context, v, artifactArch string, timeout time.Duration) (*github.RepositoryRelease, *github.ReleaseAsset, error) {
+	if v == "" || v == "latest" {
+		return getLatestRelease(ctx, artifactArch, timeout)
+	}
+
+	ver, err := version.NewVersion(v)
+	if err != nil {
+		return nil, nil, err
+	}
+	return getReleaseForVersion(ctx, *ver, artifactArch, timeout)
+}
+
+// writeMirroredReleases adds releases to the releases.json of the mirror at
+// dir, keeping those mirrored before. Each release only lists the assets that
+// are in the mirror, and releases are sorted newest first, as GitHub lists
+// them.
+func writeMirroredReleases(dir string, releases []*github.RepositoryRelease) error {
+	byTag := map[string]*github.RepositoryRelease{}
+
+	data, err := os.ReadFile(filepath.Join(dir, releasesFileName))
+	if err == nil {
+		var existing []*github.RepositoryRelease
+		if err := json.Unmarshal(data, &existing); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", releasesFileName, err)
+		}
+		for _, release := range existing {
+			byTag[release.GetTagName()] = release
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	for _, release := range releases {
+		byTag[release.GetTagName()] = release
+	}
+
+	all := make([]*github.RepositoryRelease, 0, len(byTag))
+	for tag, release := range byTag {
+		var assets []*github.ReleaseAsset
+		for _, asset := range release.Assets {
+			if _, err := os.Stat(filepath.Join(dir, tag, asset.GetName())); err == nil {
+				assets = append(assets, asset)
+			}
+		}
+
+		mirroredRelease := *release
+		mirroredRelease.Assets = assets
+		all = append(all, &mirroredRelease)
+	}
+
+	slices.SortFunc(all, func(a, b *github.RepositoryRelease) int {
+		aVer, aErr := version.NewVersion(a.GetTagName())
+		bVer, bErr := version.NewVersion(b.GetTagName())
+		switch {
+		case aErr != nil && bErr != nil:
+			return 0
+		case aErr != nil:
+			return 1
+		case bErr != nil:
+			return -1
+		}
+		return bVer.Compare(aVer)
+	})
+
+	data, err = json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, releasesFileName), data, 0o644)
+}
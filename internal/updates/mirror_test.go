@@ -0,0 +1,110 @@
+package updates
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-github/v63/github"
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRelease(tag string, assets ...string) *github.RepositoryRelease {
+	release := &github.RepositoryRelease{TagName: github.String(tag)}
+	for _, name := range assets {
+		release.Assets = append(release.Assets, &github.ReleaseAsset{
+			Name:               github.String(name),
+			BrowserDownloadURL: github.String("https://github.com/speakeasy-api/speakeasy/releases/download/" + tag + "/" + name),
+		})
+	}
+	return release
+}
+
+func writeMirrorAsset(t *testing.T, dir, tag, name string, content []byte) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, tag), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, tag, name), content, 0o644))
+}
+
+func readMirroredReleases(t *testing.T, dir string) []*github.RepositoryRelease {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, releasesFileName))
+	require.NoError(t, err)
+	var releases []*github.RepositoryRelease
+	require.NoError(t, json.Unmarshal(data, &releases))
+	return releases
+}
+
+func TestWriteMirroredReleases(t *testing.T) {
+	dir := t.TempDir()
+	const darwinAsset = "speakeasy_darwin_arm64.zip"
+
+	writeMirrorAsset(t, dir, "v1.2.3", testAssetName, []byte("release"))
+	writeMirrorAsset(t, dir, "v1.2.3", checksumsAssetName, []byte("checksums"))
+	require.NoError(t, writeMirroredReleases(dir, []*github.RepositoryRelease{
+		testRelease("v1.2.3", testAssetName, darwinAsset, checksumsAssetName),
+	}))
+
+	releases := readMirroredReleases(t, dir)
+	require.Len(t, releases, 1)
+	assert.Len(t, releases[0].Assets, 2, "only mirrored assets are listed")
+
+	writeMirrorAsset(t, dir, "v1.2.3", darwinAsset, []byte("release"))
+	writeMirrorAsset(t, dir, "v1.10.0", testAssetName, []byte("release"))
+	require.NoError(t, writeMirroredReleases(dir, []*github.RepositoryRelease{
+		testRelease("v1.2.3", testAssetName, darwinAsset, checksumsAssetName),
+		testRelease("v1.10.0", testAssetName),
+	}))
+
+	releases = readMirroredReleases(t, dir)
+	require.Len(t, releases, 2)
+	assert.Equal(t, "v1.10.0", releases[0].GetTagName(), "newest release comes first")
+	assert.Equal(t, "v1.2.3", releases[1].GetTagName())
+	assert.Len(t, releases[1].Assets, 3)
+}
+
+func TestOfflineMirror(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("SPEAKEASY_UPDATE_PUBLIC_KEY", "")
+
+	mirrorDir := t.TempDir()
+	t.Setenv("SPEAKEASY_OFFLINE", "true")
+	t.Setenv("SPEAKEASY_MIRROR_DIR", mirrorDir)
+
+	ctx := context.Background()
+	v := version.Must(version.NewVersion("1.2.3"))
+
+	_, _, err := getReleaseForVersion(ctx, *v, "linux_amd64", testTimeout)
+	assert.ErrorContains(t, err, "run speakeasy mirror sync")
+
+	dir := filepath.Join(mirrorDir, "cli")
+	writeMirrorAsset(t, dir, "v1.2.3", testAssetName, []byte("release"))
+	writeMirrorAsset(t, dir, "v1.2.3", checksumsAssetName, testChecksums([]byte("release")))
+	require.NoError(t, writeMirroredReleases(dir, []*github.RepositoryRelease{
+		testRelease("v1.2.3", testAssetName, checksumsAssetName),
+	}))
+
+	release, asset, err := getReleaseForVersion(ctx, *v, "linux_amd64", testTimeout)
+	require.NoError(t, err)
+	require.NotNil(t, asset)
+	assert.Equal(t, testAssetName, asset.GetName())
+	assert.Equal(t, filepath.Join(dir, "v1.2.3", testAssetName), assetURL(release, asset))
+
+	latest, _, err := getLatestRelease(ctx, "linux_amd64", testTimeout)
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2.3", latest.GetTagName())
+
+	_, _, err = getReleaseForVersion(ctx, *version.Must(version.NewVersion("1.0.0")), "linux_amd64", testTimeout)
+	assert.ErrorContains(t, err, "isn't in the offline mirror")
+
+	dest := t.TempDir()
+	downloaded, err := downloadCLI(dest, assetURL(release, asset), 10)
+	require.NoError(t, err)
+	assert.NoError(t, verifyDownload(ctx, dest, release, asset, downloaded, 10))
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/speakeasy-api/speakeasy/internal/env"
 	"github.com/speakeasy-api/speakeasy/internal/locks"
 	"github.com/speakeasy-api/speakeasy/internal/log"
+	"github.com/speakeasy-api/speakeasy/internal/offline"
 
 	"github.com/google/go-github/v63/github"
 	"github.com/hashicorp/go-version"
@@ -34,6 +35,7 @@ const (
 	ArtifactArchContextKey         contextKey = "cli-artifact-arch"
 	GitHubReleaseRateLimitingLimit            = time.Second * 60
 	fallbackBaseURL                           = "https://cli-releases.speakeasy.com"
+	releasesFileName                          = "releases.json"
 )
 
 type fallbackDownloadResponse struct {
@@ -113,7 +115,7 @@ func Update(ctx context.Context, currentVersion, artifactArch string, timeout in
 		return "", err
 	}
 
-	return release.GetTagName(), install(artifactArch, asset.GetBrowserDownloadURL(), exPath, timeout)
+	return release.GetTagName(), install(ctx, artifactArch, release, asset, exPath, timeout)
 }
 
 // InstallVersion installs a specific version of the CLI
@@ -162,7 +164,7 @@ func InstallVersion(ctx context.Context, desiredVersion, artifactArch string, ti
 	// It's important that these logs remain. We rely on them as part of `run` output
 	log.From(ctx).PrintfStyled(styles.DimmedItalic, "Downloading Speakeasy version %s\n", desiredVersion)
 
-	return dst, install(artifactArch, asset.GetBrowserDownloadURL(), dst, timeout)
+	return dst, install(ctx, artifactArch, release, asset, dst, timeout)
 }
 
 func getVersionInstallLocation(artifactArch string, v *version.Version) (string, error) {
@@ -190,7 +192,7 @@ func getBinaryName(artifactArch string) string {
 	return binaryName
 }
 
-func install(artifactArch, downloadURL, installLocation string, timeout int) error {
+func install(ctx context.Context, artifactArch string, release *github.RepositoryRelease, asset *github.ReleaseAsset, installLocation string, timeout int) error {
 	dirName, err := os.MkdirTemp("", "speakeasy")
 	if err != nil {
 		return err
@@ -198,11 +200,15 @@ func install(artifactArch, downloadURL, installLocation string, timeout int) err
 
 	defer func() { _ = os.RemoveAll(dirName) }()
 
-	downloadedPath, err := downloadCLI(dirName, downloadURL, timeout)
+	downloadedPath, err := downloadCLI(dirName, assetURL(release, asset), timeout)
 	if err != nil {
 		return fmt.Errorf("you've encountered local network issues, please try again in a few moments: %w", err)
 	}
 
+	if err := verifyDownload(ctx, dirName, release, asset, downloadedPath, timeout); err != nil {
+		return fmt.Errorf("refusing to install %s: %w", asset.GetName(), err)
+	}
+
 	tmpLocation := filepath.Join(dirName, "extracted")
 	if err := os.MkdirAll(tmpLocation, 0o755); err != nil {
 		return err
@@ -308,14 +314,28 @@ func getLatestRelease(ctx context.Context, artifactArch string, timeout time.Dur
 		ClearOnNewVersion: true,
 		Duration:          GitHubReleaseRateLimitingLimit,
 	})
+	if offline.Enabled() {
+		// A cached release may not be in the local mirror.
+		releaseCache = nil
+	}
 
 	cached, err := releaseCache.Get()
 	if err == nil {
 		return cached.Repo, cached.Release, nil
 	}
 
-	releases, _, err := client.Repositories.ListReleases(context.Background(), "speakeasy-api", "speakeasy", nil)
-	if err != nil {
+	var releases []*github.RepositoryRelease
+	if offline.Enabled() {
+		releases, _, err = fetchReleasesFromLocalMirror()
+		if err != nil {
+			return nil, nil, err
+		}
+	} else if mirrorURL := env.UpdateMirrorURL(); mirrorURL != "" {
+		releases, err = fetchReleasesFromMirror(mirrorURL, timeout)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else if releases, _, err = client.Repositories.ListReleases(context.Background(), "speakeasy-api", "speakeasy", nil); err != nil {
 		var fallbackErr error
 		releases, fallbackErr = fetchReleasesFromFallback(timeout)
 		if fallbackErr != nil {
@@ -355,9 +375,32 @@ func getReleaseForVersion(ctx context.Context, version version.Version, artifact
 		ClearOnNewVersion: true,
 		Duration:          GitHubReleaseRateLimitingLimit,
 	})
+	if offline.Enabled() {
+		// A cached release may not be in the local mirror.
+		cache = nil
+	}
 	var release *github.RepositoryRelease
 	if cachedRelease, err := cache.Get(); err == nil {
 		release = cachedRelease
+	} else if offline.Enabled() {
+		releases, dir, err := fetchReleasesFromLocalMirror()
+		if err != nil {
+			return nil, nil, err
+		}
+		release = findRelease(releases, tag)
+		if release == nil {
+			return nil, nil, fmt.Errorf("release %s isn't in the offline mirror at %s, run speakeasy mirror sync --version %s with network access to add it", tag, dir, version.String())
+		}
+	} else if mirrorURL := env.UpdateMirrorURL(); mirrorURL != "" {
+		releases, err := fetchReleasesFromMirror(mirrorURL, timeout)
+		if err != nil {
+			return nil, nil, err
+		}
+		release = findRelease(releases, tag)
+		if release == nil {
+			return nil, nil, fmt.Errorf("release %s not found in mirror %s", tag, mirrorURL)
+		}
+		_ = cache.Store(release)
 	} else {
 		release, _, err = client.Repositories.GetReleaseByTag(context.Background(), "speakeasy-api", "speakeasy", tag)
 		if err != nil {
@@ -366,12 +409,7 @@ func getReleaseForVersion(ctx context.Context, version version.Version, artifact
 			if fallbackErr != nil {
 				return nil, nil, err // return original error
 			}
-			for _, r := range releases {
-				if r.GetTagName() == tag {
-					release = r
-					break
-				}
-			}
+			release = findRelease(releases, tag)
 			if release == nil {
 				return nil, nil, fmt.Errorf("release %s not found", tag)
 			}
@@ -391,14 +429,28 @@ func getReleaseForVersion(ctx context.Context, version version.Version, artifact
 	return nil, nil, nil
 }
 
+func findRelease(releases []*github.RepositoryRelease, tag string) *github.RepositoryRelease {
+	for _, r := range releases {
+		if r.GetTagName() == tag {
+			return r
+		}
+	}
+	return nil
+}
+
 func downloadCLI(dest, link string, timeout int) (string, error) {
+	if offline.Enabled() {
+		return copyFromLocalMirror(dest, link)
+	}
+
 	downloadURL := link
 
 	c := &http.Client{
 		Timeout: time.Duration(timeout) * time.Second,
 	}
 	resp, err := c.Get(downloadURL)
-	if err != nil || resp.StatusCode != http.StatusOK {
+	// A mirror is used instead of GitHub and the caching proxy, not as well as them.
+	if (err != nil || resp.StatusCode != http.StatusOK) && env.UpdateMirrorURL() == "" {
 		if resp != nil {
 			resp.Body.Close()
 		}
@@ -416,6 +468,9 @@ func downloadCLI(dest, link string, timeout int) (string, error) {
 			return "", err
 		}
 	}
+	if err != nil {
+		return "", err
+	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
@@ -438,25 +493,105 @@ func downloadCLI(dest, link string, timeout int) (string, error) {
 // fetchReleasesFromFallback calls the caching proxy's list endpoint and
 // unmarshals the response into GitHub RepositoryRelease objects.
 func fetchReleasesFromFallback(timeout time.Duration) ([]*github.RepositoryRelease, error) {
+	return fetchReleases("fallback", fallbackBaseURL+"?action=list", timeout)
+}
+
+// fetchReleasesFromMirror lists the releases of a mirror set by
+// SPEAKEASY_UPDATE_MIRROR_URL. A mirror serves the GitHub releases of the CLI
+// as JSON at <mirror>/releases.json, and their assets at <mirror>/<tag>/<asset>.
+func fetchReleasesFromMirror(mirrorURL string, timeout time.Duration) ([]*github.RepositoryRelease, error) {
+	return fetchReleases("mirror", strings.TrimSuffix(mirrorURL, "/")+"/"+releasesFileName, timeout)
+}
+
+// fetchReleasesFromLocalMirror lists the releases in the local mirror used in
+// offline mode, returning the directory they're mirrored in.
+func fetchReleasesFromLocalMirror() ([]*github.RepositoryRelease, string, error) {
+	dir, err := localMirrorDir()
+	if err != nil {
+		return nil, "", err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, releasesFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, dir, fmt.Errorf("no CLI releases in the offline mirror at %s, run speakeasy mirror sync with network access to add them", dir)
+	} else if err != nil {
+		return nil, dir, err
+	}
+
+	var releases []*github.RepositoryRelease
+	if err := json.Unmarshal(data, &releases); err != nil {
+		return nil, dir, fmt.Errorf("offline mirror list decode: %w", err)
+	}
+
+	return releases, dir, nil
+}
+
+func localMirrorDir() (string, error) {
+	mirrorDir, err := offline.MirrorDir()
+	if err != nil {
+		return "", err
+	}
+	return offline.CLIReleasesDir(mirrorDir), nil
+}
+
+// copyFromLocalMirror copies an asset of the local mirror to dest, as
+// downloadCLI does when online.
+func copyFromLocalMirror(dest, path string) (string, error) {
+	src, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("%s isn't in the offline mirror, run speakeasy mirror sync with network access to add it", filepath.Base(path))
+	} else if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	copied, err := os.Create(filepath.Join(dest, filepath.Base(path)))
+	if err != nil {
+		return "", err
+	}
+	defer copied.Close()
+
+	if _, err := io.Copy(copied, src); err != nil {
+		return "", err
+	}
+
+	return copied.Name(), nil
+}
+
+func fetchReleases(source, listURL string, timeout time.Duration) ([]*github.RepositoryRelease, error) {
 	c := &http.Client{Timeout: timeout}
-	resp, err := c.Get(fallbackBaseURL + "?action=list")
+	resp, err := c.Get(listURL)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("fallback list failed: %s", resp.Status)
+		return nil, fmt.Errorf("%s list failed: %s", source, resp.Status)
 	}
 
 	var releases []*github.RepositoryRelease
 	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
-		return nil, fmt.Errorf("fallback list decode: %w", err)
+		return nil, fmt.Errorf("%s list decode: %w", source, err)
 	}
 
 	return releases, nil
 }
 
+// assetURL returns where to download a release asset from, the mirror if
+// there is one, or its path in the local mirror in offline mode.
+func assetURL(release *github.RepositoryRelease, asset *github.ReleaseAsset) string {
+	if offline.Enabled() {
+		if dir, err := localMirrorDir(); err == nil {
+			return filepath.Join(dir, release.GetTagName(), asset.GetName())
+		}
+	}
+	if mirrorURL := env.UpdateMirrorURL(); mirrorURL != "" {
+		return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(mirrorURL, "/"), url.PathEscape(release.GetTagName()), url.PathEscape(asset.GetName()))
+	}
+	return asset.GetBrowserDownloadURL()
+}
+
 // getFallbackDownloadURL parses a GitHub release asset URL to extract the tag
 // and asset name, then asks the caching proxy for a signed download URL.
 func getFallbackDownloadURL(link string, timeout time.Duration) (string, error) {
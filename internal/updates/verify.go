@@ -0,0 +1,274 @@
+package updates
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/speakeasy-api/speakeasy/internal/env"
+	"github.com/speakeasy-api/speakeasy/internal/log"
+
+	"github.com/google/go-github/v63/github"
+	"github.com/hashicorp/go-version"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	checksumsAssetName = "checksums.txt"
+	minisignSuffix     = ".minisig"
+	cosignSuffix       = ".sig"
+)
+
+// releasePublicKey is the minisign key releases are signed with, set at build
+// time with -X by .goreleaser.yaml. SPEAKEASY_UPDATE_PUBLIC_KEY takes
+// precedence over it.
+var releasePublicKey string
+
+// firstSignedVersion is the first release published with a signature of its
+// checksums. Older releases can only be checked against their checksums.
+var firstSignedVersion = version.Must(version.NewVersion("1.760.0"))
+
+// ErrVerificationFailed is returned when a downloaded release doesn't match
+// its checksum or signature.
+var ErrVerificationFailed = errors.New("release verification failed")
+
+// verifyDownload checks a downloaded asset against the checksums published
+// with its release, and the checksums against their signature when there is a
+// public key to check it with and the release was signed, warning when either
+// is missing. Nothing is installed unless it passes.
+func verifyDownload(ctx context.Context, dest string, release *github.RepositoryRelease, asset *github.ReleaseAsset, downloadedPath string, timeout int) error {
+	checksumsAsset := findAsset(release, checksumsAssetName)
+	if checksumsAsset == nil {
+		return fmt.Errorf("%w: release %s has no %s", ErrVerificationFailed, release.GetTagName(), checksumsAssetName)
+	}
+
+	checksumsPath, err := downloadCLI(dest, assetURL(release, checksumsAsset), timeout)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", checksumsAssetName, err)
+	}
+	checksums, err := os.ReadFile(checksumsPath)
+	if err != nil {
+		return err
+	}
+
+	if publicKey := getPublicKey(); publicKey == "" {
+		log.From(ctx).Warnf("Not checking the signature of release %s: this build of the CLI has no release public key, set SPEAKEASY_UPDATE_PUBLIC_KEY to check it", release.GetTagName())
+	} else if !isSigned(release) {
+		log.From(ctx).Warnf("Not checking the signature of release %s: releases before v%s aren't signed", release.GetTagName(), firstSignedVersion)
+	} else if err := verifyChecksumsSignature(dest, release, checksums, publicKey, timeout); err != nil {
+		return err
+	}
+
+	return verifyChecksum(checksums, asset.GetName(), downloadedPath)
+}
+
+// isSigned reports whether a release was published with a signature, which
+// is assumed of any release whose version can't be parsed.
+func isSigned(release *github.RepositoryRelease) bool {
+	v, err := version.NewVersion(release.GetTagName())
+	return err != nil || !v.LessThan(firstSignedVersion)
+}
+
+func getPublicKey() string {
+	if publicKey := env.UpdatePublicKey(); publicKey != "" {
+		return publicKey
+	}
+	return releasePublicKey
+}
+
+func verifyChecksumsSignature(dest string, release *github.RepositoryRelease, checksums []byte, publicKey string, timeout int) error {
+	isCosignKey := strings.HasPrefix(strings.TrimSpace(publicKey), "-----BEGIN")
+
+	signatureName := checksumsAssetName + minisignSuffix
+	if isCosignKey {
+		signatureName = checksumsAssetName + cosignSuffix
+	}
+
+	signatureAsset := findAsset(release, signatureName)
+	if signatureAsset == nil {
+		return fmt.Errorf("%w: release %s has no %s", ErrVerificationFailed, release.GetTagName(), signatureName)
+	}
+
+	signaturePath, err := downloadCLI(dest, assetURL(release, signatureAsset), timeout)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", signatureName, err)
+	}
+	signature, err := os.ReadFile(signaturePath)
+	if err != nil {
+		return err
+	}
+
+	if isCosignKey {
+		err = verifyCosign(publicKey, checksums, signature)
+	} else {
+		err = verifyMinisign(publicKey, checksums, signature)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s of release %s: %w", ErrVerificationFailed, signatureName, release.GetTagName(), err)
+	}
+	return nil
+}
+
+// verifyChecksum checks the SHA-256 of the file at path against its entry in
+// a checksums file of "<sha256>  <name>" lines, as written by sha256sum.
+func verifyChecksum(checksums []byte, name, path string) error {
+	var expected string
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			expected = strings.ToLower(fields[0])
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if expected == "" {
+		return fmt.Errorf("%w: no checksum for %s", ErrVerificationFailed, name)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+
+	if actual := hex.EncodeToString(h.Sum(nil)); actual != expected {
+		return fmt.Errorf("%w: checksum of %s is %s, expected %s", ErrVerificationFailed, name, actual, expected)
+	}
+	return nil
+}
+
+// verifyMinisign verifies a minisign signature of message, including its
+// trusted comment. publicKey is either the base64 key or the contents of a
+// minisign public key file.
+func verifyMinisign(publicKey string, message, signature []byte) error {
+	keyBytes, err := base64.StdEncoding.DecodeString(lastLine(publicKey))
+	if err != nil || len(keyBytes) != 42 || string(keyBytes[:2]) != "Ed" {
+		return fmt.Errorf("invalid minisign public key")
+	}
+	keyID, key := keyBytes[2:10], ed25519.PublicKey(keyBytes[10:])
+
+	lines := strings.Split(strings.TrimSpace(string(signature)), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[2], "trusted comment: ") {
+		return fmt.Errorf("invalid minisign signature")
+	}
+
+	sigBytes, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(sigBytes) != 74 {
+		return fmt.Errorf("invalid minisign signature")
+	}
+	algorithm, sigKeyID, sig := string(sigBytes[:2]), sigBytes[2:10], sigBytes[10:]
+
+	if !bytes.Equal(keyID, sigKeyID) {
+		return fmt.Errorf("signed with key %X, expected %X", reverse(sigKeyID), reverse(keyID))
+	}
+
+	switch algorithm {
+	case "Ed":
+	case "ED":
+		// Prehashed, the default since minisign 0.8.
+		digest := blake2b.Sum512(message)
+		message = digest[:]
+	default:
+		return fmt.Errorf("unsupported minisign signature algorithm %s", algorithm)
+	}
+
+	if !ed25519.Verify(key, message, sig) {
+		return fmt.Errorf("signature doesn't match")
+	}
+
+	trustedComment := strings.TrimSuffix(strings.TrimPrefix(lines[2], "trusted comment: "), "\r")
+	globalSig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil || !ed25519.Verify(key, append(bytes.Clone(sig), trustedComment...), globalSig) {
+		return fmt.Errorf("trusted comment signature doesn't match")
+	}
+
+	return nil
+}
+
+// verifyCosign verifies a signature made with cosign sign-blob and a key pair,
+// which is the base64 signature of message with the PEM encoded publicKey.
+func verifyCosign(publicKey string, message, signature []byte) error {
+	block, _ := pem.Decode([]byte(strings.TrimSpace(publicKey)))
+	if block == nil {
+		return fmt.Errorf("invalid cosign public key")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("invalid cosign public key: %w", err)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return fmt.Errorf("invalid cosign signature: %w", err)
+	}
+
+	switch key := key.(type) {
+	case *ecdsa.PublicKey:
+		hash := crypto.SHA256
+		switch key.Curve {
+		case elliptic.P384():
+			hash = crypto.SHA384
+		case elliptic.P521():
+			hash = crypto.SHA512
+		}
+		h := hash.New()
+		h.Write(message)
+		if !ecdsa.VerifyASN1(key, h.Sum(nil), sig) {
+			return fmt.Errorf("signature doesn't match")
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, message, sig) {
+			return fmt.Errorf("signature doesn't match")
+		}
+	default:
+		return fmt.Errorf("unsupported cosign public key type %T", key)
+	}
+
+	return nil
+}
+
+func findAsset(release *github.RepositoryRelease, name string) *github.ReleaseAsset {
+	for _, asset := range release.Assets {
+		if asset.GetName() == name {
+			return asset
+		}
+	}
+	return nil
+}
+
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// reverse returns a reversed copy of a minisign key ID, which is displayed
+// little-endian.
+func reverse(b []byte) []byte {
+	r := make([]byte, len(b))
+	for i := range b {
+		r[len(b)-1-i] = b[i]
+	}
+	return r
+}
@@ -0,0 +1,194 @@
+package updates
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-github/v63/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+)
+
+const testAssetName = "speakeasy_linux_amd64.zip"
+
+func testChecksums(content []byte) []byte {
+	sum := sha256.Sum256(content)
+	return fmt.Appendf(nil, "%x  speakeasy_darwin_arm64.zip\n%s  %s\n", sha256.Sum256([]byte("other")), hex.EncodeToString(sum[:]), testAssetName)
+}
+
+// minisign signs message in minisign's prehashed format.
+func minisign(t *testing.T, key ed25519.PrivateKey, keyID []byte, message []byte) []byte {
+	t.Helper()
+
+	digest := blake2b.Sum512(message)
+	sig := ed25519.Sign(key, digest[:])
+	trustedComment := "timestamp:1700000000\tfile:checksums.txt\thashed"
+	globalSig := ed25519.Sign(key, append(append([]byte{}, sig...), trustedComment...))
+
+	sigBytes := append(append([]byte("ED"), keyID...), sig...)
+	return fmt.Appendf(nil, "untrusted comment: signature from minisign secret key\n%s\ntrusted comment: %s\n%s\n",
+		base64.StdEncoding.EncodeToString(sigBytes), trustedComment, base64.StdEncoding.EncodeToString(globalSig))
+}
+
+func minisignPublicKey(key ed25519.PublicKey, keyID []byte) string {
+	return "untrusted comment: minisign public key\n" + base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), keyID...), key...))
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	path := filepath.Join(t.TempDir(), testAssetName)
+	require.NoError(t, os.WriteFile(path, []byte("release"), 0o644))
+
+	assert.NoError(t, verifyChecksum(testChecksums([]byte("release")), testAssetName, path))
+
+	err := verifyChecksum(testChecksums([]byte("tampered")), testAssetName, path)
+	assert.ErrorIs(t, err, ErrVerificationFailed)
+	assert.ErrorContains(t, err, "checksum of "+testAssetName)
+
+	err = verifyChecksum([]byte("abc  speakeasy_windows_amd64.zip\n"), testAssetName, path)
+	assert.ErrorIs(t, err, ErrVerificationFailed)
+	assert.ErrorContains(t, err, "no checksum for "+testAssetName)
+}
+
+func TestVerifyMinisign(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	keyID := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+
+	message := []byte("checksums")
+	signature := minisign(t, private, keyID, message)
+
+	assert.NoError(t, verifyMinisign(minisignPublicKey(public, keyID), message, signature))
+	assert.ErrorContains(t, verifyMinisign(minisignPublicKey(public, keyID), []byte("tampered"), signature), "signature doesn't match")
+
+	otherPublic, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	assert.ErrorContains(t, verifyMinisign(minisignPublicKey(otherPublic, keyID), message, signature), "signature doesn't match")
+	assert.ErrorContains(t, verifyMinisign(minisignPublicKey(public, []byte{8, 7, 6, 5, 4, 3, 2, 1}), message, signature), "signed with key 0807060504030201")
+	assert.ErrorContains(t, verifyMinisign("not a key", message, signature), "invalid minisign public key")
+}
+
+func TestVerifyCosign(t *testing.T) {
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	require.NoError(t, err)
+	publicKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	message := []byte("checksums")
+	digest := sha256.Sum256(message)
+	sig, err := ecdsa.SignASN1(rand.Reader, private, digest[:])
+	require.NoError(t, err)
+	signature := []byte(base64.StdEncoding.EncodeToString(sig))
+
+	assert.NoError(t, verifyCosign(publicKey, message, signature))
+	assert.ErrorContains(t, verifyCosign(publicKey, []byte("tampered"), signature), "signature doesn't match")
+	assert.ErrorContains(t, verifyCosign("not a key", message, signature), "invalid cosign public key")
+}
+
+func TestVerifyDownloadFromMirror(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	keyID := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+
+	tag := "v" + firstSignedVersion.String()
+	checksums := testChecksums([]byte("release"))
+	files := map[string][]byte{
+		"/" + tag + "/" + checksumsAssetName:                  checksums,
+		"/" + tag + "/" + checksumsAssetName + minisignSuffix: minisign(t, private, keyID, checksums),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(content)
+	}))
+	defer server.Close()
+
+	t.Setenv("SPEAKEASY_UPDATE_MIRROR_URL", server.URL)
+	t.Setenv("SPEAKEASY_UPDATE_PUBLIC_KEY", minisignPublicKey(public, keyID))
+
+	asset := &github.ReleaseAsset{Name: github.String(testAssetName)}
+	release := &github.RepositoryRelease{
+		TagName: github.String(tag),
+		Assets: []*github.ReleaseAsset{
+			asset,
+			{Name: github.String(checksumsAssetName)},
+			{Name: github.String(checksumsAssetName + minisignSuffix)},
+		},
+	}
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	downloaded := filepath.Join(dir, "downloaded.zip")
+	require.NoError(t, os.WriteFile(downloaded, []byte("release"), 0o644))
+	assert.NoError(t, verifyDownload(ctx, dir, release, asset, downloaded, 10))
+
+	require.NoError(t, os.WriteFile(downloaded, []byte("tampered"), 0o644))
+	assert.ErrorIs(t, verifyDownload(ctx, dir, release, asset, downloaded, 10), ErrVerificationFailed)
+
+	files["/"+tag+"/"+checksumsAssetName] = testChecksums([]byte("tampered"))
+	err = verifyDownload(ctx, dir, release, asset, downloaded, 10)
+	assert.ErrorIs(t, err, ErrVerificationFailed)
+	assert.ErrorContains(t, err, "signature doesn't match")
+
+	unsigned := &github.RepositoryRelease{TagName: release.TagName, Assets: release.Assets[:2]}
+	err = verifyDownload(ctx, dir, unsigned, asset, downloaded, 10)
+	assert.ErrorIs(t, err, ErrVerificationFailed)
+	assert.ErrorContains(t, err, "has no checksums.txt.minisig")
+}
+
+func TestVerifyDownloadBeforeSigning(t *testing.T) {
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	files := map[string][]byte{
+		"/v1.2.3/" + checksumsAssetName: testChecksums([]byte("release")),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(content)
+	}))
+	defer server.Close()
+
+	t.Setenv("SPEAKEASY_UPDATE_MIRROR_URL", server.URL)
+	t.Setenv("SPEAKEASY_UPDATE_PUBLIC_KEY", "")
+	embedded := releasePublicKey
+	releasePublicKey = minisignPublicKey(public, []byte{1, 2, 3, 4, 5, 6, 7, 8})
+	defer func() { releasePublicKey = embedded }()
+
+	asset := &github.ReleaseAsset{Name: github.String(testAssetName)}
+	release := &github.RepositoryRelease{
+		TagName: github.String("v1.2.3"),
+		Assets:  []*github.ReleaseAsset{asset, {Name: github.String(checksumsAssetName)}},
+	}
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	downloaded := filepath.Join(dir, "downloaded.zip")
+	require.NoError(t, os.WriteFile(downloaded, []byte("release"), 0o644))
+	assert.NoError(t, verifyDownload(ctx, dir, release, asset, downloaded, 10))
+
+	require.NoError(t, os.WriteFile(downloaded, []byte("tampered"), 0o644))
+	assert.ErrorIs(t, verifyDownload(ctx, dir, release, asset, downloaded, 10), ErrVerificationFailed)
+}
@@ -71,7 +71,7 @@ func OpenInBrowser(path string) error {
 	return err
 }
 
-var FlagsToIgnore = []string{"help", "version", "logLevel"}
+var FlagsToIgnore = []string{"help", "version", "logLevel", "offline"}
 
 func CreateDirectory(filename string) error {
 	dir := filepath.Dir(filename)
@@ -208,6 +208,11 @@ func GetFullCommandString(cmd *cobra.Command) string {
 func GetCommandParts(cmd *cobra.Command) []string {
 	parts := strings.Split(cmd.CommandPath(), " ")
 	for _, f := range getSetFlags(cmd.Flags()) {
+		// Offline mode is passed on by SPEAKEASY_OFFLINE, which versions
+		// without the flag ignore.
+		if f.Name == "offline" {
+			continue
+		}
 		fval := f.Value.String()
 		if f.Value.Type() == "stringSlice" {
 			fval = fval[1 : len(fval)-1] // Remove brackets
@@ -0,0 +1,228 @@
+package validation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/speakeasy-api/openapi-generation/v2/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultBaselinePath is where the lint baseline of a project is kept,
+// relative to its root. speakeasy run applies it when it exists.
+const DefaultBaselinePath = ".speakeasy/lint-baseline.json"
+
+// Baseline is a recorded set of known lint findings of the documents of a
+// project, which are left out when linting so only new findings are reported.
+type Baseline struct {
+	// Documents holds the findings of each document, keyed by DocumentKey.
+	Documents map[string]*DocumentBaseline `json:"documents"`
+}
+
+// DocumentBaseline is the recorded set of known lint findings of a document.
+type DocumentBaseline struct {
+	Findings []BaselineFinding `json:"findings"`
+}
+
+// BaselineFinding identifies a finding by its rule, the JSON pointer of the
+// node it was found at and a fingerprint of both and its message, so it
+// still matches after unrelated changes move it to another line.
+type BaselineFinding struct {
+	Rule        string `json:"rule"`
+	Pointer     string `json:"pointer"`
+	Fingerprint string `json:"fingerprint"`
+	Message     string `json:"message"`
+}
+
+// LoadBaseline reads a baseline written by Baseline.Write. The error wraps
+// os.ErrNotExist if there is no baseline at path.
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lint baseline: %w", err)
+	}
+
+	b := Baseline{Documents: map[string]*DocumentBaseline{}}
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse lint baseline %s: %w", path, err)
+	}
+	return &b, nil
+}
+
+// LoadProjectBaseline reads the baseline at DefaultBaselinePath in
+// projectDir, if there is one.
+func LoadProjectBaseline(projectDir string) (*Baseline, error) {
+	b, err := LoadBaseline(filepath.Join(projectDir, DefaultBaselinePath))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return b, err
+}
+
+// DocumentKey identifies the document at schemaPath in a baseline: its
+// slash-separated path relative to projectDir, or its URL if it is remote.
+func DocumentKey(projectDir, schemaPath string) string {
+	if strings.Contains(schemaPath, "://") {
+		return schemaPath
+	}
+
+	abs, err := filepath.Abs(schemaPath)
+	if err != nil {
+		return filepath.ToSlash(filepath.Clean(schemaPath))
+	}
+	if projectAbs, err := filepath.Abs(projectDir); err == nil {
+		if rel, err := filepath.Rel(projectAbs, abs); err == nil {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(abs)
+}
+
+// Document returns the known findings of the document with key, or nil if
+// there are none.
+func (b *Baseline) Document(key string) *DocumentBaseline {
+	if b == nil {
+		return nil
+	}
+	return b.Documents[key]
+}
+
+// SetDocument replaces the known findings of the document with key, keeping
+// those of other documents.
+func (b *Baseline) SetDocument(key string, d *DocumentBaseline) {
+	if b.Documents == nil {
+		b.Documents = map[string]*DocumentBaseline{}
+	}
+	b.Documents[key] = d
+}
+
+// NewDocumentBaseline records the findings of a document.
+func NewDocumentBaseline(schema []byte, errs []error) *DocumentBaseline {
+	pointers := indexPointers(schema)
+
+	d := &DocumentBaseline{Findings: []BaselineFinding{}}
+	for _, err := range errs {
+		d.Findings = append(d.Findings, newBaselineFinding(err, pointers))
+	}
+	sort.SliceStable(d.Findings, func(i, j int) bool {
+		if d.Findings[i].Pointer != d.Findings[j].Pointer {
+			return d.Findings[i].Pointer < d.Findings[j].Pointer
+		}
+		return d.Findings[i].Rule < d.Findings[j].Rule
+	})
+	return d
+}
+
+// Write writes the baseline to path, creating its directory if needed.
+func (b *Baseline) Write(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create lint baseline directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write lint baseline: %w", err)
+	}
+	return nil
+}
+
+// Filter returns the findings of a document that aren't in its baseline, and
+// how many were left out. Each finding in the baseline matches once, so new
+// occurrences of a known finding are still reported.
+func (d *DocumentBaseline) Filter(schema []byte, errs []error) ([]error, int) {
+	if d == nil || len(d.Findings) == 0 {
+		return errs, 0
+	}
+
+	known := map[string]int{}
+	for _, finding := range d.Findings {
+		known[finding.Fingerprint]++
+	}
+
+	pointers := indexPointers(schema)
+
+	var filtered []error
+	for _, err := range errs {
+		fingerprint := newBaselineFinding(err, pointers).Fingerprint
+		if known[fingerprint] > 0 {
+			known[fingerprint]--
+			continue
+		}
+		filtered = append(filtered, err)
+	}
+	return filtered, len(errs) - len(filtered)
+}
+
+func newBaselineFinding(err error, pointers map[[2]int]string) BaselineFinding {
+	var rule, message string
+	var line, column int
+
+	if vErr := errors.GetValidationErr(err); vErr != nil {
+		rule, message = vErr.Rule, vErr.Message
+		line, column = vErr.GetLineNumber(), vErr.GetColumnNumber()
+	} else if uErr := errors.GetUnsupportedErr(err); uErr != nil {
+		rule, message = "unsupported", uErr.Error()
+		line, column = uErr.GetLineNumber(), uErr.GetColumnNumber()
+	} else {
+		message = err.Error()
+	}
+
+	finding := BaselineFinding{
+		Rule:    rule,
+		Pointer: pointers[[2]int{line, column}],
+		Message: message,
+	}
+	sum := sha256.Sum256([]byte(finding.Rule + "\x00" + finding.Pointer + "\x00" + finding.Message))
+	finding.Fingerprint = hex.EncodeToString(sum[:8])
+	return finding
+}
+
+// indexPointers maps the line and column of each node of a document to its
+// JSON pointer. Where a mapping shares its position with its first key, the
+// key wins, as findings are reported at keys.
+func indexPointers(schema []byte) map[[2]int]string {
+	pointers := map[[2]int]string{}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(schema, &root); err != nil || len(root.Content) == 0 {
+		return pointers
+	}
+
+	var walk func(node *yaml.Node, pointer string)
+	walk = func(node *yaml.Node, pointer string) {
+		position := [2]int{node.Line, node.Column}
+		if _, ok := pointers[position]; !ok {
+			pointers[position] = pointer
+		}
+
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key := node.Content[i]
+				child := pointer + "/" + escapePointerToken(key.Value)
+				pointers[[2]int{key.Line, key.Column}] = child
+				walk(node.Content[i+1], child)
+			}
+		case yaml.SequenceNode:
+			for i, item := range node.Content {
+				walk(item, pointer+"/"+strconv.Itoa(i))
+			}
+		}
+	}
+	walk(root.Content[0], "")
+
+	return pointers
+}
+
+func escapePointerToken(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
@@ -0,0 +1,142 @@
+package validation
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/speakeasy-api/openapi-generation/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const baselineSchema = `openapi: 3.1.0
+info:
+  title: Pets
+  version: 1.0.0
+paths:
+  /pets/{petId}:
+    get:
+      operationId: getPet
+      responses:
+        "200":
+          description: OK
+`
+
+// The same document with a new operation above the known findings
+const baselineSchemaChanged = `openapi: 3.1.0
+info:
+  title: Pets
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: OK
+  /pets/{petId}:
+    get:
+      operationId: getPet
+      responses:
+        "200":
+          description: OK
+`
+
+func finding(rule, message string, line, column int) error {
+	return &errors.ValidationError{
+		Severity: errors.SeverityWarn,
+		Rule:     rule,
+		Message:  message,
+		Node:     &yaml.Node{Line: line, Column: column},
+		Cause:    fmt.Errorf("%s", message),
+	}
+}
+
+func TestBaseline(t *testing.T) {
+	t.Parallel()
+
+	b := &Baseline{}
+	b.SetDocument("openapi.yaml", NewDocumentBaseline([]byte(baselineSchema), []error{
+		finding("operation-description", "operation is missing a description", 7, 5),
+		finding("operation-tags", "operation is missing tags", 7, 5),
+		fmt.Errorf("failed to resolve reference"),
+	}))
+
+	path := filepath.Join(t.TempDir(), ".speakeasy", "lint-baseline.json")
+	require.NoError(t, b.Write(path))
+	b, err := LoadBaseline(path)
+	require.NoError(t, err)
+
+	d := b.Document("openapi.yaml")
+	require.NotNil(t, d)
+	require.Len(t, d.Findings, 3)
+	assert.Equal(t, "", d.Findings[0].Pointer)
+	assert.Equal(t, "/paths/~1pets~1{petId}/get", d.Findings[1].Pointer)
+	assert.Equal(t, "operation-description", d.Findings[1].Rule)
+	assert.Equal(t, "/paths/~1pets~1{petId}/get", d.Findings[2].Pointer)
+	assert.Equal(t, "operation-tags", d.Findings[2].Rule)
+
+	// Known findings moved to another line are left out, new ones aren't
+	errs, baselined := d.Filter([]byte(baselineSchemaChanged), []error{
+		finding("operation-description", "operation is missing a description", 7, 5),
+		finding("operation-tags", "operation is missing tags", 7, 5),
+		finding("operation-description", "operation is missing a description", 13, 5),
+		finding("operation-tags", "operation is missing tags", 13, 5),
+		fmt.Errorf("failed to resolve reference"),
+	})
+	assert.Equal(t, 3, baselined)
+	assert.Equal(t, []error{
+		finding("operation-description", "operation is missing a description", 7, 5),
+		finding("operation-tags", "operation is missing tags", 7, 5),
+	}, errs)
+
+	// Findings of one document don't hide those of another
+	errs, baselined = b.Document("other.yaml").Filter([]byte(baselineSchema), []error{
+		finding("operation-tags", "operation is missing tags", 7, 5),
+	})
+	assert.Zero(t, baselined)
+	assert.Len(t, errs, 1)
+}
+
+func TestBaselineSetDocument(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "lint-baseline.json")
+	b := &Baseline{}
+	b.SetDocument("a.yaml", NewDocumentBaseline([]byte(baselineSchema), []error{finding("operation-tags", "operation is missing tags", 7, 5)}))
+	require.NoError(t, b.Write(path))
+
+	// Updating one document keeps the findings of the others
+	b, err := LoadBaseline(path)
+	require.NoError(t, err)
+	b.SetDocument("b.yaml", NewDocumentBaseline([]byte(baselineSchema), nil))
+	require.NoError(t, b.Write(path))
+
+	b, err = LoadBaseline(path)
+	require.NoError(t, err)
+	assert.Len(t, b.Document("a.yaml").Findings, 1)
+	assert.Empty(t, b.Document("b.yaml").Findings)
+}
+
+func TestDocumentKey(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	assert.Equal(t, "specs/openapi.yaml", DocumentKey(dir, filepath.Join(dir, "specs", "openapi.yaml")))
+	assert.Equal(t, "openapi.yaml", DocumentKey(dir, filepath.Join(dir, "specs", "..", "openapi.yaml")))
+	assert.Equal(t, "https://example.com/openapi.yaml", DocumentKey(dir, "https://example.com/openapi.yaml"))
+}
+
+func TestLoadProjectBaseline(t *testing.T) {
+	t.Parallel()
+
+	b, err := LoadProjectBaseline(t.TempDir())
+	require.NoError(t, err)
+	assert.Nil(t, b)
+
+	errs, baselined := b.Document("openapi.yaml").Filter([]byte(baselineSchema), []error{fmt.Errorf("failed to resolve reference")})
+	assert.Len(t, errs, 1)
+	assert.Zero(t, baselined)
+}
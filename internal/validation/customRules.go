@@ -0,0 +1,424 @@
+package validation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/speakeasy-api/jsonpath/pkg/jsonpath"
+	"github.com/speakeasy-api/jsonpath/pkg/jsonpath/config"
+	"gopkg.in/yaml.v3"
+)
+
+// customRulesDir is where organization specific lint rules are loaded from,
+// relative to the working directory. Each YAML file in it holds a set of
+// rules in the format of Spectral rulesets:
+//
+//	rules:
+//	  operation-owner:
+//	    description: Operations must declare an owning team
+//	    severity: error
+//	    given: $.paths.*['get','put','post','delete','patch']
+//	    then:
+//	      field: x-owner
+//	      function: truthy
+const customRulesDir = ".speakeasy/rules"
+
+// casings are the patterns of the case types supported by the casing function.
+var casings = map[string]*regexp.Regexp{
+	"flat":   regexp.MustCompile(`^[a-z][a-z0-9]*$`),
+	"camel":  regexp.MustCompile(`^[a-z][a-z0-9]*(?:[A-Z][a-z0-9]*)*$`),
+	"pascal": regexp.MustCompile(`^(?:[A-Z][a-z0-9]*)+$`),
+	"kebab":  regexp.MustCompile(`^[a-z][a-z0-9]*(?:-[a-z0-9]+)*$`),
+	"cobol":  regexp.MustCompile(`^[A-Z][A-Z0-9]*(?:-[A-Z0-9]+)*$`),
+	"snake":  regexp.MustCompile(`^[a-z][a-z0-9]*(?:_[a-z0-9]+)*$`),
+	"macro":  regexp.MustCompile(`^[A-Z][A-Z0-9]*(?:_[A-Z0-9]+)*$`),
+}
+
+type customRuleset struct {
+	Rules map[string]customRuleDefinition `yaml:"rules"`
+}
+
+type customRuleDefinition struct {
+	Description string                `yaml:"description"`
+	Message     string                `yaml:"message"`
+	Severity    string                `yaml:"severity"`
+	Given       oneOrMany[string]     `yaml:"given"`
+	Then        oneOrMany[customThen] `yaml:"then"`
+}
+
+type customThen struct {
+	Field           string                `yaml:"field"`
+	Function        string                `yaml:"function"`
+	FunctionOptions customFunctionOptions `yaml:"functionOptions"`
+}
+
+type customFunctionOptions struct {
+	Match    string   `yaml:"match"`
+	NotMatch string   `yaml:"notMatch"`
+	Values   []string `yaml:"values"`
+	Min      *int     `yaml:"min"`
+	Max      *int     `yaml:"max"`
+	Type     string   `yaml:"type"`
+}
+
+// oneOrMany decodes either a single value or a list of values, as allowed
+// for the given and then fields of a rule.
+type oneOrMany[T any] []T
+
+func (o *oneOrMany[T]) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.SequenceNode {
+		var many []T
+		if err := value.Decode(&many); err != nil {
+			return err
+		}
+		*o = many
+		return nil
+	}
+
+	var one T
+	if err := value.Decode(&one); err != nil {
+		return err
+	}
+	*o = oneOrMany[T]{one}
+	return nil
+}
+
+type customRule struct {
+	id          string
+	description string
+	message     string
+	severity    string
+	given       []*jsonpath.JSONPath
+	then        []customCheck
+}
+
+type customCheck struct {
+	field    []string
+	function string
+	options  customFunctionOptions
+	match    *regexp.Regexp
+	notMatch *regexp.Regexp
+}
+
+type customViolation struct {
+	rule     string
+	severity string
+	message  string
+	node     *yaml.Node
+	err      error
+}
+
+// loadCustomRules loads the rules defined in the custom rules directory of
+// workingDir, if there is one, in order of file and rule name.
+func loadCustomRules(workingDir string) ([]*customRule, error) {
+	var files []string
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(workingDir, customRulesDir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+
+	var rules []*customRule
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read custom lint rules: %w", err)
+		}
+
+		var ruleset customRuleset
+		if err := yaml.Unmarshal(data, &ruleset); err != nil {
+			return nil, fmt.Errorf("failed to parse custom lint rules in %s: %w", file, err)
+		}
+
+		ids := make([]string, 0, len(ruleset.Rules))
+		for id := range ruleset.Rules {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+
+		for _, id := range ids {
+			rule, err := compileCustomRule(id, ruleset.Rules[id])
+			if err != nil {
+				return nil, fmt.Errorf("invalid custom lint rule %s in %s: %w", id, file, err)
+			}
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules, nil
+}
+
+func compileCustomRule(id string, def customRuleDefinition) (*customRule, error) {
+	rule := &customRule{
+		id:          id,
+		description: def.Description,
+		message:     def.Message,
+		severity:    def.Severity,
+	}
+
+	switch def.Severity {
+	case "", "warn", "warning":
+		rule.severity = "warn"
+	case "error":
+	case "hint", "info":
+		rule.severity = "hint"
+	default:
+		return nil, fmt.Errorf("unknown severity %s, expected error, warn or hint", def.Severity)
+	}
+
+	if len(def.Given) == 0 {
+		return nil, fmt.Errorf("given is required")
+	}
+	for _, given := range def.Given {
+		path, err := jsonpath.NewPath(given, config.WithPropertyNameExtension())
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSONPath %q: %w", given, err)
+		}
+		rule.given = append(rule.given, path)
+	}
+
+	if len(def.Then) == 0 {
+		return nil, fmt.Errorf("then is required")
+	}
+	for _, then := range def.Then {
+		check := customCheck{function: then.Function, options: then.FunctionOptions}
+		if then.Field != "" {
+			check.field = strings.Split(then.Field, ".")
+		}
+
+		var err error
+		switch then.Function {
+		case "truthy", "falsy", "defined", "undefined":
+		case "pattern":
+			if then.FunctionOptions.Match == "" && then.FunctionOptions.NotMatch == "" {
+				return nil, fmt.Errorf("pattern requires match or notMatch")
+			}
+			if then.FunctionOptions.Match != "" {
+				if check.match, err = regexp.Compile(then.FunctionOptions.Match); err != nil {
+					return nil, fmt.Errorf("invalid match pattern: %w", err)
+				}
+			}
+			if then.FunctionOptions.NotMatch != "" {
+				if check.notMatch, err = regexp.Compile(then.FunctionOptions.NotMatch); err != nil {
+					return nil, fmt.Errorf("invalid notMatch pattern: %w", err)
+				}
+			}
+		case "enumeration":
+			if len(then.FunctionOptions.Values) == 0 {
+				return nil, fmt.Errorf("enumeration requires values")
+			}
+		case "length":
+			if then.FunctionOptions.Min == nil && then.FunctionOptions.Max == nil {
+				return nil, fmt.Errorf("length requires min or max")
+			}
+		case "casing":
+			if _, ok := casings[then.FunctionOptions.Type]; !ok {
+				return nil, fmt.Errorf("unknown casing type %q", then.FunctionOptions.Type)
+			}
+		default:
+			return nil, fmt.Errorf("unknown function %q, expected one of truthy, falsy, defined, undefined, pattern, enumeration, length or casing", then.Function)
+		}
+
+		rule.then = append(rule.then, check)
+	}
+
+	return rule, nil
+}
+
+// applyCustomRules returns the violations of rules in the document.
+func applyCustomRules(schema []byte, rules []*customRule) ([]customViolation, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(schema, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse document for custom lint rules: %w", err)
+	}
+
+	var violations []customViolation
+	for _, rule := range rules {
+		violations = append(violations, rule.apply(&root)...)
+	}
+	return violations, nil
+}
+
+func (r *customRule) apply(root *yaml.Node) []customViolation {
+	var violations []customViolation
+	for _, given := range r.given {
+		for _, node := range given.Query(root) {
+			for _, check := range r.then {
+				target := lookupField(node, check.field)
+				err := check.run(target)
+				if err == nil {
+					continue
+				}
+
+				if target == nil {
+					target = node
+				}
+				violations = append(violations, customViolation{
+					rule:     r.id,
+					severity: r.severity,
+					message:  r.format(check, target, err),
+					node:     target,
+					err:      err,
+				})
+			}
+		}
+	}
+	return violations
+}
+
+// format renders the message of a rule, substituting {{error}}, {{value}}
+// and {{property}}. Rules without a message use their description, or the
+// failure of the check.
+func (r *customRule) format(check customCheck, target *yaml.Node, err error) string {
+	message := r.message
+	if message == "" {
+		message = r.description
+	}
+	if message == "" {
+		return err.Error()
+	}
+
+	return strings.NewReplacer(
+		"{{error}}", err.Error(),
+		"{{value}}", target.Value,
+		"{{property}}", strings.Join(check.field, "."),
+	).Replace(message)
+}
+
+// lookupField returns the node at a dot separated path of properties from
+// node, or nil if there is none.
+func lookupField(node *yaml.Node, field []string) *yaml.Node {
+	for _, name := range field {
+		if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+			node = node.Content[0]
+		}
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+
+		var next *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == name {
+				next = node.Content[i+1]
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		node = next
+	}
+	return node
+}
+
+// run returns why the target violates the check, if it does. Functions
+// other than defined, undefined, truthy and falsy only apply to targets that
+// are present.
+func (c customCheck) run(target *yaml.Node) error {
+	name := "value"
+	if len(c.field) > 0 {
+		name = strings.Join(c.field, ".")
+	}
+
+	switch c.function {
+	case "defined":
+		if target == nil {
+			return fmt.Errorf("%s must be defined", name)
+		}
+		return nil
+	case "undefined":
+		if target != nil {
+			return fmt.Errorf("%s must not be defined", name)
+		}
+		return nil
+	case "truthy":
+		if !isTruthy(target) {
+			return fmt.Errorf("%s must be set", name)
+		}
+		return nil
+	case "falsy":
+		if isTruthy(target) {
+			return fmt.Errorf("%s must not be set", name)
+		}
+		return nil
+	}
+
+	if target == nil {
+		return nil
+	}
+
+	switch c.function {
+	case "pattern":
+		if target.Kind != yaml.ScalarNode {
+			return nil
+		}
+		if c.match != nil && !c.match.MatchString(target.Value) {
+			return fmt.Errorf("%q must match the pattern %q", target.Value, c.match)
+		}
+		if c.notMatch != nil && c.notMatch.MatchString(target.Value) {
+			return fmt.Errorf("%q must not match the pattern %q", target.Value, c.notMatch)
+		}
+	case "enumeration":
+		if target.Kind == yaml.ScalarNode && !slices.Contains(c.options.Values, target.Value) {
+			return fmt.Errorf("%q must be one of %s", target.Value, strings.Join(c.options.Values, ", "))
+		}
+	case "length":
+		// Numbers are compared by value, like in Spectral
+		length := float64(len(target.Content))
+		switch target.Kind {
+		case yaml.ScalarNode:
+			length = float64(len([]rune(target.Value)))
+			if tag := target.ShortTag(); tag == "!!int" || tag == "!!float" {
+				length, _ = strconv.ParseFloat(target.Value, 64)
+			}
+		case yaml.MappingNode:
+			length /= 2
+		}
+		if c.options.Min != nil && length < float64(*c.options.Min) {
+			return fmt.Errorf("%s must be at least %d", name, *c.options.Min)
+		}
+		if c.options.Max != nil && length > float64(*c.options.Max) {
+			return fmt.Errorf("%s must be at most %d", name, *c.options.Max)
+		}
+	case "casing":
+		if target.Kind == yaml.ScalarNode && !casings[c.options.Type].MatchString(target.Value) {
+			return fmt.Errorf("%q must be %s case", target.Value, c.options.Type)
+		}
+	}
+
+	return nil
+}
+
+func isTruthy(node *yaml.Node) bool {
+	if node == nil {
+		return false
+	}
+	if node.Kind != yaml.ScalarNode {
+		return true
+	}
+
+	switch node.ShortTag() {
+	case "!!null":
+		return false
+	case "!!bool":
+		v, _ := strconv.ParseBool(node.Value)
+		return v
+	case "!!int", "!!float":
+		v, err := strconv.ParseFloat(node.Value, 64)
+		return err != nil || v != 0
+	}
+	return node.Value != ""
+}
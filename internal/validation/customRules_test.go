@@ -0,0 +1,58 @@
+package validation
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomRules(t *testing.T) {
+	t.Parallel()
+
+	rules, err := loadCustomRules("testdata/customrules")
+	require.NoError(t, err)
+	require.Len(t, rules, 4)
+
+	schema, err := os.ReadFile("testdata/customrules/openapi.yaml")
+	require.NoError(t, err)
+
+	violations, err := applyCustomRules(schema, rules)
+	require.NoError(t, err)
+
+	type violation struct {
+		rule     string
+		severity string
+		line     int
+		message  string
+	}
+	var actual []violation
+	for _, v := range violations {
+		actual = append(actual, violation{v.rule, v.severity, v.node.Line, v.message})
+	}
+
+	assert.Equal(t, []violation{
+		{"operation-owner", "error", 28, "Operations must declare their owning team"},
+		{"operation-owner", "error", 23, "Operations must declare their owning team"},
+		{"pagination-limit", "hint", 18, "schema.maximum of 1000 is too large"},
+		{"paths-kebab-case", "error", 26, `"/petOwners/{ownerId}" must match the pattern "^(/([a-z0-9-]+|\\{[a-zA-Z]+\\}))+$"`},
+		{"tag-casing", "warn", 8, "Tags must be PascalCase"},
+		{"tag-casing", "warn", 8, "Tags must be PascalCase"},
+	}, actual)
+}
+
+func TestCustomRulesNone(t *testing.T) {
+	t.Parallel()
+
+	rules, err := loadCustomRules(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+}
+
+func TestCustomRulesInvalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := loadCustomRules("testdata/invalidrules")
+	assert.ErrorContains(t, err, `invalid custom lint rule operation-owner in testdata/invalidrules/.speakeasy/rules/style.yaml: unknown function "required"`)
+}
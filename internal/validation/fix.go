@@ -0,0 +1,435 @@
+package validation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/speakeasy-api/openapi/overlay"
+	"github.com/speakeasy-api/speakeasy/internal/suggest"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	RuleMissingOperationID   = "operation-operationId"
+	RuleDuplicateOperationID = "operation-operationId-unique"
+	RuleOperationIDCasing    = "operation-operationId-casing"
+	RuleMissingDescription   = "operation-description"
+	RuleUnusedComponent      = "oas3-unused-component"
+)
+
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// componentTypes are the components that can be unused, those referenced
+// by name rather than $ref, like security schemes, are left alone.
+var componentTypes = []string{"schemas", "responses", "parameters", "examples", "requestBodies", "headers", "links", "callbacks", "pathItems"}
+
+// Fix is a lint finding fixed by FixOpenAPI.
+type Fix struct {
+	Rule    string
+	Message string
+	Line    int
+}
+
+type fixOperation struct {
+	path   string
+	method string
+	key    *yaml.Node
+	node   *yaml.Node
+}
+
+// FixOpenAPI fixes the lint findings of an OpenAPI document that can be fixed
+// without judgement: missing, duplicate and inconsistently cased
+// operationIds, operations with a summary but no description, and unused
+// components. It returns the fixed document, which keeps the comments and
+// ordering of the original, and an overlay making the same changes.
+func FixOpenAPI(schema []byte) (*yaml.Node, *overlay.Overlay, []Fix, error) {
+	var original, fixed yaml.Node
+	if err := yaml.Unmarshal(schema, &original); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to parse document: %w", err)
+	}
+	if err := yaml.Unmarshal(schema, &fixed); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to parse document: %w", err)
+	}
+	if len(fixed.Content) == 0 || fixed.Content[0].Kind != yaml.MappingNode {
+		return nil, nil, nil, fmt.Errorf("failed to parse document: not an object")
+	}
+
+	root := fixed.Content[0]
+	operations := collectOperations(root)
+
+	var fixes []Fix
+	fixes = append(fixes, fixOperationIDCasing(root, operations)...)
+	fixes = append(fixes, fixOperationIDs(operations)...)
+	fixes = append(fixes, fixDescriptions(operations)...)
+	fixes = append(fixes, fixUnusedComponents(root)...)
+
+	o, err := overlay.Compare("Lint Fixes Overlay", &original, fixed)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to create overlay: %w", err)
+	}
+
+	return &fixed, o, fixes, nil
+}
+
+func collectOperations(root *yaml.Node) []fixOperation {
+	var operations []fixOperation
+
+	paths := mappingValue(root, "paths")
+	if paths == nil || paths.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		pathItem := paths.Content[i+1]
+		if pathItem.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j+1 < len(pathItem.Content); j += 2 {
+			if !slices.Contains(httpMethods, pathItem.Content[j].Value) || pathItem.Content[j+1].Kind != yaml.MappingNode {
+				continue
+			}
+			operations = append(operations, fixOperation{
+				path:   paths.Content[i].Value,
+				method: pathItem.Content[j].Value,
+				key:    pathItem.Content[j],
+				node:   pathItem.Content[j+1],
+			})
+		}
+	}
+
+	return operations
+}
+
+func (op fixOperation) String() string {
+	return strings.ToUpper(op.method) + " " + op.path
+}
+
+func (op fixOperation) operationID() string {
+	if id := mappingValue(op.node, "operationId"); id != nil {
+		return id.Value
+	}
+	return ""
+}
+
+func (op fixOperation) tags() []string {
+	tags := mappingValue(op.node, "tags")
+	if tags == nil || tags.Kind != yaml.SequenceNode {
+		return nil
+	}
+	var values []string
+	for _, tag := range tags.Content {
+		values = append(values, tag.Value)
+	}
+	return values
+}
+
+// fixOperationIDCasing renames operationIds that aren't in the casing most
+// operationIds use, along with the links referring to them.
+func fixOperationIDCasing(root *yaml.Node, operations []fixOperation) []Fix {
+	casing := dominantCasing(operations)
+
+	var fixes []Fix
+	for _, op := range operations {
+		id := op.operationID()
+		if id == "" || casingOf(id) == "" || casingOf(id) == casing {
+			continue
+		}
+
+		renamed := formatWords(suggest.SplitWords(id), casing)
+		if renamed == id {
+			continue
+		}
+		mappingValue(op.node, "operationId").Value = renamed
+		renameLinkedOperationID(root, id, renamed)
+		fixes = append(fixes, Fix{
+			Rule:    RuleOperationIDCasing,
+			Message: fmt.Sprintf("renamed operationId %s of %s to %s to match the %s case of other operations", id, op, renamed, casing),
+			Line:    op.key.Line,
+		})
+	}
+
+	return fixes
+}
+
+// fixOperationIDs adds operationIds to operations without one and renames
+// all but the first of operations sharing one, naming them after the method
+// suggest operation-ids --local gives them. Links referring to a duplicate
+// operationId keep referring to the first operation.
+func fixOperationIDs(operations []fixOperation) []Fix {
+	casing := dominantCasing(operations)
+
+	taken := map[string]bool{}
+	for _, op := range operations {
+		taken[op.operationID()] = true
+	}
+
+	seen := map[string]bool{}
+	var fixes []Fix
+	for _, op := range operations {
+		id := op.operationID()
+		if id != "" && !seen[id] {
+			seen[id] = true
+			continue
+		}
+
+		derived := formatWords(suggest.OperationIDWords(op.path, op.method, op.tags()), casing)
+		name := derived
+		for n := 2; taken[name]; n++ {
+			name = fmt.Sprintf("%s%d", derived, n)
+		}
+		taken[name] = true
+		seen[name] = true
+
+		if id == "" {
+			op.node.Content = append([]*yaml.Node{suggest.StringNode("operationId"), suggest.StringNode(name)}, op.node.Content...)
+			fixes = append(fixes, Fix{
+				Rule:    RuleMissingOperationID,
+				Message: fmt.Sprintf("added operationId %s to %s", name, op),
+				Line:    op.key.Line,
+			})
+			continue
+		}
+
+		mappingValue(op.node, "operationId").Value = name
+		fixes = append(fixes, Fix{
+			Rule:    RuleDuplicateOperationID,
+			Message: fmt.Sprintf("renamed duplicate operationId %s of %s to %s", id, op, name),
+			Line:    op.key.Line,
+		})
+	}
+
+	return fixes
+}
+
+// fixDescriptions copies the summary of operations without a description.
+func fixDescriptions(operations []fixOperation) []Fix {
+	var fixes []Fix
+	for _, op := range operations {
+		summary := mappingValue(op.node, "summary")
+		if summary == nil || summary.Value == "" {
+			continue
+		}
+		if description := mappingValue(op.node, "description"); description != nil && description.Value != "" {
+			continue
+		}
+
+		setMappingValueAfter(op.node, "description", suggest.StringNode(summary.Value), "summary")
+		fixes = append(fixes, Fix{
+			Rule:    RuleMissingDescription,
+			Message: fmt.Sprintf("added description to %s from its summary", op),
+			Line:    op.key.Line,
+		})
+	}
+
+	return fixes
+}
+
+// fixUnusedComponents removes components that aren't referenced, directly or
+// through other components, from outside the components section.
+func fixUnusedComponents(root *yaml.Node) []Fix {
+	components := mappingValue(root, "components")
+	if components == nil || components.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	used := map[string]bool{}
+	var queue []string
+	reference := func(ref string) {
+		if !used[ref] {
+			used[ref] = true
+			queue = append(queue, ref)
+		}
+	}
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "components" {
+			collectComponentRefs(root.Content[i+1], reference)
+		}
+	}
+	for i := 0; i+1 < len(components.Content); i += 2 {
+		if !slices.Contains(componentTypes, components.Content[i].Value) {
+			collectComponentRefs(components.Content[i+1], reference)
+		}
+	}
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+		componentType, name, _ := strings.Cut(ref, "/")
+		if component := mappingValue(mappingValue(components, componentType), name); component != nil {
+			collectComponentRefs(component, reference)
+		}
+	}
+
+	var fixes []Fix
+	for _, componentType := range componentTypes {
+		entries := mappingValue(components, componentType)
+		if entries == nil || entries.Kind != yaml.MappingNode {
+			continue
+		}
+
+		var kept []*yaml.Node
+		for i := 0; i+1 < len(entries.Content); i += 2 {
+			ref := componentType + "/" + entries.Content[i].Value
+			if used[ref] {
+				kept = append(kept, entries.Content[i], entries.Content[i+1])
+				continue
+			}
+			fixes = append(fixes, Fix{
+				Rule:    RuleUnusedComponent,
+				Message: fmt.Sprintf("removed unused component %s", ref),
+				Line:    entries.Content[i].Line,
+			})
+		}
+		entries.Content = kept
+
+		if len(kept) == 0 {
+			deleteMappingKey(components, componentType)
+		}
+	}
+	if len(components.Content) == 0 {
+		deleteMappingKey(root, "components")
+	}
+
+	return fixes
+}
+
+// collectComponentRefs calls reference with the type and name, as in
+// schemas/Pet, of each local component referenced within node, including
+// those in discriminator mappings.
+func collectComponentRefs(node *yaml.Node, reference func(string)) {
+	if node == nil {
+		return
+	}
+
+	if node.Kind == yaml.ScalarNode {
+		ref, ok := strings.CutPrefix(node.Value, "#/components/")
+		if !ok {
+			return
+		}
+		parts := strings.SplitN(ref, "/", 3)
+		if len(parts) < 2 {
+			return
+		}
+		name := strings.NewReplacer("~1", "/", "~0", "~").Replace(parts[1])
+		reference(parts[0] + "/" + name)
+		return
+	}
+
+	for _, child := range node.Content {
+		collectComponentRefs(child, reference)
+	}
+}
+
+// casingOf returns the casing of an identifier, or "" for single lowercase
+// words that fit several.
+func casingOf(id string) string {
+	switch {
+	case strings.Contains(id, "_"):
+		return "snake"
+	case strings.Contains(id, "-"):
+		return "kebab"
+	case id[0] >= 'A' && id[0] <= 'Z':
+		return "pascal"
+	case strings.ToLower(id) != id:
+		return "camel"
+	}
+	return ""
+}
+
+// dominantCasing returns the casing most operationIds use, preferring camel
+// case on ties.
+func dominantCasing(operations []fixOperation) string {
+	counts := map[string]int{}
+	for _, op := range operations {
+		if id := op.operationID(); id != "" {
+			counts[casingOf(id)]++
+		}
+	}
+
+	dominant := "camel"
+	for _, casing := range []string{"pascal", "snake", "kebab"} {
+		if counts[casing] > counts[dominant] {
+			dominant = casing
+		}
+	}
+	return dominant
+}
+
+// renameLinkedOperationID renames the operationId of links anywhere in the
+// document, in responses or components, that refer to an operation by it.
+func renameLinkedOperationID(node *yaml.Node, from, to string) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			links := node.Content[i+1]
+			if node.Content[i].Value != "links" || links.Kind != yaml.MappingNode {
+				continue
+			}
+			for j := 1; j < len(links.Content); j += 2 {
+				if id := mappingValue(links.Content[j], "operationId"); id != nil && id.Value == from {
+					id.Value = to
+				}
+			}
+		}
+	}
+	for _, child := range node.Content {
+		renameLinkedOperationID(child, from, to)
+	}
+}
+
+func formatWords(words []string, casing string) string {
+	switch casing {
+	case "snake":
+		return strings.Join(words, "_")
+	case "kebab":
+		return strings.Join(words, "-")
+	}
+
+	var sb strings.Builder
+	for i, word := range words {
+		if i == 0 && casing == "camel" {
+			sb.WriteString(word)
+			continue
+		}
+		sb.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return sb.String()
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingValueAfter sets key in a mapping, adding it after the key after
+// if it isn't set yet.
+func setMappingValueAfter(node *yaml.Node, key string, value *yaml.Node, after string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content[i+1] = value
+			return
+		}
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == after {
+			node.Content = slices.Insert(node.Content, i+2, suggest.StringNode(key), value)
+			return
+		}
+	}
+	node.Content = append(node.Content, suggest.StringNode(key), value)
+}
+
+func deleteMappingKey(node *yaml.Node, key string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = slices.Delete(node.Content, i, i+2)
+			return
+		}
+	}
+}
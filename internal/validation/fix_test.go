@@ -0,0 +1,71 @@
+package validation
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestFixOpenAPI(t *testing.T) {
+	t.Parallel()
+
+	schema, err := os.ReadFile("testdata/fix/openapi.yaml")
+	require.NoError(t, err)
+
+	fixed, o, fixes, err := FixOpenAPI(schema)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Fix{
+		{RuleOperationIDCasing, "renamed operationId create_pet of POST /pets to createPet to match the camel case of other operations", 22},
+		{RuleDuplicateOperationID, "renamed duplicate operationId listPets of GET /pets/{petId} to petsGet", 33},
+		{RuleMissingOperationID, "added operationId petsDelete to DELETE /pets/{petId}", 43},
+		{RuleMissingDescription, "added description to GET /pets from its summary", 7},
+		{RuleMissingDescription, "added description to GET /pets/{petId} from its summary", 33},
+		{RuleUnusedComponent, "removed unused component schemas/Legacy", 65},
+		{RuleUnusedComponent, "removed unused component parameters/Limit", 77},
+	}, fixes)
+
+	expected, err := os.ReadFile("testdata/fix/fixed.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), render(t, fixed))
+
+	// The overlay makes the same changes
+	var original yaml.Node
+	require.NoError(t, yaml.Unmarshal(schema, &original))
+	require.NoError(t, o.ApplyTo(&original))
+	var overlaid yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(render(t, &original)), &overlaid))
+	var want yaml.Node
+	require.NoError(t, yaml.Unmarshal(expected, &want))
+	var overlaidValue, wantValue any
+	require.NoError(t, overlaid.Decode(&overlaidValue))
+	require.NoError(t, want.Decode(&wantValue))
+	assert.Equal(t, wantValue, overlaidValue)
+}
+
+func TestFixOpenAPINothingToFix(t *testing.T) {
+	t.Parallel()
+
+	expected, err := os.ReadFile("testdata/fix/fixed.yaml")
+	require.NoError(t, err)
+
+	fixed, o, fixes, err := FixOpenAPI(expected)
+	require.NoError(t, err)
+	assert.Empty(t, fixes)
+	assert.Empty(t, o.Actions)
+	assert.Equal(t, string(expected), render(t, fixed))
+}
+
+func render(t *testing.T, node *yaml.Node) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	require.NoError(t, encoder.Encode(node))
+	return buf.String()
+}
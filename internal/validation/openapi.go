@@ -45,6 +45,9 @@ type ValidationResult struct {
 	ValidOperations   []string
 	InvalidOperations []string
 	Report            *reports.ReportResult
+	// Baselined is the number of findings left out as they are in the
+	// baseline.
+	Baselined int
 }
 
 var validSpeakeasyRulesets = []string{"speakeasy-recommended", "speakeasy-generation", "speakeasy-openapi", "vacuum", "owasp"}
@@ -60,7 +63,7 @@ func ValidateWithInteractivity(ctx context.Context, schemaPath, header, token st
 		return nil, fmt.Errorf("failed to get document contents: %w", err)
 	}
 
-	res, err := Validate(ctx, logger, schema, schemaPath, limits, isRemote, defaultRuleset, workingDir, false, skipGenerateReport, "")
+	res, err := Validate(ctx, logger, schema, schemaPath, limits, isRemote, defaultRuleset, workingDir, false, skipGenerateReport, "", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -103,7 +106,7 @@ func ValidateWithInteractivity(ctx context.Context, schemaPath, header, token st
 	return res, nil
 }
 
-func ValidateOpenAPI(ctx context.Context, source, schemaPath, header, token string, limits *OutputLimits, defaultRuleset, workingDir string, isQuickstart bool, skipGenerateReport bool, target string) (*ValidationResult, error) {
+func ValidateOpenAPI(ctx context.Context, source, schemaPath, header, token string, limits *OutputLimits, defaultRuleset, workingDir string, isQuickstart bool, skipGenerateReport bool, target string, baseline *DocumentBaseline) (*ValidationResult, error) {
 	logger := log.From(ctx)
 	logger.Info("Linting OpenAPI document...\n")
 
@@ -114,7 +117,7 @@ func ValidateOpenAPI(ctx context.Context, source, schemaPath, header, token stri
 
 	prefixedLogger := logger.WithAssociatedFile(schemaPath).WithFormatter(log.PrefixedFormatter)
 
-	res, err := Validate(ctx, logger, schema, schemaPath, limits, isRemote, defaultRuleset, workingDir, isQuickstart, skipGenerateReport, target)
+	res, err := Validate(ctx, logger, schema, schemaPath, limits, isRemote, defaultRuleset, workingDir, isQuickstart, skipGenerateReport, target, baseline)
 	if err != nil {
 		return nil, err
 	}
@@ -130,6 +133,9 @@ func ValidateOpenAPI(ctx context.Context, source, schemaPath, header, token stri
 	}
 
 	logger.Infof("\nOpenAPI document linting complete. %d errors, %d warnings, %d hints\n", len(res.Errors), len(res.Warnings), len(res.Infos))
+	if res.Baselined > 0 {
+		logger.Infof("%d known findings in the baseline were left out\n", res.Baselined)
+	}
 
 	reportURL := ""
 	if res.Report != nil {
@@ -255,7 +261,8 @@ func getDetailedView(lines []string, err errors.ValidationError) string {
 }
 
 // Validate returns (validation errors, validation warnings, validation info, error)
-func Validate(ctx context.Context, outputLogger log.Logger, schema []byte, schemaPath string, limits *OutputLimits, isRemote bool, defaultRuleset, workingDir string, parseValidOperations bool, skipGenerateReport bool, target string) (*ValidationResult, error) {
+// Findings in baseline, if set, are left out.
+func Validate(ctx context.Context, outputLogger log.Logger, schema []byte, schemaPath string, limits *OutputLimits, isRemote bool, defaultRuleset, workingDir string, parseValidOperations bool, skipGenerateReport bool, target string, baseline *DocumentBaseline) (*ValidationResult, error) {
 	l := log.From(ctx).WithFormatter(log.PrefixedFormatter)
 
 	opts := []generate.GeneratorOptions{
@@ -284,6 +291,11 @@ func Validate(ctx context.Context, outputLogger log.Logger, schema []byte, schem
 		opts = append(opts, generate.WithValidationRuleset(defaultRuleset))
 	}
 
+	customRules, err := loadCustomRules(workingDir)
+	if err != nil {
+		return nil, err
+	}
+
 	g, err := generate.New(opts...)
 	if err != nil {
 		return nil, err
@@ -303,6 +315,30 @@ func Validate(ctx context.Context, outputLogger log.Logger, schema []byte, schem
 	var vErrs, vWarns, vInfo []error
 
 	errs := res.GetValidationErrors()
+
+	violations, err := applyCustomRules(schema, customRules)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range violations {
+		severity := errors.SeverityWarn
+		switch v.severity {
+		case "error":
+			severity = errors.SeverityError
+		case "hint":
+			severity = errors.SeverityHint
+		}
+		errs = append(errs, &errors.ValidationError{
+			Severity: severity,
+			Rule:     v.rule,
+			Message:  v.message,
+			Node:     v.node,
+			Cause:    v.err,
+		})
+	}
+
+	errs, baselined := baseline.Filter(schema, errs)
+
 	for _, err := range errs {
 		vErr := errors.GetValidationErr(err)
 		uErr := errors.GetUnsupportedErr(err)
@@ -374,6 +410,7 @@ func Validate(ctx context.Context, outputLogger log.Logger, schema []byte, schem
 		ValidOperations:   res.GetValidOperations(),
 		InvalidOperations: res.GetInvalidOperations(),
 		Report:            report,
+		Baselined:         baselined,
 	}, nil
 }
 
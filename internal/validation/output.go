@@ -0,0 +1,396 @@
+package validation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/speakeasy-api/openapi-generation/v2/pkg/errors"
+)
+
+type OutputFormat string
+
+const (
+	OutputFormatSARIF      OutputFormat = "sarif"
+	OutputFormatJUnit      OutputFormat = "junit"
+	OutputFormatJSON       OutputFormat = "json"
+	OutputFormatCheckstyle OutputFormat = "checkstyle"
+)
+
+var OutputFormats = []string{string(OutputFormatSARIF), string(OutputFormatJUnit), string(OutputFormatJSON), string(OutputFormatCheckstyle)}
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityHint    = "hint"
+)
+
+// Finding is a lint finding of a document, as written to reports.
+type Finding struct {
+	File     string
+	Line     int
+	Column   int
+	Rule     string
+	Severity string
+	Message  string
+}
+
+// FindingsOf returns the findings of the document at file in a validation
+// result.
+func FindingsOf(file string, res *ValidationResult) []Finding {
+	bySeverity := []struct {
+		severity string
+		errs     []error
+	}{
+		{SeverityError, res.Errors},
+		{SeverityWarning, res.Warnings},
+		{SeverityHint, res.Infos},
+	}
+
+	var findings []Finding
+	for _, group := range bySeverity {
+		for _, err := range group.errs {
+			finding := Finding{File: file, Severity: group.severity, Message: err.Error()}
+			if vErr := errors.GetValidationErr(err); vErr != nil {
+				finding.Rule, finding.Message = vErr.Rule, vErr.Message
+				finding.Line, finding.Column = vErr.GetLineNumber(), vErr.GetColumnNumber()
+			} else if uErr := errors.GetUnsupportedErr(err); uErr != nil {
+				finding.Rule = "unsupported"
+				finding.Line, finding.Column = uErr.GetLineNumber(), uErr.GetColumnNumber()
+			}
+			findings = append(findings, finding)
+		}
+	}
+
+	slices.SortStableFunc(findings, func(a, b Finding) int {
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		return a.Column - b.Column
+	})
+	return findings
+}
+
+// WriteFindings writes the findings of the linted files in a report format.
+// Files without findings are included where the format has a notion of
+// passing files.
+func WriteFindings(w io.Writer, format OutputFormat, files []string, findings []Finding) error {
+	switch format {
+	case OutputFormatSARIF:
+		return writeSARIF(w, findings)
+	case OutputFormatJUnit:
+		return writeJUnit(w, files, findings)
+	case OutputFormatJSON:
+		return writeCodeQuality(w, findings)
+	case OutputFormatCheckstyle:
+		return writeCheckstyle(w, files, findings)
+	default:
+		return fmt.Errorf("unsupported output format %s", format)
+	}
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId,omitempty"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// writeSARIF writes findings as SARIF 2.1.0, as read by GitHub code scanning.
+func writeSARIF(w io.Writer, findings []Finding) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "speakeasy",
+			InformationURI: "https://www.speakeasy.com/docs/linting",
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+
+	for _, finding := range findings {
+		if finding.Rule != "" && !slices.Contains(run.Tool.Driver.Rules, sarifRule{ID: finding.Rule}) {
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: finding.Rule})
+		}
+
+		level := "note"
+		switch finding.Severity {
+		case SeverityError:
+			level = "error"
+		case SeverityWarning:
+			level = "warning"
+		}
+
+		location := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: finding.File}}
+		if finding.Line > 0 {
+			location.Region = &sarifRegion{StartLine: finding.Line, StartColumn: max(finding.Column, 0)}
+		}
+
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    finding.Rule,
+			Level:     level,
+			Message:   sarifMessage{Text: finding.Message},
+			Locations: []sarifLocation{{PhysicalLocation: location}},
+		})
+	}
+
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
+
+type codeQualityIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    codeQualityLocation `json:"location"`
+}
+
+type codeQualityLocation struct {
+	Path      string               `json:"path"`
+	Positions codeQualityPositions `json:"positions"`
+}
+
+type codeQualityPositions struct {
+	Begin codeQualityPosition `json:"begin"`
+}
+
+type codeQualityPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// writeCodeQuality writes findings as a Code Climate report, as read by
+// GitLab code quality.
+func writeCodeQuality(w io.Writer, findings []Finding) error {
+	issues := []codeQualityIssue{}
+	for _, finding := range findings {
+		severity := "info"
+		switch finding.Severity {
+		case SeverityError:
+			severity = "major"
+		case SeverityWarning:
+			severity = "minor"
+		}
+
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%d\x00%d\x00%s", finding.File, finding.Rule, finding.Line, finding.Column, finding.Message)))
+		issues = append(issues, codeQualityIssue{
+			Description: finding.Message,
+			CheckName:   ruleOrDefault(finding.Rule),
+			Fingerprint: hex.EncodeToString(sum[:]),
+			Severity:    severity,
+			Location: codeQualityLocation{
+				Path:      finding.File,
+				Positions: codeQualityPositions{Begin: codeQualityPosition{Line: max(finding.Line, 1), Column: max(finding.Column, 1)}},
+			},
+		})
+	}
+
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(issues)
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// writeJUnit writes findings as JUnit XML, with a test suite per file and a
+// test case per finding. Errors fail, warnings and hints are skipped so they
+// show without failing the build, and files without findings get a passing
+// test case.
+func writeJUnit(w io.Writer, files []string, findings []Finding) error {
+	suites := junitTestSuites{Name: "speakeasy lint"}
+
+	for _, file := range files {
+		suite := junitTestSuite{Name: file}
+
+		for _, finding := range findings {
+			if finding.File != file {
+				continue
+			}
+
+			testCase := junitTestCase{
+				Name:      fmt.Sprintf("%s at line %d:%d", ruleOrDefault(finding.Rule), finding.Line, finding.Column),
+				ClassName: file,
+			}
+			if finding.Severity == SeverityError {
+				testCase.Failure = &junitFailure{Message: finding.Message, Type: finding.Rule, Text: finding.Message}
+				suite.Failures++
+			} else {
+				testCase.Skipped = &junitSkipped{Message: fmt.Sprintf("%s: %s", finding.Severity, finding.Message)}
+				suite.Skipped++
+			}
+			suite.Cases = append(suite.Cases, testCase)
+		}
+
+		if len(suite.Cases) == 0 {
+			suite.Cases = append(suite.Cases, junitTestCase{Name: "lint", ClassName: file})
+		}
+
+		suite.Tests = len(suite.Cases)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Skipped += suite.Skipped
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	return writeXML(w, suites)
+}
+
+type checkstyle struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// writeCheckstyle writes findings as Checkstyle XML, as read by the Jenkins
+// warnings plugin.
+func writeCheckstyle(w io.Writer, files []string, findings []Finding) error {
+	report := checkstyle{Version: "4.3"}
+
+	for _, file := range files {
+		f := checkstyleFile{Name: file}
+		for _, finding := range findings {
+			if finding.File != file {
+				continue
+			}
+
+			severity := "info"
+			switch finding.Severity {
+			case SeverityError:
+				severity = "error"
+			case SeverityWarning:
+				severity = "warning"
+			}
+			f.Errors = append(f.Errors, checkstyleError{
+				Line:     max(finding.Line, 0),
+				Column:   max(finding.Column, 0),
+				Severity: severity,
+				Message:  finding.Message,
+				Source:   ruleOrDefault(finding.Rule),
+			})
+		}
+		report.Files = append(report.Files, f)
+	}
+
+	return writeXML(w, report)
+}
+
+func writeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	if err := e.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func ruleOrDefault(rule string) string {
+	if rule == "" {
+		return "speakeasy"
+	}
+	return rule
+}
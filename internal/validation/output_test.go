@@ -0,0 +1,91 @@
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/speakeasy-api/openapi-generation/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func testFindings() []Finding {
+	return FindingsOf("openapi.yaml", &ValidationResult{
+		Errors: []error{
+			&errors.ValidationError{Severity: errors.SeverityError, Rule: "operation-operationId", Message: "operation is missing an operationId", Node: &yaml.Node{Line: 12, Column: 5}},
+		},
+		Warnings: []error{
+			&errors.ValidationError{Severity: errors.SeverityWarn, Rule: "operation-description", Message: "operation is missing a description", Node: &yaml.Node{Line: 7, Column: 5}},
+			fmt.Errorf("and 3 more warnings"),
+		},
+		Infos: []error{
+			&errors.ValidationError{Severity: errors.SeverityHint, Rule: "operation-tags", Message: "operation has no tags", Node: &yaml.Node{Line: 12, Column: 5}},
+		},
+	})
+}
+
+func TestFindingsOf(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []Finding{
+		{File: "openapi.yaml", Severity: SeverityWarning, Message: "and 3 more warnings"},
+		{File: "openapi.yaml", Line: 7, Column: 5, Rule: "operation-description", Severity: SeverityWarning, Message: "operation is missing a description"},
+		{File: "openapi.yaml", Line: 12, Column: 5, Rule: "operation-operationId", Severity: SeverityError, Message: "operation is missing an operationId"},
+		{File: "openapi.yaml", Line: 12, Column: 5, Rule: "operation-tags", Severity: SeverityHint, Message: "operation has no tags"},
+	}, testFindings())
+}
+
+func TestWriteFindings(t *testing.T) {
+	t.Parallel()
+
+	files := []string{"openapi.yaml", "clean.yaml"}
+
+	var sarif bytes.Buffer
+	require.NoError(t, WriteFindings(&sarif, OutputFormatSARIF, files, testFindings()))
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(sarif.Bytes(), &decoded))
+	assert.Equal(t, "2.1.0", decoded["version"])
+	assert.Contains(t, sarif.String(), `"ruleId": "operation-operationId",
+          "level": "error",
+          "message": {
+            "text": "operation is missing an operationId"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "openapi.yaml"
+                },
+                "region": {
+                  "startLine": 12,
+                  "startColumn": 5
+                }`)
+
+	var junit bytes.Buffer
+	require.NoError(t, WriteFindings(&junit, OutputFormatJUnit, files, testFindings()))
+	assert.Contains(t, junit.String(), `<testsuites name="speakeasy lint" tests="5" failures="1" skipped="3">`)
+	assert.Contains(t, junit.String(), `<testcase name="operation-operationId at line 12:5" classname="openapi.yaml">
+      <failure message="operation is missing an operationId" type="operation-operationId">operation is missing an operationId</failure>`)
+	assert.Contains(t, junit.String(), `<skipped message="hint: operation has no tags"></skipped>`)
+	assert.Contains(t, junit.String(), `<testsuite name="clean.yaml" tests="1" failures="0" skipped="0">
+    <testcase name="lint" classname="clean.yaml"></testcase>`)
+
+	var codeQuality bytes.Buffer
+	require.NoError(t, WriteFindings(&codeQuality, OutputFormatJSON, files, testFindings()))
+	var issues []map[string]any
+	require.NoError(t, json.Unmarshal(codeQuality.Bytes(), &issues))
+	require.Len(t, issues, 4)
+	assert.Equal(t, "speakeasy", issues[0]["check_name"])
+	assert.Equal(t, "major", issues[2]["severity"])
+	assert.Equal(t, map[string]any{"path": "openapi.yaml", "positions": map[string]any{"begin": map[string]any{"line": 12.0, "column": 5.0}}}, issues[2]["location"])
+
+	var checkstyle bytes.Buffer
+	require.NoError(t, WriteFindings(&checkstyle, OutputFormatCheckstyle, files, testFindings()))
+	assert.Contains(t, checkstyle.String(), `<error line="7" column="5" severity="warning" message="operation is missing a description" source="operation-description"></error>`)
+	assert.Contains(t, checkstyle.String(), `<file name="clean.yaml"></file>`)
+
+	assert.ErrorContains(t, WriteFindings(&bytes.Buffer{}, "html", files, nil), "unsupported output format html")
+}
@@ -11,6 +11,7 @@ import (
 	core "github.com/speakeasy-api/speakeasy-core/auth"
 	"github.com/speakeasy-api/speakeasy/internal/download"
 	"github.com/speakeasy-api/speakeasy/internal/log"
+	"github.com/speakeasy-api/speakeasy/internal/offline"
 )
 
 func ResolveSpeakeasyRegistryBundle(ctx context.Context, d workflow.Document, outPath string) (*download.DownloadedRegistryOpenAPIBundle, error) {
@@ -20,17 +21,22 @@ func ResolveSpeakeasyRegistryBundle(ctx context.Context, d workflow.Document, ou
 		return nil, err
 	}
 
+	registryBreakdown := workflow.ParseSpeakeasyRegistryReference(d.Location.Resolve())
+	if registryBreakdown == nil {
+		return nil, fmt.Errorf("failed to parse speakeasy registry reference %s", d.Location)
+	}
+
+	// Offline, the bundle comes from the mirror, which doesn't need authenticating.
+	if offline.Enabled() {
+		return download.DownloadRegistryOpenAPIBundle(ctx, *registryBreakdown, outPath)
+	}
+
 	workspaceSlug := core.GetWorkspaceSlugFromContext(ctx)
 	organizationSlug := core.GetOrgSlugFromContext(ctx)
 	if workspaceSlug == "" || organizationSlug == "" {
 		return nil, fmt.Errorf("unable to use speakeasy registry reference without authenticating")
 	}
 
-	registryBreakdown := workflow.ParseSpeakeasyRegistryReference(d.Location.Resolve())
-	if registryBreakdown == nil {
-		return nil, fmt.Errorf("failed to parse speakeasy registry reference %s", d.Location)
-	}
-
 	keyForWorkspace := config.GetWorkspaceAPIKey(organizationSlug, workspaceSlug)
 	if keyForWorkspace == "" && organizationSlug != "speakeasy-self" {
 		if registryBreakdown.OrganizationSlug != organizationSlug {
@@ -46,6 +52,11 @@ func ResolveSpeakeasyRegistryBundle(ctx context.Context, d workflow.Document, ou
 }
 
 func IsRegistryEnabled(ctx context.Context) bool {
+	// Publishing to the registry and tracking changes need network access.
+	if offline.Enabled() {
+		return false
+	}
+
 	hasSkipSchemaRegistry, _ := core.HasWorkspaceFeatureFlag(ctx, "skip_schema_registry")
 	telemetryDisabled := core.IsTelemetryDisabled(ctx)
 	return !hasSkipSchemaRegistry && !telemetryDisabled